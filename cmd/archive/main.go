@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"sora_landing_be/cmd/repository"
+	"sora_landing_be/cmd/services"
+	"sora_landing_be/pkg/config"
+	"sora_landing_be/pkg/database"
+	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/storage"
+)
+
+func main() {
+	// Parse command line flags
+	exportPath := flag.String("export", "", "Write a content archive to the given zip file")
+	importPath := flag.String("import", "", "Restore a content archive from the given zip file into an empty database")
+	flag.Parse()
+
+	if (*exportPath == "") == (*importPath == "") {
+		log.Fatal("Specify exactly one of -export or -import")
+	}
+
+	// Initialize configuration and database
+	cfg := config.LoadConfig()
+	logger.NewZapLogger(cfg.Logger)
+	database.InitDB(cfg.Database)
+	storage.InitDriver(cfg)
+	repository.Init(database.GetDB())
+	services.Init()
+	ctx := context.Background()
+
+	archiveSrv := services.ServicePool.ArchiveService
+
+	if *exportPath != "" {
+		f, err := os.Create(*exportPath)
+		if err != nil {
+			log.Fatalf("Error creating archive: %v", err)
+		}
+		defer f.Close()
+
+		if err := archiveSrv.Export(ctx, f); err != nil {
+			log.Fatalf("Error exporting archive: %v", err)
+		}
+		log.Printf("Archive written to %s", *exportPath)
+		return
+	}
+
+	f, err := os.Open(*importPath)
+	if err != nil {
+		log.Fatalf("Error opening archive: %v", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		log.Fatalf("Error reading archive: %v", err)
+	}
+
+	res, err := archiveSrv.Import(ctx, f, info.Size())
+	if err != nil {
+		log.Fatalf("Error importing archive: %v", err)
+	}
+	log.Printf("Imported %d articles, %d categories, %d tags, %d authors and %d files",
+		res.Articles, res.Categories, res.Tags, res.Authors, res.Files)
+}
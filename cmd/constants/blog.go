@@ -1,5 +1,7 @@
 package constants
 
+import "time"
+
 type ArticleStatus string
 
 const (
@@ -8,3 +10,32 @@ const (
 	StatusScheduled ArticleStatus = "scheduled"
 	StatusArchived  ArticleStatus = "archived"
 )
+
+// ArticleExpiryTick is how often expired articles are archived, public queries hide
+// them from the moment they expire so this only bounds how long the status lags
+const ArticleExpiryTick = time.Minute
+
+// ExcerptDefaultLength is used when application.excerpt_length is not configured
+const ExcerptDefaultLength = 200
+
+// CursorMaxPageSize caps the page size of keyset paginated public listings
+const CursorMaxPageSize = 100
+
+const (
+	// TagCloudLevels is the number of distinct weights in the public tag cloud
+	TagCloudLevels = 5
+	// TagCloudDefaultLimit caps the tag cloud when the client does not ask for a size
+	TagCloudDefaultLimit = 50
+)
+
+const (
+	// TagCorpusTTL is how long the TF-IDF document frequencies of published articles are reused
+	TagCorpusTTL = 30 * time.Minute
+	// TagTitleWeight counts every title word this many times, titles say more than body text
+	TagTitleWeight = 3
+	// TagSuggestionDefaultLimit caps suggested tags and keywords when the client does not ask for a size
+	TagSuggestionDefaultLimit = 10
+	// AutoTagMaxTags and AutoTagMinScore bound the tags attached to imported articles
+	AutoTagMaxTags  = 3
+	AutoTagMinScore = 0.01
+)
@@ -0,0 +1,69 @@
+package constants
+
+import "time"
+
+// FileUsage is how content shows an uploaded file
+type FileUsage string
+
+const (
+	FileUsageCover   FileUsage = "cover"
+	FileUsageInline  FileUsage = "inline"
+	FileUsageImage   FileUsage = "image" // category and tag images
+	FileUsageOgImage FileUsage = "og_image"
+)
+
+const (
+	// FileModuleArticle marks uploads stored while importing an external article
+	FileModuleArticle = "article"
+)
+
+// MediaType groups content types for filtering the media library
+type MediaType string
+
+const (
+	MediaTypeImage    MediaType = "image"
+	MediaTypeVideo    MediaType = "video"
+	MediaTypeAudio    MediaType = "audio"
+	MediaTypeDocument MediaType = "document"
+)
+
+// MediaTypePrefixes maps a media type to the content type prefixes it covers
+var MediaTypePrefixes = map[MediaType][]string{
+	MediaTypeImage:    {"image/"},
+	MediaTypeVideo:    {"video/"},
+	MediaTypeAudio:    {"audio/"},
+	MediaTypeDocument: {"application/", "text/"},
+}
+
+const (
+	// ImageFormatWebP is the format every image variant is also stored in
+	ImageFormatWebP = "webp"
+	// ImageVariantOriginal names the image at its full size
+	ImageVariantOriginal = "original"
+	// WebPDefaultQuality is used when image.webp_quality is not configured
+	WebPDefaultQuality = 80
+)
+
+// PresignDefaultExpiry is used when object_storage.presign_expiration is not configured
+const PresignDefaultExpiry = time.Hour
+
+// TemporaryUploadDefaultTTL is used when storage.temporary_ttl is not configured
+const TemporaryUploadDefaultTTL = 24 * time.Hour
+
+const (
+	// TemporaryUploadSweepBatch is the most abandoned uploads removed in one sweep
+	TemporaryUploadSweepBatch = 100
+	// TemporaryUploadSweepTick is how often the upload sweep job runs
+	TemporaryUploadSweepTick = time.Hour
+)
+
+const (
+	// DirectUploadExpiry is how long a presigned upload form is accepted
+	DirectUploadExpiry = 15 * time.Minute
+	// metadata the upload form stores with the object, read back when the upload completes
+	DirectUploadMetaUploadedBy  = "uploaded-by"
+	DirectUploadMetaFileName    = "file-name"
+	DirectUploadMetaModule      = "module"
+	DirectUploadMetaReferenceID = "reference-id"
+	DirectUploadMetaPublic      = "public"
+)
@@ -0,0 +1,33 @@
+package constants
+
+import "time"
+
+type ImportStatus string
+
+const (
+	ImportStatusPending    ImportStatus = "pending"
+	ImportStatusProcessing ImportStatus = "processing"
+	ImportStatusDone       ImportStatus = "done"
+
+	// Per-URL outcomes
+	ImportStatusImported  ImportStatus = "imported"
+	ImportStatusDuplicate ImportStatus = "duplicate"
+	ImportStatusFailed    ImportStatus = "failed"
+)
+
+const (
+	ExternalCategoryName = "external"
+	// MaxImportBatchSize caps how many URLs a single batch import may contain
+	MaxImportBatchSize = 50
+)
+
+const (
+	// FeedPollInterval is how often a subscription is checked for new entries
+	FeedPollInterval = 30 * time.Minute
+	// FeedPollTick is how often the feed poller looks for subscriptions that are due
+	FeedPollTick = 5 * time.Minute
+	// FeedMaxEntriesPerPoll caps how many new entries are imported from a feed in one poll
+	FeedMaxEntriesPerPoll = 20
+	// FeedMaxAttempts is how many times a failing entry is retried before it is skipped for good
+	FeedMaxAttempts = 3
+)
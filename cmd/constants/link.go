@@ -0,0 +1,34 @@
+package constants
+
+import "time"
+
+type LinkKind string
+
+const (
+	LinkKindLink  LinkKind = "link"
+	LinkKindImage LinkKind = "image"
+)
+
+type LinkStatus string
+
+const (
+	LinkStatusOK LinkStatus = "ok"
+	// LinkStatusRedirected links still work but point at an old address, e.g. a renamed tag
+	LinkStatusRedirected LinkStatus = "redirected"
+	LinkStatusBroken     LinkStatus = "broken"
+	// LinkStatusUnreachable covers timeouts, DNS failures and destinations the fetcher refuses
+	LinkStatusUnreachable LinkStatus = "unreachable"
+)
+
+const (
+	// LinkCheckInterval is how often the links of an unchanged article are checked again
+	LinkCheckInterval = 7 * 24 * time.Hour
+	// LinkCheckTimeout bounds a single outbound link check
+	LinkCheckTimeout = 10 * time.Second
+	// LinkCheckConcurrency caps the outbound requests running at the same time
+	LinkCheckConcurrency = 8
+	// LinkAuditBatchSize is how many articles one run of the link audit job checks
+	LinkAuditBatchSize = 20
+	// LinkAuditTick is how often the link audit job looks for a batch of due articles
+	LinkAuditTick = 10 * time.Minute
+)
@@ -0,0 +1,15 @@
+package constants
+
+const (
+	// MetaTitleMaxLength and MetaDescriptionMaxLength are the lengths search engines display before truncating
+	MetaTitleMaxLength       = 60
+	MetaDescriptionMaxLength = 160
+)
+
+// Paths of the public pages on the frontend, relative to Application.FrontendURL
+const (
+	ArticlePathFormat  = "/blog/%s"
+	CategoryPathFormat = "/blog/category/%s"
+	TagPathFormat      = "/blog/tag/%s"
+	BlogPath           = "/blog"
+)
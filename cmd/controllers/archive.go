@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"sora_landing_be/cmd/services"
+	"sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/http/server/http_response"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ArchiveController struct {
+	ArchiveService services.ArchiveService
+}
+
+func NewArchiveController(archiveService services.ArchiveService) ArchiveController {
+	return ArchiveController{
+		ArchiveService: archiveService,
+	}
+}
+
+// Export streams the whole content archive as a zip file
+func (ctl *ArchiveController) Export(ctx *gin.Context) {
+	filename := fmt.Sprintf("sora-archive-%s.zip", time.Now().Format("20060102-150405"))
+	ctx.Header("Content-Disposition", "attachment; filename="+filename)
+	ctx.Header("Content-Type", "application/zip")
+	ctx.Header("Content-Transfer-Encoding", "binary")
+
+	if err := ctl.ArchiveService.Export(ctx, ctx.Writer); err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+}
+
+// Import restores an archive produced by Export into an empty database
+func (ctl *ArchiveController) Import(ctx *gin.Context) {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		http_response.SendError(ctx, errors.StorageErrorToAppError("Failed to read uploaded archive"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		http_response.SendError(ctx, errors.StorageErrorToAppError("Failed to open uploaded archive"))
+		return
+	}
+	defer file.Close()
+
+	res, err := ctl.ArchiveService.Import(ctx, file, fileHeader.Size)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusCreated, "Archive imported successfully", res)
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"sora_landing_be/cmd/constants"
 	"sora_landing_be/cmd/dto/requests"
 	"sora_landing_be/cmd/services"
 	"sora_landing_be/pkg/authentication"
@@ -47,13 +48,82 @@ func (ctl *BlogController) CreateArticleFromURL(ctx *gin.Context) {
 	}
 
 	userID := authentication.GetUserDataFromToken(ctx).UserID
-	err := ctl.BlogService.CreateArticleFromURL(ctx, userID, payload)
+	res, err := ctl.BlogService.CreateArticleFromURL(ctx, userID, payload)
 	if err != nil {
 		http_response.SendError(ctx, err)
 		return
 	}
 
-	http_response.SendSuccess(ctx, http.StatusCreated, "Article created successfully", nil)
+	if res.Status == constants.ImportStatusDuplicate {
+		http_response.SendSuccess(ctx, http.StatusOK, "Article from this URL was already imported", res)
+		return
+	}
+	http_response.SendSuccess(ctx, http.StatusCreated, "Article created successfully", res)
+}
+
+func (ctl *BlogController) CreateArticlesFromURLs(ctx *gin.Context) {
+	var payload requests.BatchFromURL
+	if err := internalHTTP.BindData(ctx, &payload); err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	userID := authentication.GetUserDataFromToken(ctx).UserID
+	res, err := ctl.BlogService.CreateArticlesFromURLs(ctx, userID, payload)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusAccepted, "Import queued successfully", res)
+}
+
+func (ctl *BlogController) GetImportJob(ctx *gin.Context) {
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.BlogService.GetImportJob(ctx, id)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Import job retrieved successfully", res)
+}
+
+func (ctl *BlogController) SuggestTags(ctx *gin.Context) {
+	var payload requests.SuggestTags
+	if err := internalHTTP.BindData(ctx, &payload); err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.BlogService.SuggestTags(ctx, payload)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Tag suggestions retrieved successfully", res)
+}
+
+func (ctl *BlogController) ResyncExternalArticle(ctx *gin.Context) {
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.BlogService.ResyncExternalArticle(ctx, id)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Article synced successfully", res)
 }
 
 func (ctl *BlogController) UpdateArticle(ctx *gin.Context) {
@@ -223,6 +293,16 @@ func (ctl *BlogController) ListPublicArticles(ctx *gin.Context) {
 		return
 	}
 
+	if params.UseCursor() {
+		articles, err := ctl.BlogService.ListPublicArticlesCursor(ctx, params)
+		if err != nil {
+			http_response.SendError(ctx, err)
+			return
+		}
+		http_response.SendSuccess(ctx, http.StatusOK, "Articles retrieved successfully", articles)
+		return
+	}
+
 	articles, err := ctl.BlogService.ListPublicArticles(ctx, params)
 	if err != nil {
 		http_response.SendError(ctx, err)
@@ -232,6 +312,38 @@ func (ctl *BlogController) ListPublicArticles(ctx *gin.Context) {
 	http_response.SendSuccess(ctx, http.StatusOK, "Articles retrieved successfully", articles)
 }
 
+func (ctl *BlogController) GetBlogArchive(ctx *gin.Context) {
+	archive, err := ctl.BlogService.GetBlogArchive(ctx)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Archive retrieved successfully", archive)
+}
+
+func (ctl *BlogController) ListArchiveArticles(ctx *gin.Context) {
+	var period requests.ArchivePeriod
+	if err := ctx.ShouldBindUri(&period); err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	var params requests.ListArtikel
+	if err := internalHTTP.BindData(ctx, &params); err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	articles, err := ctl.BlogService.ListArchiveArticles(ctx, period, params)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Articles retrieved successfully", articles)
+}
+
 func (ctl *BlogController) GetPublicArticleBySlug(ctx *gin.Context) {
 	slug, err := internalHTTP.BindParams[string](ctx, "id")
 	if err != nil {
@@ -248,6 +360,38 @@ func (ctl *BlogController) GetPublicArticleBySlug(ctx *gin.Context) {
 	http_response.SendSuccess(ctx, http.StatusOK, "Article retrieved successfully", article)
 }
 
+func (ctl *BlogController) GetPublicArticleSchema(ctx *gin.Context) {
+	slug, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.BlogService.GetPublicArticleSchema(ctx, slug)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Structured data retrieved successfully", res)
+}
+
+func (ctl *BlogController) ListPublicArticlesSchema(ctx *gin.Context) {
+	var params requests.ListArtikel
+	if err := internalHTTP.BindData(ctx, &params); err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.BlogService.ListPublicArticlesSchema(ctx, params)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Structured data retrieved successfully", res)
+}
+
 func (ctl *BlogController) GetFeaturedArticle(ctx *gin.Context) {
 	articles, err := ctl.BlogService.GetFeaturedArticle(ctx)
 	if err != nil {
@@ -103,3 +103,31 @@ func (ctl *CatController) Get(ctx *gin.Context) {
 
 	http_response.SendSuccess(ctx, http.StatusOK, "Success get data", res)
 }
+
+// Public endpoints
+
+func (ctl *CatController) ListPublic(ctx *gin.Context) {
+	res, err := ctl.CatServices.ListPublicCategories(ctx)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Success get list category", res)
+}
+
+func (ctl *CatController) GetPublic(ctx *gin.Context) {
+	slug, err := internalHTTP.BindParams[string](ctx, "slug")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.CatServices.GetPublicCategory(ctx, slug)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Success get data", res)
+}
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"net/http"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/services"
+	"sora_landing_be/pkg/authentication"
+	"sora_landing_be/pkg/errors"
+	internalHTTP "sora_landing_be/pkg/http"
+	"sora_landing_be/pkg/http/server/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedController struct {
+	FeedService services.FeedService
+}
+
+func NewFeedController(feedService services.FeedService) FeedController {
+	return FeedController{
+		FeedService: feedService,
+	}
+}
+
+func (ctl *FeedController) Create(ctx *gin.Context) {
+	var payload requests.FeedSubscription
+	if err := internalHTTP.BindData(ctx, &payload); err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	userID := authentication.GetUserDataFromToken(ctx).UserID
+	res, err := ctl.FeedService.CreateSubscription(ctx, userID, payload)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusCreated, "Feed subscription created successfully", res)
+}
+
+func (ctl *FeedController) List(ctx *gin.Context) {
+	var params requests.ListFeedSubscription
+	if err := internalHTTP.BindData(ctx, &params); err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.FeedService.ListSubscriptions(ctx, params)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Success get list feed subscription", res)
+}
+
+func (ctl *FeedController) Get(ctx *gin.Context) {
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.FeedService.GetSubscription(ctx, id)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Success get data", res)
+}
+
+func (ctl *FeedController) Update(ctx *gin.Context) {
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	var payload requests.FeedSubscription
+	if err := internalHTTP.BindData(ctx, &payload); err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	userID := authentication.GetUserDataFromToken(ctx).UserID
+	if err := ctl.FeedService.UpdateSubscription(ctx, id, userID, payload); err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Feed subscription updated successfully", nil)
+}
+
+func (ctl *FeedController) Delete(ctx *gin.Context) {
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	if err := ctl.FeedService.DeleteSubscription(ctx, id); err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Feed subscription deleted successfully", nil)
+}
+
+func (ctl *FeedController) Poll(ctx *gin.Context) {
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.FeedService.PollSubscription(ctx, id)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Feed polled successfully", res)
+}
@@ -3,10 +3,13 @@ package controllers
 import (
 	"fmt"
 	"net/http"
-	"os"
-	"path"
 	"path/filepath"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/services"
+	"sora_landing_be/pkg/authentication"
+	"sora_landing_be/pkg/config"
 	"sora_landing_be/pkg/errors"
+	internalHTTP "sora_landing_be/pkg/http"
 	"sora_landing_be/pkg/http/server/http_response"
 	"sora_landing_be/pkg/utils"
 	"strings"
@@ -14,20 +17,26 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// multipartOverhead is what the form fields and part headers around an uploaded file may add
+const multipartOverhead = 64 << 10
+
 type FileController struct {
+	FileService services.FileService
 }
 
-func NewFileController() FileController {
-	return FileController{}
+func NewFileController(fileService services.FileService) FileController {
+	return FileController{
+		FileService: fileService,
+	}
 }
 
 // UploadFiles handles multiple file uploads
 func (ctl *FileController) UploadFile(c *gin.Context) {
-	// Limit file size (in MB → bytes)
+	// Limit file size (in MB → bytes), leaving room for the rest of the form
 	c.Request.Body = http.MaxBytesReader(
 		c.Writer,
 		c.Request.Body,
-		3024*1024,
+		config.LoadConfig().ObjectStorage.MaxFileSizeBytes()+multipartOverhead,
 	)
 
 	// Get uploaded file
@@ -45,21 +54,58 @@ func (ctl *FileController) UploadFile(c *gin.Context) {
 		return
 	}
 
-	// Generate a safe/unique filename
-	filename := utils.GenerateKeyFile(fileHeader.Filename)
+	var payload requests.UploadFile
+	if err := internalHTTP.BindData(c, &payload); err != nil {
+		http_response.SendError(c, errors.ValidationErrorToAppError(err))
+		return
+	}
 
-	// Save file to local storage (e.g., ./uploads)
-	savePath := path.Join("uploads", filename)
-	if err := c.SaveUploadedFile(fileHeader, savePath); err != nil {
-		http_response.SendError(c, errors.StorageErrorToAppError("Failed to save file"))
+	// Save file to local storage (e.g., ./uploads) and record it
+	userID := authentication.GetUserDataFromToken(c).UserID
+	res, err := ctl.FileService.Upload(c, userID, fileHeader, payload)
+	if err != nil {
+		http_response.SendError(c, err)
 		return
 	}
 
 	// Return success response
-	http_response.SendSuccess(c, http.StatusOK, "Success upload file", map[string]interface{}{
-		"filename": filename,
-		"path":     savePath,
-	})
+	http_response.SendSuccess(c, http.StatusOK, "Success upload file", res)
+}
+
+// PresignUpload starts an upload sent straight to the bucket, for files too large to go through the API
+func (ctl *FileController) PresignUpload(c *gin.Context) {
+	var payload requests.PresignUpload
+	if err := internalHTTP.BindData(c, &payload); err != nil {
+		http_response.SendError(c, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	userID := authentication.GetUserDataFromToken(c).UserID
+	res, err := ctl.FileService.PresignUpload(c, userID, payload)
+	if err != nil {
+		http_response.SendError(c, err)
+		return
+	}
+
+	http_response.SendSuccess(c, http.StatusOK, "Success presign upload", res)
+}
+
+// CompleteUpload records a file once it was sent to the bucket
+func (ctl *FileController) CompleteUpload(c *gin.Context) {
+	var payload requests.CompleteUpload
+	if err := internalHTTP.BindData(c, &payload); err != nil {
+		http_response.SendError(c, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	userID := authentication.GetUserDataFromToken(c).UserID
+	res, err := ctl.FileService.CompleteUpload(c, userID, payload)
+	if err != nil {
+		http_response.SendError(c, err)
+		return
+	}
+
+	http_response.SendSuccess(c, http.StatusOK, "Success upload file", res)
 }
 
 // GetPublicFile gets a public file by ID
@@ -83,13 +129,9 @@ func (ctl *FileController) DeleteFile(c *gin.Context) {
 		return
 	}
 
-	// Try to remove file
-	if err := os.Remove(filePath); err != nil {
-		if os.IsNotExist(err) {
-			http_response.SendError(c, errors.StorageErrorToAppError("File not found"))
-			return
-		}
-		http_response.SendError(c, errors.StorageErrorToAppError("Failed to delete file"))
+	// Try to remove file and its record
+	if err := ctl.FileService.Delete(c, cleanName); err != nil {
+		http_response.SendError(c, err)
 		return
 	}
 
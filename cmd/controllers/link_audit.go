@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"net/http"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/services"
+	"sora_landing_be/pkg/errors"
+	internalHTTP "sora_landing_be/pkg/http"
+	"sora_landing_be/pkg/http/server/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LinkAuditController struct {
+	LinkService services.LinkAuditService
+}
+
+func NewLinkAuditController(linkService services.LinkAuditService) LinkAuditController {
+	return LinkAuditController{
+		LinkService: linkService,
+	}
+}
+
+func (ctl *LinkAuditController) ListReport(ctx *gin.Context) {
+	var params requests.ListLinkReport
+	if err := internalHTTP.BindData(ctx, &params); err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.LinkService.ListLinkReport(ctx, params)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Success get link report", res)
+}
+
+func (ctl *LinkAuditController) GetArticleLinks(ctx *gin.Context) {
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.LinkService.GetArticleLinks(ctx, id)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Success get article links", res)
+}
+
+func (ctl *LinkAuditController) CheckArticle(ctx *gin.Context) {
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.LinkService.CheckArticle(ctx, id)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Article links checked successfully", res)
+}
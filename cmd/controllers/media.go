@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"net/http"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/services"
+	"sora_landing_be/pkg/errors"
+	internalHTTP "sora_landing_be/pkg/http"
+	"sora_landing_be/pkg/http/server/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MediaController struct {
+	FileService services.FileService
+}
+
+func NewMediaController(fileService services.FileService) MediaController {
+	return MediaController{
+		FileService: fileService,
+	}
+}
+
+func (ctl *MediaController) ListMedia(ctx *gin.Context) {
+	var params requests.ListMedia
+	if err := internalHTTP.BindData(ctx, &params); err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.FileService.ListMedia(ctx, params)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Success get media", res)
+}
+
+func (ctl *MediaController) GetMedia(ctx *gin.Context) {
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.FileService.GetMedia(ctx, id)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Success get media", res)
+}
+
+func (ctl *MediaController) UpdateMedia(ctx *gin.Context) {
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	var payload requests.UpdateMedia
+	if err := internalHTTP.BindData(ctx, &payload); err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.FileService.UpdateMedia(ctx, id, payload)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Media updated successfully", res)
+}
+
+func (ctl *MediaController) DeleteMedia(ctx *gin.Context) {
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	if err := ctl.FileService.DeleteMedia(ctx, id); err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Media deleted successfully", nil)
+}
@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sora_landing_be/cmd/services"
+	"sora_landing_be/cmd/templates"
+	internalHTTP "sora_landing_be/pkg/http"
+	"sora_landing_be/pkg/http/server/http_response"
+	"sora_landing_be/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// shareCacheControl lets CDNs and crawlers reuse a share page for a while
+const shareCacheControl = "public, max-age=600"
+
+type ShareController struct {
+	BlogService services.BlogService
+}
+
+func NewShareController(blogService services.BlogService) ShareController {
+	return ShareController{
+		BlogService: blogService,
+	}
+}
+
+// Article serves the Open Graph page of an article to link preview crawlers
+// and redirects everyone else to the article on the website
+func (ctl *ShareController) Article(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+
+	// the response depends on who is asking, caches must keep both variants apart
+	ctx.Header("Vary", "User-Agent")
+	ctx.Header("Cache-Control", shareCacheControl)
+
+	if !internalHTTP.IsLinkPreviewBot(ctx.Request.UserAgent()) {
+		ctx.Redirect(http.StatusFound, services.ArticlePageURL(slug))
+		return
+	}
+
+	page, err := ctl.BlogService.GetSharePage(ctx, slug)
+	if err != nil {
+		ctx.Header("Cache-Control", "no-store")
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := templates.ShareArticle.Execute(&buf, page); err != nil {
+		logger.Log.Error("failed to render share page", zap.String("slug", slug), zap.Error(err))
+		ctx.Header("Cache-Control", "no-store")
+		http_response.SendRaw(ctx, http.StatusInternalServerError, "text/plain; charset=utf-8", http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	ctx.Header("ETag", etag)
+	if ctx.GetHeader("If-None-Match") == etag {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
@@ -103,3 +103,97 @@ func (ctl *TagController) Get(ctx *gin.Context) {
 
 	http_response.SendSuccess(ctx, http.StatusOK, "Success get data", res)
 }
+
+func (ctl *TagController) Merge(ctx *gin.Context) {
+	var payload requests.MergeTags
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	if err = internalHTTP.BindData(ctx, &payload); err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.TagServices.MergeTags(ctx, id, payload)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Tags merged successfully", res)
+}
+
+func (ctl *TagController) ListSynonyms(ctx *gin.Context) {
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.TagServices.ListSynonyms(ctx, id)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Success get list synonym", res)
+}
+
+func (ctl *TagController) CreateSynonym(ctx *gin.Context) {
+	var payload requests.TagSynonym
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	if err = internalHTTP.BindData(ctx, &payload); err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	err = ctl.TagServices.CreateSynonym(ctx, id, payload)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusCreated, "", nil)
+}
+
+func (ctl *TagController) DeleteSynonym(ctx *gin.Context) {
+	id, err := internalHTTP.BindParams[string](ctx, "id")
+	if err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	err = ctl.TagServices.DeleteSynonym(ctx, id, ctx.Param("synonym_id"))
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "", nil)
+}
+
+// Public endpoints
+
+func (ctl *TagController) ListPublic(ctx *gin.Context) {
+	var params requests.ListPublicTag
+	if err := internalHTTP.BindData(ctx, &params); err != nil {
+		http_response.SendError(ctx, errors.ValidationErrorToAppError(err))
+		return
+	}
+
+	res, err := ctl.TagServices.ListPublicTags(ctx, params)
+	if err != nil {
+		http_response.SendError(ctx, err)
+		return
+	}
+
+	http_response.SendSuccess(ctx, http.StatusOK, "Success get list tag", res)
+}
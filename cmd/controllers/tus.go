@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"net/http"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/dto/response"
+	"sora_landing_be/pkg/authentication"
+	"sora_landing_be/pkg/config"
+	"sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/http/server/http_response"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	tusVersion     = "1.0.0"
+	tusExtensions  = "creation,termination,expiration"
+	tusContentType = "application/offset+octet-stream"
+)
+
+// tusHeaders answers every tus request with the protocol version, rejecting clients speaking
+// another one. OPTIONS never gets here, the CORS middleware answers it for all routes.
+func tusHeaders(c *gin.Context) bool {
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", tusExtensions)
+	c.Header("Tus-Max-Size", strconv.FormatInt(config.LoadConfig().Storage.ResumableMaxSizeBytes(), 10))
+	if c.GetHeader("Tus-Resumable") != tusVersion {
+		c.AbortWithStatus(http.StatusPreconditionFailed)
+		return false
+	}
+	return true
+}
+
+func setUploadState(c *gin.Context, res response.ResumableUpload) {
+	c.Header("Upload-Offset", strconv.FormatInt(res.Offset, 10))
+	c.Header("Upload-Expires", res.ExpiresAt.UTC().Format(http.TimeFormat))
+	// the finished upload is the file record a regular upload returns
+	if res.FileID != "" {
+		c.Header("X-File-Id", res.FileID)
+	}
+}
+
+// CreateResumableUpload starts a tus upload, the client sends the chunks to the returned Location
+func (ctl *FileController) CreateResumableUpload(c *gin.Context) {
+	if !tusHeaders(c) {
+		return
+	}
+	var payload requests.CreateResumableUpload
+	if err := c.ShouldBindHeader(&payload); err != nil {
+		http_response.SendError(c, errors.StorageErrorToAppError("Upload-Length must be a number"))
+		return
+	}
+
+	userID := authentication.GetUserDataFromToken(c).UserID
+	res, err := ctl.FileService.CreateResumableUpload(c, userID, payload)
+	if err != nil {
+		http_response.SendError(c, err)
+		return
+	}
+
+	c.Header("Location", strings.TrimSuffix(c.Request.URL.Path, "/")+"/"+res.ID)
+	c.Header("Upload-Expires", res.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusCreated)
+}
+
+// GetResumableUpload tells the client where to resume an upload
+func (ctl *FileController) GetResumableUpload(c *gin.Context) {
+	if !tusHeaders(c) {
+		return
+	}
+
+	userID := authentication.GetUserDataFromToken(c).UserID
+	res, err := ctl.FileService.GetResumableUpload(c, userID, c.Param("id"))
+	if err != nil {
+		http_response.SendError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Upload-Length", strconv.FormatInt(res.Length, 10))
+	if res.Metadata != "" {
+		c.Header("Upload-Metadata", res.Metadata)
+	}
+	setUploadState(c, res)
+	c.Status(http.StatusOK)
+}
+
+// AppendResumableUpload receives the next chunk of a tus upload
+func (ctl *FileController) AppendResumableUpload(c *gin.Context) {
+	if !tusHeaders(c) {
+		return
+	}
+	if c.ContentType() != tusContentType {
+		http_response.SendError(c, errors.NewDefaultError(http.StatusUnsupportedMediaType, "Content-Type must be "+tusContentType))
+		return
+	}
+	var payload requests.AppendResumableUpload
+	if err := c.ShouldBindHeader(&payload); err != nil {
+		http_response.SendError(c, errors.StorageErrorToAppError("Upload-Offset must be a number"))
+		return
+	}
+
+	userID := authentication.GetUserDataFromToken(c).UserID
+	res, err := ctl.FileService.AppendResumableUpload(c, userID, c.Param("id"), payload, c.Request.Body)
+	if err != nil {
+		http_response.SendError(c, err)
+		return
+	}
+
+	setUploadState(c, res)
+	c.Status(http.StatusNoContent)
+}
+
+// TerminateResumableUpload drops an unfinished upload and what was received of it
+func (ctl *FileController) TerminateResumableUpload(c *gin.Context) {
+	if !tusHeaders(c) {
+		return
+	}
+
+	userID := authentication.GetUserDataFromToken(c).UserID
+	if err := ctl.FileService.TerminateResumableUpload(c, userID, c.Param("id")); err != nil {
+		http_response.SendError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
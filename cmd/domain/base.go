@@ -18,7 +18,9 @@ type BaseEntity struct {
 func (m *BaseEntity) BeforeAppendModel(_ context.Context, query bun.Query) error {
 	switch query.(type) {
 	case *bun.InsertQuery:
-		m.ID = ksuid.New().String()
+		if m.ID == "" {
+			m.ID = ksuid.New().String()
+		}
 		if m.CreatedAt.IsZero() {
 			m.CreatedAt = time.Now()
 		}
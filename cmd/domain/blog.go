@@ -11,19 +11,28 @@ type BlogArtikel struct {
 	bun.BaseModel `bun:"table:blog_artikels,alias:ba"`
 	BaseEntity
 
-	Title       string                  `bun:",notnull"`
-	Slug        string                  `bun:",unique,notnull"`
-	Content     string                  `bun:",type:text,notnull"`
-	Excerpt     string                  `bun:",type:text"`
-	ImageURL    string                  `bun:",nullzero"` // optional feature imag
-	CategoryID  string                  `bun:",notnull"`
-	Category    *Category               `bun:"rel:belongs-to,join:category_id=id"`
-	AuthorID    string                  `bun:",notnull"`
-	Author      *User                   `bun:"rel:belongs-to,join:author_id=id"`
-	Status      constants.ArticleStatus `bun:",notnull,default:'draft'"` // draft, published, archived
-	Views       int64                   `bun:",default:0"`
-	Source      string                  `bun:",notnull,default:'-'"`
-	Featured    *int                    `bun:",unique"`
-	PublishedAt time.Time               `bun:",nullzero"`
-	Tags        []*Tag                  `bun:"m2m:article_tags,join:Article=Tag"`
+	Title            string                  `bun:",notnull"`
+	Slug             string                  `bun:",unique,notnull"`
+	Content          string                  `bun:",type:text,notnull"`
+	Excerpt          string                  `bun:",type:text"`
+	ExcerptGenerated bool                    `bun:",notnull,default:false"` // derived from the content, not written by an editor
+	ImageURL         string                  `bun:",nullzero"`              // optional feature imag
+	CategoryID       string                  `bun:",notnull"`
+	Category         *Category               `bun:"rel:belongs-to,join:category_id=id"`
+	AuthorID         string                  `bun:",notnull"`
+	Author           *User                   `bun:"rel:belongs-to,join:author_id=id"`
+	Status           constants.ArticleStatus `bun:",notnull,default:'draft'"` // draft, published, archived
+	Views            int64                   `bun:",default:0"`
+	Source           string                  `bun:",notnull,default:'-'"`
+	SourceHash       string                  `bun:",nullzero"` // hash of the extracted source, used to detect changes on re-sync
+	SyncedAt         time.Time               `bun:",nullzero"`
+	Featured         *int                    `bun:",unique"`
+	PublishedAt      time.Time               `bun:",nullzero"`
+	UnpublishAt      time.Time               `bun:",nullzero"` // the article is archived once this passes
+	LinksCheckedAt   time.Time               `bun:",nullzero"` // last run of the link audit
+	Tags             []*Tag                  `bun:"m2m:article_tags,join:Article=Tag"`
+	Links            []*ArticleLink          `bun:"rel:has-many,join:id=article_id"`
+
+	SeoMeta
+	SeoGenerated
 }
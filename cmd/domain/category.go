@@ -10,10 +10,17 @@ type Category struct {
 
 	Name        string  `bun:",unique,notnull"`
 	Slug        string  `bun:",unique,notnull"`
+	Description string  `bun:",nullzero"`
+	ImageURL    string  `bun:",nullzero"`
 	CreatedByID string  `bun:",nullzero"`
 	CreatedBy   *User   `bun:"rel:belongs-to,join:created_by_id=id"`
 	EditedByID  *string `bun:",nullzero"`
 	EditedBy    *User   `bun:"rel:belongs-to,join:edited_by_id=id"`
+	// Tree position, Path is the chain of slugs from the root e.g. product/pos/tutorials
+	ParentID *string   `bun:",nullzero"`
+	Parent   *Category `bun:"rel:belongs-to,join:parent_id=id"`
+	Path     string    `bun:",notnull"`
+	SeoMeta
 	// Reverse relation
 	BlogArtikels []*BlogArtikel `bun:"rel:has-many,join:id=category_id"`
 }
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"sora_landing_be/cmd/constants"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type FeedSubscription struct {
+	bun.BaseModel `bun:"table:feed_subscriptions,alias:fs"`
+	BaseEntity
+
+	URL          string    `bun:",notnull"`
+	Title        string    `bun:",nullzero"`
+	CategoryID   *string   `bun:",nullzero"`
+	Category     *Category `bun:"rel:belongs-to,join:category_id=id"`
+	TagIDs       []string  `bun:",array"`
+	AutoPublish  bool      `bun:",notnull"`
+	Active       bool      `bun:",notnull"`
+	ETag         string    `bun:"etag,nullzero"`
+	LastModified string    `bun:",nullzero"`
+	LastPolledAt time.Time `bun:",nullzero"`
+	LastError    string    `bun:",nullzero"`
+	CreatedByID  string    `bun:",notnull"`
+	EditedByID   *string   `bun:",nullzero"`
+}
+
+// FeedEntry records every feed item we tried to import so it is not fetched again
+type FeedEntry struct {
+	bun.BaseModel `bun:"table:feed_entries,alias:fe"`
+	BaseEntity
+
+	SubscriptionID string                 `bun:",notnull"`
+	GUID           string                 `bun:"guid,notnull"`
+	URL            string                 `bun:",notnull"`
+	Status         constants.ImportStatus `bun:",notnull"`
+	ArticleID      *string                `bun:",nullzero"`
+	Attempts       int                    `bun:",notnull"`
+	Error          string                 `bun:",nullzero"`
+}
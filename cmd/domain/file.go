@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"sora_landing_be/cmd/constants"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// FileUpload is a file stored in the upload directory
+type FileUpload struct {
+	bun.BaseModel `bun:"table:file_uploads,alias:fu"`
+	BaseEntity
+
+	FileName    string  `bun:",notnull"` // name of the file on the uploader's side
+	FilePath    string  `bun:",notnull"`
+	FileSize    int64   `bun:",notnull"`
+	ContentType string  `bun:",notnull"`
+	ContentHash string  `bun:",nullzero"` // hex encoded SHA-256 of the content
+	Module      string  `bun:",nullzero"`
+	ReferenceID *string `bun:",nullzero"`
+	UploadedBy  string  `bun:",notnull"`
+	IsPublic    bool    `bun:",notnull"`
+	IsTemporary bool    `bun:",notnull"` // not yet referenced by saved content, swept after storage.temporary_ttl
+	Title       string  `bun:",nullzero"`
+	AltText     string  `bun:",nullzero"`
+	Caption     string  `bun:",nullzero"`
+	Width       int     `bun:",nullzero"` // set for images only
+	Height      int     `bun:",nullzero"`
+
+	Uploader *User          `bun:"rel:belongs-to,join:uploaded_by=id"`
+	Articles []*ArticleFile `bun:"rel:has-many,join:id=file_id"`
+	Variants []*FileVariant `bun:"rel:has-many,join:id=file_id"`
+}
+
+// FileVariant is a resized copy of an uploaded image, stored next to it in one format
+type FileVariant struct {
+	bun.BaseModel `bun:"table:file_variants,alias:fv"`
+
+	FileID      string    `bun:",pk"`
+	Name        string    `bun:",pk"`
+	Format      string    `bun:",pk"` // webp or the format of the original
+	FilePath    string    `bun:",notnull"`
+	FileSize    int64     `bun:",notnull"`
+	ContentType string    `bun:",notnull"`
+	Width       int       `bun:",notnull"`
+	Height      int       `bun:",notnull"`
+	CreatedAt   time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+}
+
+// ArticleFile links an upload to an article showing it as cover or inside the content
+type ArticleFile struct {
+	bun.BaseModel `bun:"table:article_files,alias:af"`
+
+	ArticleID string              `bun:",pk"`
+	FileID    string              `bun:",pk"`
+	File      *FileUpload         `bun:"rel:belongs-to,join:file_id=id"`
+	Article   *BlogArtikel        `bun:"rel:belongs-to,join:article_id=id"`
+	Usage     constants.FileUsage `bun:",pk"`
+}
+
+// UploadSession is a resumable upload in progress. Its chunks are stored as hidden parts
+// until the last one arrives and they are joined into the file under FileKey. Direct
+// uploads have no chunks, the client sends the object under FileKey to the bucket itself.
+type UploadSession struct {
+	bun.BaseModel `bun:"table:upload_sessions,alias:us"`
+	BaseEntity
+
+	FileKey      string   `bun:",notnull"`
+	FileName     string   `bun:",notnull"`
+	UploadLength int64    `bun:",notnull"`
+	UploadOffset int64    `bun:",notnull"`
+	Parts        []string `bun:",array"`    // storage keys of the chunks received so far, in order
+	Metadata     string   `bun:",nullzero"` // Upload-Metadata as the client sent it
+	Module       string   `bun:",nullzero"`
+	ReferenceID  *string  `bun:",nullzero"`
+	IsPublic     bool     `bun:",notnull"`
+	UploadedBy   string   `bun:",notnull"`
+	FileID       *string  `bun:",nullzero"` // set once the last chunk arrived
+	Direct       bool     `bun:",notnull"`  // a presigned upload, FileID is set once completed
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"sora_landing_be/cmd/constants"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type ImportJob struct {
+	bun.BaseModel `bun:"table:import_jobs,alias:ij"`
+	BaseEntity
+
+	CreatedByID string                 `bun:",notnull"`
+	Status      constants.ImportStatus `bun:",notnull,default:'pending'"`
+	AsDraft     bool                   `bun:",notnull"`
+	Total       int                    `bun:",notnull"`
+	FinishedAt  time.Time              `bun:",nullzero"`
+	Items       []*ImportJobItem       `bun:"rel:has-many,join:id=job_id"`
+}
+
+type ImportJobItem struct {
+	bun.BaseModel `bun:"table:import_job_items,alias:iji"`
+	BaseEntity
+
+	JobID     string                 `bun:",notnull"`
+	URL       string                 `bun:",notnull"`
+	Status    constants.ImportStatus `bun:",notnull,default:'pending'"`
+	ArticleID *string                `bun:",nullzero"`
+	Error     string                 `bun:",nullzero"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"sora_landing_be/cmd/constants"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ArticleLink is a link or image found in an article with the result of its last check
+type ArticleLink struct {
+	bun.BaseModel `bun:"table:article_links,alias:al"`
+	BaseEntity
+
+	ArticleID  string               `bun:",notnull"`
+	Article    *BlogArtikel         `bun:"rel:belongs-to,join:article_id=id"`
+	URL        string               `bun:",notnull"`
+	Kind       constants.LinkKind   `bun:",notnull"`
+	Internal   bool                 `bun:",notnull"`
+	Status     constants.LinkStatus `bun:",notnull"`
+	StatusCode int                  `bun:",nullzero"`
+	FinalURL   string               `bun:",nullzero"` // where a redirected link ends up
+	Error      string               `bun:",nullzero"`
+	CheckedAt  time.Time            `bun:",notnull"`
+}
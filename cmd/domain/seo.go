@@ -0,0 +1,19 @@
+package domain
+
+// SeoMeta holds search engine and Open Graph overrides. It is embedded in
+// every model that has its own public page; empty fields mean "use the default".
+type SeoMeta struct {
+	MetaTitle       string `bun:",nullzero"`
+	MetaDescription string `bun:",type:text,nullzero"`
+	CanonicalURL    string `bun:",nullzero"`
+	OgImage         string `bun:",nullzero"`
+	NoIndex         bool   `bun:"noindex,notnull,default:false"`
+}
+
+// SeoGenerated marks the SEO fields of an article filled in from the article itself rather
+// than written by an editor, they follow the title, excerpt and cover until overridden
+type SeoGenerated struct {
+	MetaTitleGenerated       bool `bun:",notnull,default:false"`
+	MetaDescriptionGenerated bool `bun:",notnull,default:false"`
+	OgImageGenerated         bool `bun:",notnull,default:false"`
+}
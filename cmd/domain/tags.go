@@ -9,11 +9,36 @@ type Tag struct {
 	BaseEntity
 	Name        string  `bun:",unique,notnull"`
 	Slug        string  `bun:",unique,notnull"`
+	Description string  `bun:",nullzero"`
+	ImageURL    string  `bun:",nullzero"`
 	CreatedByID string  `bun:",nullzero"`
 	CreatedBy   *User   `bun:"rel:belongs-to,join:created_by_id=id"`
 	EditedByID  *string `bun:",nullzero"`
 	EditedBy    *User   `bun:"rel:belongs-to,join:edited_by_id=id"`
+	SeoMeta
 
 	// Reverse relation
 	BlogArtikels []*BlogArtikel `bun:"rel:has-many,join:id=category_id"`
+	Synonyms     []*TagSynonym  `bun:"rel:has-many,join:id=tag_id"`
+}
+
+// TagSynonym is an alternative name that resolves to its tag, Normalized is the slugified name
+type TagSynonym struct {
+	bun.BaseModel `bun:"table:tag_synonyms,alias:ts"`
+	BaseEntity
+
+	TagID       string `bun:",notnull"`
+	Tag         *Tag   `bun:"rel:belongs-to,join:tag_id=id"`
+	Name        string `bun:",notnull"`
+	Normalized  string `bun:",notnull"`
+	CreatedByID string `bun:",nullzero"`
+}
+
+// TagRedirect points the slug of a merged or renamed tag at the tag that replaced it
+type TagRedirect struct {
+	bun.BaseModel `bun:"table:tag_redirects,alias:tr"`
+	BaseEntity
+
+	OldSlug string `bun:",notnull"`
+	TagID   string `bun:",notnull"`
 }
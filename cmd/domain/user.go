@@ -13,6 +13,7 @@ type User struct {
 	bun.BaseModel
 	ID             string               `bun:",pk"`
 	Name           string               `bun:"name"`
+	Slug           string               `bun:",nullzero"`
 	Email          string               `bun:",nullzero"`
 	Roles          []constants.UserRole `bun:",array"`
 	Status         constants.UserStatus `bun:","`
@@ -24,7 +25,9 @@ type User struct {
 func (m *User) BeforeAppendModel(_ context.Context, query bun.Query) error {
 	switch query.(type) {
 	case *bun.InsertQuery:
-		m.ID = ksuid.New().String()
+		if m.ID == "" {
+			m.ID = ksuid.New().String()
+		}
 	case *bun.UpdateQuery:
 		m.UpdatedAt = time.Now()
 	}
@@ -0,0 +1,8 @@
+package dto
+
+// ArchiveMonth is the number of published articles in one month of the site calendar
+type ArchiveMonth struct {
+	Year  int `bun:"year"`
+	Month int `bun:"month"`
+	Count int `bun:"count"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the boundary row of a keyset paginated page, it is sent to clients as an opaque string
+type Cursor struct {
+	SortBy   string `json:"s"`
+	Desc     bool   `json:"d,omitempty"`
+	Value    string `json:"v"`
+	ID       string `json:"i"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+type CursorPaginationResponse[T any] struct {
+	PageSize    int     `json:"page_size"`
+	HasPrevious bool    `json:"has_previous"`
+	HasNext     bool    `json:"has_next"`
+	PrevCursor  *string `json:"prev_cursor"`
+	NextCursor  *string `json:"next_cursor"`
+	Data        []T     `json:"data"`
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+)
+
+// MediaFile is an upload with the number of places content shows it
+type MediaFile struct {
+	domain.FileUpload `bun:",extend"`
+	UsageCount        int `bun:"usage_count"`
+}
+
+// FileUsage is a place content shows a file, an article, category or tag
+type FileUsage struct {
+	Type   string // article, category or tag
+	ID     string
+	Title  string
+	Slug   string
+	Status constants.ArticleStatus // set for articles only
+	Usage  constants.FileUsage
+}
@@ -1,9 +1,11 @@
 package requests
 
 import (
+	"slices"
 	"sora_landing_be/cmd/constants"
 	"sora_landing_be/cmd/domain"
 	"sora_landing_be/cmd/dto"
+	"strings"
 	"time"
 )
 
@@ -18,9 +20,27 @@ type (
 		TagIDs     []string                `json:"tag_ids" validate:"dive,required"`
 		Status     constants.ArticleStatus `json:"status" validate:"required,oneof=draft published scheduled archived"`
 		PublishAt  *time.Time              `json:"publish_at,omitempty" validate:"required_if=Status scheduled"`
+		// UnpublishAt archives the article automatically, for promos and events
+		UnpublishAt *time.Time `json:"unpublish_at,omitempty"`
+		Seo         *SeoMeta   `json:"seo,omitempty"`
 	}
 	FromURL struct {
-		URL string `json:"url" validate:"required"`
+		URL     string `json:"url" validate:"required,url"`
+		AsDraft bool   `json:"as_draft"`
+		// AutoTag attaches the existing tags suggested for the imported content
+		AutoTag bool `json:"auto_tag"`
+	}
+	// SuggestTags scores either the given title and content or a stored article
+	SuggestTags struct {
+		ArticleID string `json:"article_id"`
+		Title     string `json:"title" validate:"required_without=ArticleID"`
+		Content   string `json:"content"`
+		Limit     int    `json:"limit" validate:"omitempty,min=1,max=50"`
+	}
+	// BatchFromURL queues several external URLs for asynchronous import
+	BatchFromURL struct {
+		URLs    []string `json:"urls" validate:"required,min=1,max=50,dive,required,url"`
+		AsDraft bool     `json:"as_draft"`
 	}
 	UpdateArtikel struct {
 		Title      string                   `json:"title" validate:"required,min=3,max=255"`
@@ -31,6 +51,13 @@ type (
 		TagIDs     []string                 `json:"tag_ids" validate:"dive,omitempty"`
 		Status     *constants.ArticleStatus `json:"status" validate:"omitempty,oneof=draft published scheduled archived"`
 		PublishAt  *time.Time               `json:"publish_at,omitempty" validate:"required_if=Status scheduled"`
+		// ClearExcerpt drops an authored excerpt so it is generated from the content again
+		ClearExcerpt bool `json:"clear_excerpt"`
+		// UnpublishAt replaces the expiry when present, ClearUnpublishAt removes it
+		UnpublishAt      *time.Time `json:"unpublish_at,omitempty"`
+		ClearUnpublishAt bool       `json:"clear_unpublish_at"`
+		// Seo replaces the article's SEO metadata when present and keeps it otherwise
+		Seo *SeoMeta `json:"seo,omitempty"`
 	}
 
 	// ListArtikel is used for querying blog articles with filters
@@ -44,6 +71,26 @@ type (
 		EndDate    *time.Time              `form:"end_date,omitempty"`
 		SortBy     string                  `form:"sort_by,omitempty" validate:"omitempty,oneof=created_at published_at views title"`
 		SortOrder  string                  `form:"sort_order,omitempty" validate:"omitempty,oneof=asc desc"`
+		// Paginate selects offset (default) or cursor pagination, passing a cursor implies cursor mode
+		Paginate string `form:"paginate,omitempty" validate:"omitempty,oneof=offset cursor"`
+		Cursor   string `form:"cursor,omitempty"`
+		// Public listing filters, the frontend references categories, tags and authors by slug
+		Category           string     `form:"category,omitempty"`
+		IncludeDescendants bool       `form:"include_descendants,omitempty"`
+		Tags               string     `form:"tags,omitempty"`
+		TagMode            string     `form:"tag_mode,omitempty" validate:"omitempty,oneof=any all"`
+		ExcludeTags        string     `form:"exclude_tags,omitempty"`
+		Author             string     `form:"author,omitempty"`
+		PublishedFrom      *time.Time `form:"published_from,omitempty"`
+		PublishedTo        *time.Time `form:"published_to,omitempty"`
+		// IncludeFeatured keeps featured articles in public listings, they are shown separately on the blog home
+		IncludeFeatured bool `form:"-"`
+	}
+
+	// ArchivePeriod selects a year, or a month of it, of the blog archive
+	ArchivePeriod struct {
+		Year  int `uri:"year" binding:"required,min=1"`
+		Month int `uri:"month" binding:"omitempty,min=1,max=12"`
 	}
 
 	UpdateFeaturedPos struct {
@@ -54,9 +101,44 @@ type (
 	UpdateArticleStatus struct {
 		Status    constants.ArticleStatus `json:"status" validate:"required,oneof=draft published scheduled archived"`
 		PublishAt *time.Time              `json:"publish_at,omitempty" validate:"required_if=Status scheduled"`
+		// UnpublishAt replaces the expiry when present, ClearUnpublishAt removes it
+		UnpublishAt      *time.Time `json:"unpublish_at,omitempty"`
+		ClearUnpublishAt bool       `json:"clear_unpublish_at"`
 	}
 )
 
+// UseCursor reports whether the listing should be keyset paginated
+func (r ListArtikel) UseCursor() bool {
+	return r.Paginate == "cursor" || r.Cursor != ""
+}
+
+// TagSlugs returns the tags an article must carry, see TagMode
+func (r ListArtikel) TagSlugs() []string {
+	return splitSlugs(r.Tags)
+}
+
+// ExcludedTagSlugs returns the tags an article must not carry
+func (r ListArtikel) ExcludedTagSlugs() []string {
+	return splitSlugs(r.ExcludeTags)
+}
+
+// MatchAllTags reports whether every requested tag is required instead of any of them
+func (r ListArtikel) MatchAllTags() bool {
+	return r.TagMode == "all"
+}
+
+// splitSlugs parses a comma separated slug list, dropping blanks and duplicates
+func splitSlugs(raw string) []string {
+	var res []string
+	for _, part := range strings.Split(raw, ",") {
+		slug := strings.ToLower(strings.TrimSpace(part))
+		if slug != "" && !slices.Contains(res, slug) {
+			res = append(res, slug)
+		}
+	}
+	return res
+}
+
 func (r *BlogArtikel) ToDomain(userID string, slug string) *domain.BlogArtikel {
 	article := &domain.BlogArtikel{
 		Title:      r.Title,
@@ -68,6 +150,11 @@ func (r *BlogArtikel) ToDomain(userID string, slug string) *domain.BlogArtikel {
 		CategoryID: r.CategoryID,
 		AuthorID:   userID,
 		Tags:       make([]*domain.Tag, 0), // will be filled later by service
+		SeoMeta:    r.Seo.ToDomain(),
+	}
+
+	if r.UnpublishAt != nil {
+		article.UnpublishAt = *r.UnpublishAt
 	}
 
 	if r.Status == constants.StatusPublished {
@@ -7,7 +7,13 @@ import (
 
 type (
 	Category struct {
-		Name string `json:"name" validate:"required"`
+		Name        string `json:"name" validate:"required"`
+		Description string `json:"description" validate:"omitempty,max=1000"`
+		ImageURL    string `json:"image_url"`
+		// ParentID places the category under another one, on update nil keeps the
+		// current parent and an empty string moves the subtree to the root
+		ParentID *string  `json:"parent_id,omitempty"`
+		Seo      *SeoMeta `json:"seo,omitempty"`
 	}
 	ListCategory struct {
 		dto.PaginationRequest
@@ -17,7 +23,10 @@ type (
 
 func (c *Category) ToDomain(slug string) domain.Category {
 	return domain.Category{
-		Name: c.Name,
-		Slug: slug,
+		Name:        c.Name,
+		Slug:        slug,
+		Description: c.Description,
+		ImageURL:    c.ImageURL,
+		SeoMeta:     c.Seo.ToDomain(),
 	}
 }
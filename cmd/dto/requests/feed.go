@@ -0,0 +1,42 @@
+package requests
+
+import (
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto"
+)
+
+type (
+	// FeedSubscription creates or updates an RSS/Atom subscription.
+	// Entries are imported into CategoryID, or the "external" category when empty.
+	FeedSubscription struct {
+		URL         string   `json:"url" validate:"required,url"`
+		CategoryID  string   `json:"category_id" validate:"omitempty"`
+		TagIDs      []string `json:"tag_ids" validate:"dive,required"`
+		AutoPublish bool     `json:"auto_publish"`
+		Active      *bool    `json:"active"`
+	}
+
+	ListFeedSubscription struct {
+		dto.PaginationRequest
+		Search string `form:"search,omitempty"`
+	}
+)
+
+func (r *FeedSubscription) ToDomain() domain.FeedSubscription {
+	data := domain.FeedSubscription{
+		URL:         r.URL,
+		TagIDs:      r.TagIDs,
+		AutoPublish: r.AutoPublish,
+		Active:      true,
+	}
+	if data.TagIDs == nil {
+		data.TagIDs = []string{}
+	}
+	if r.CategoryID != "" {
+		data.CategoryID = &r.CategoryID
+	}
+	if r.Active != nil {
+		data.Active = *r.Active
+	}
+	return data
+}
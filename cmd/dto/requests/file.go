@@ -0,0 +1,35 @@
+package requests
+
+// UploadFile describes where an uploaded file is used, sent as form fields next to the file
+type UploadFile struct {
+	Module      string `form:"module" validate:"omitempty,max=50"`
+	ReferenceID string `form:"reference_id"`
+	IsPublic    bool   `form:"is_public"`
+}
+
+// PresignUpload starts an upload the client sends straight to the bucket
+type PresignUpload struct {
+	FileName    string `json:"file_name" validate:"required,max=255"`
+	ContentType string `json:"content_type" validate:"required,max=255"`
+	Size        int64  `json:"size" validate:"required,gt=0"`
+	Module      string `json:"module" validate:"omitempty,max=50"`
+	ReferenceID string `json:"reference_id"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+// CompleteUpload registers a file once the client finished sending it to the bucket
+type CompleteUpload struct {
+	Key string `json:"key" validate:"required"`
+}
+
+// CreateResumableUpload starts a tus upload, Metadata holds comma separated keys with
+// base64 values and must name the file
+type CreateResumableUpload struct {
+	Length   *int64 `header:"Upload-Length"`
+	Metadata string `header:"Upload-Metadata"`
+}
+
+// AppendResumableUpload sends the next chunk of a tus upload, starting at Offset
+type AppendResumableUpload struct {
+	Offset *int64 `header:"Upload-Offset"`
+}
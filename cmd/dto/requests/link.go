@@ -0,0 +1,25 @@
+package requests
+
+import (
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/dto"
+)
+
+// ListLinkReport pages through the articles whose links need attention
+type ListLinkReport struct {
+	dto.PaginationRequest
+	// Status is a comma separated list of link statuses, broken and unreachable by default
+	Status   string `form:"status,omitempty"`
+	Internal *bool  `form:"internal,omitempty"`
+}
+
+func (r ListLinkReport) LinkStatuses() []constants.LinkStatus {
+	var res []constants.LinkStatus
+	for _, status := range splitSlugs(r.Status) {
+		res = append(res, constants.LinkStatus(status))
+	}
+	if len(res) == 0 {
+		res = []constants.LinkStatus{constants.LinkStatusBroken, constants.LinkStatusUnreachable}
+	}
+	return res
+}
@@ -0,0 +1,29 @@
+package requests
+
+import (
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/dto"
+	"time"
+)
+
+type (
+	// ListMedia pages through the uploaded files
+	ListMedia struct {
+		dto.PaginationRequest
+		// Search matches the original file name, title, alt text and caption
+		Search     string              `form:"search,omitempty"`
+		Type       constants.MediaType `form:"type,omitempty" validate:"omitempty,oneof=image video audio document"`
+		UploadedBy string              `form:"uploaded_by,omitempty"`
+		StartDate  *time.Time          `form:"start_date,omitempty"`
+		EndDate    *time.Time          `form:"end_date,omitempty"`
+		SortBy     string              `form:"sort_by,omitempty" validate:"omitempty,oneof=created_at file_name file_size"`
+		SortOrder  string              `form:"sort_order,omitempty" validate:"omitempty,oneof=asc desc"`
+	}
+
+	// UpdateMedia edits the descriptive fields of a file, fields left out keep their value
+	UpdateMedia struct {
+		Title   *string `json:"title" validate:"omitempty,max=255"`
+		AltText *string `json:"alt_text" validate:"omitempty,max=500"`
+		Caption *string `json:"caption"`
+	}
+)
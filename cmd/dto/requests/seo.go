@@ -0,0 +1,26 @@
+package requests
+
+import "sora_landing_be/cmd/domain"
+
+// SeoMeta overrides the search engine and Open Graph metadata of a page.
+// Fields left empty are filled with defaults when an article is published.
+type SeoMeta struct {
+	MetaTitle       string `json:"meta_title" validate:"omitempty,max=70"`
+	MetaDescription string `json:"meta_description" validate:"omitempty,max=320"`
+	CanonicalURL    string `json:"canonical_url" validate:"omitempty,url"`
+	OgImage         string `json:"og_image" validate:"omitempty,max=2048"`
+	NoIndex         bool   `json:"noindex"`
+}
+
+func (r *SeoMeta) ToDomain() domain.SeoMeta {
+	if r == nil {
+		return domain.SeoMeta{}
+	}
+	return domain.SeoMeta{
+		MetaTitle:       r.MetaTitle,
+		MetaDescription: r.MetaDescription,
+		CanonicalURL:    r.CanonicalURL,
+		OgImage:         r.OgImage,
+		NoIndex:         r.NoIndex,
+	}
+}
@@ -6,7 +6,10 @@ import (
 )
 
 type TagRequest struct {
-	Name string `json:"name" validate:"required"`
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description" validate:"omitempty,max=1000"`
+	ImageURL    string   `json:"image_url"`
+	Seo         *SeoMeta `json:"seo,omitempty"`
 }
 
 type ListTag struct {
@@ -16,7 +19,25 @@ type ListTag struct {
 
 func (r *TagRequest) ToDomain(slug string) domain.Tag {
 	return domain.Tag{
-		Name: r.Name,
-		Slug: slug,
+		Name:        r.Name,
+		Slug:        slug,
+		Description: r.Description,
+		ImageURL:    r.ImageURL,
+		SeoMeta:     r.Seo.ToDomain(),
 	}
 }
+
+// MergeTags folds the source tags into the tag addressed by the route
+type MergeTags struct {
+	SourceIDs []string `json:"source_ids" validate:"required,min=1,dive,required"`
+}
+
+type TagSynonym struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// ListPublicTag selects a plain list, or a cloud of the most used tags weighted by usage
+type ListPublicTag struct {
+	Mode  string `form:"mode,omitempty" validate:"omitempty,oneof=list cloud"`
+	Limit int    `form:"limit,omitempty" validate:"omitempty,min=1,max=500"`
+}
@@ -0,0 +1,10 @@
+package response
+
+// ArchiveImport summarizes what was restored from a content archive
+type ArchiveImport struct {
+	Authors    int `json:"authors"`
+	Categories int `json:"categories"`
+	Tags       int `json:"tags"`
+	Articles   int `json:"articles"`
+	Files      int `json:"files"`
+}
@@ -9,20 +9,24 @@ import (
 type (
 	// BlogArticle represents the full article response
 	BlogArticle struct {
-		ID          string                  `json:"id"`
-		Title       string                  `json:"title"`
-		Slug        string                  `json:"slug"`
-		Excerpt     string                  `json:"excerpt"`
-		Content     string                  `json:"content"`
-		ImageURL    string                  `json:"image_url"`
-		Views       int64                   `json:"views"`
-		Status      constants.ArticleStatus `json:"status"`
-		PublishedAt *time.Time              `json:"published_at,omitempty"`
-		Category    *CategoryResponse       `json:"category,omitempty"`
-		Author      *User                   `json:"author,omitempty"`
-		Tags        []Tag                   `json:"tags"`
-		CreatedAt   time.Time               `json:"created_at"`
-		UpdatedAt   time.Time               `json:"updated_at"`
+		ID               string                  `json:"id"`
+		Title            string                  `json:"title"`
+		Slug             string                  `json:"slug"`
+		Excerpt          string                  `json:"excerpt"`
+		ExcerptGenerated bool                    `json:"excerpt_generated"` // follows the content until an editor writes one
+		Content          string                  `json:"content"`
+		ImageURL         string                  `json:"image_url"`
+		Image            *ResponsiveImage        `json:"image,omitempty"`
+		Views            int64                   `json:"views"`
+		Status           constants.ArticleStatus `json:"status"`
+		PublishedAt      *time.Time              `json:"published_at,omitempty"`
+		UnpublishAt      *time.Time              `json:"unpublish_at,omitempty"`
+		Category         *CategoryResponse       `json:"category,omitempty"`
+		Author           *User                   `json:"author,omitempty"`
+		Tags             []Tag                   `json:"tags"`
+		Seo              *Seo                    `json:"seo"`
+		CreatedAt        time.Time               `json:"created_at"`
+		UpdatedAt        time.Time               `json:"updated_at"`
 	}
 
 	// BlogArticleList represents a summarized version for list views
@@ -32,9 +36,11 @@ type (
 		Slug        string                  `json:"slug"`
 		Excerpt     string                  `json:"excerpt"`
 		ImageURL    string                  `json:"image_url"`
+		Image       *ResponsiveImage        `json:"image,omitempty"`
 		Views       int64                   `json:"views"`
 		Status      constants.ArticleStatus `json:"status"`
 		PublishedAt *time.Time              `json:"published_at,omitempty"`
+		UnpublishAt *time.Time              `json:"unpublish_at,omitempty"`
 		Category    *CategoryResponse       `json:"category,omitempty"`
 		Author      *User                   `json:"author,omitempty"`
 		TagCount    int                     `json:"tag_count"`
@@ -57,16 +63,21 @@ func (b *BlogArticle) FromDomain(article *domain.BlogArtikel) {
 	b.Title = article.Title
 	b.Slug = article.Slug
 	b.Excerpt = article.Excerpt
+	b.ExcerptGenerated = article.ExcerptGenerated
 	b.Content = article.Content
 	b.ImageURL = article.ImageURL
 	b.Views = article.Views
 	b.Status = article.Status
+	b.Seo = NewSeo(article.SeoMeta)
 	b.CreatedAt = article.CreatedAt
 	b.UpdatedAt = article.UpdatedAt
 
 	if !article.PublishedAt.IsZero() {
 		b.PublishedAt = &article.PublishedAt
 	}
+	if !article.UnpublishAt.IsZero() {
+		b.UnpublishAt = &article.UnpublishAt
+	}
 
 	if article.Category != nil {
 		b.Category = &CategoryResponse{
@@ -108,6 +119,9 @@ func (b *BlogArticleList) FromDomain(article *domain.BlogArtikel) {
 	if !article.PublishedAt.IsZero() {
 		b.PublishedAt = &article.PublishedAt
 	}
+	if !article.UnpublishAt.IsZero() {
+		b.UnpublishAt = &article.UnpublishAt
+	}
 
 	if article.Category != nil {
 		b.Category = &CategoryResponse{
@@ -0,0 +1,31 @@
+package response
+
+import "sora_landing_be/cmd/dto"
+
+type (
+	// BlogArchiveYear groups the monthly article counts of a year, newest month first
+	BlogArchiveYear struct {
+		Year   int                `json:"year"`
+		Count  int                `json:"count"`
+		Months []BlogArchiveMonth `json:"months"`
+	}
+
+	BlogArchiveMonth struct {
+		Month int `json:"month"`
+		Count int `json:"count"`
+	}
+)
+
+// NewBlogArchive groups monthly counts, which must be ordered newest first, by year
+func NewBlogArchive(months []dto.ArchiveMonth) []BlogArchiveYear {
+	res := make([]BlogArchiveYear, 0)
+	for _, m := range months {
+		if len(res) == 0 || res[len(res)-1].Year != m.Year {
+			res = append(res, BlogArchiveYear{Year: m.Year})
+		}
+		year := &res[len(res)-1]
+		year.Count += m.Count
+		year.Months = append(year.Months, BlogArchiveMonth{Month: m.Month, Count: m.Count})
+	}
+	return res
+}
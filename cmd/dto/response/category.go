@@ -6,12 +6,17 @@ import (
 )
 
 type CategoryResponse struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Slug      string    `json:"slug"`
-	CreatedBy string    `json:"created_by"`
-	UpdatedBy *string   `json:"updated_by"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description,omitempty"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	ParentID    *string   `json:"parent_id"`
+	Path        string    `json:"path"`
+	CreatedBy   string    `json:"created_by"`
+	UpdatedBy   *string   `json:"updated_by"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Seo         *Seo      `json:"seo,omitempty"`
 }
 
 func ToCategoryResponse(category domain.Category) CategoryResponse {
@@ -20,12 +25,17 @@ func ToCategoryResponse(category domain.Category) CategoryResponse {
 		updatedBy = &category.EditedBy.Name
 	}
 	return CategoryResponse{
-		ID:        category.ID,
-		Name:      category.Name,
-		Slug:      category.Slug,
-		CreatedBy: category.CreatedBy.Name,
-		UpdatedBy: updatedBy,
-		UpdatedAt: category.UpdatedAt,
+		ID:          category.ID,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+		ImageURL:    category.ImageURL,
+		ParentID:    category.ParentID,
+		Path:        category.Path,
+		CreatedBy:   category.CreatedBy.Name,
+		UpdatedBy:   updatedBy,
+		UpdatedAt:   category.UpdatedAt,
+		Seo:         NewSeo(category.SeoMeta),
 	}
 }
 
@@ -37,14 +47,40 @@ func NewListCat(tags []domain.Category) []CategoryResponse {
 			updatedBy = &tag.EditedBy.Name
 		}
 		res = append(res, CategoryResponse{
-			ID:        tag.ID,
-			Name:      tag.Name,
-			Slug:      tag.Slug,
-			CreatedBy: tag.CreatedBy.Name,
-			UpdatedBy: updatedBy,
-			UpdatedAt: tag.UpdatedAt,
+			ID:          tag.ID,
+			Name:        tag.Name,
+			Slug:        tag.Slug,
+			Description: tag.Description,
+			ImageURL:    tag.ImageURL,
+			ParentID:    tag.ParentID,
+			Path:        tag.Path,
+			CreatedBy:   tag.CreatedBy.Name,
+			UpdatedBy:   updatedBy,
+			UpdatedAt:   tag.UpdatedAt,
+			Seo:         NewSeo(tag.SeoMeta),
 		})
 	}
 
 	return res
 }
+
+// CategoryBreadcrumb is one step of the trail from the root category down to an article's category
+type CategoryBreadcrumb struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+	Path string `json:"path"`
+}
+
+func NewCategoryBreadcrumbs(trail []domain.Category) []CategoryBreadcrumb {
+	res := make([]CategoryBreadcrumb, len(trail))
+	for i, c := range trail {
+		res[i] = CategoryBreadcrumb{
+			ID:   c.ID,
+			Name: c.Name,
+			Slug: c.Slug,
+			Path: c.Path,
+		}
+	}
+	return res
+}
@@ -0,0 +1,64 @@
+package response
+
+import (
+	"sora_landing_be/cmd/domain"
+	"time"
+)
+
+type (
+	FeedSubscription struct {
+		ID           string     `json:"id"`
+		URL          string     `json:"url"`
+		Title        string     `json:"title"`
+		CategoryID   *string    `json:"category_id"`
+		CategoryName string     `json:"category_name,omitempty"`
+		TagIDs       []string   `json:"tag_ids"`
+		AutoPublish  bool       `json:"auto_publish"`
+		Active       bool       `json:"active"`
+		LastPolledAt *time.Time `json:"last_polled_at,omitempty"`
+		LastError    string     `json:"last_error,omitempty"`
+		UpdatedAt    time.Time  `json:"updated_at"`
+	}
+
+	// FeedPoll summarizes the outcome of polling a subscription once
+	FeedPoll struct {
+		SubscriptionID string `json:"subscription_id"`
+		NotModified    bool   `json:"not_modified"`
+		Imported       int    `json:"imported"`
+		Duplicates     int    `json:"duplicates"`
+		Failed         int    `json:"failed"`
+		Skipped        int    `json:"skipped"`
+	}
+)
+
+func NewFeedSubscription(sub domain.FeedSubscription) FeedSubscription {
+	res := FeedSubscription{
+		ID:          sub.ID,
+		URL:         sub.URL,
+		Title:       sub.Title,
+		CategoryID:  sub.CategoryID,
+		TagIDs:      sub.TagIDs,
+		AutoPublish: sub.AutoPublish,
+		Active:      sub.Active,
+		LastError:   sub.LastError,
+		UpdatedAt:   sub.UpdatedAt,
+	}
+	if res.TagIDs == nil {
+		res.TagIDs = []string{}
+	}
+	if sub.Category != nil {
+		res.CategoryName = sub.Category.Name
+	}
+	if !sub.LastPolledAt.IsZero() {
+		res.LastPolledAt = &sub.LastPolledAt
+	}
+	return res
+}
+
+func NewListFeedSubscription(subs []domain.FeedSubscription) []FeedSubscription {
+	res := make([]FeedSubscription, 0, len(subs))
+	for _, sub := range subs {
+		res = append(res, NewFeedSubscription(sub))
+	}
+	return res
+}
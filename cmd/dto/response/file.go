@@ -0,0 +1,69 @@
+package response
+
+import (
+	"sora_landing_be/cmd/domain"
+	"time"
+)
+
+type FileUpload struct {
+	ID           string `json:"id"`
+	Filename     string `json:"filename"`
+	Path         string `json:"path"`
+	URL          string `json:"url"`
+	OriginalName string `json:"original_name"`
+	Size         int64  `json:"size"`
+	ContentType  string `json:"content_type"`
+	ContentHash  string `json:"content_hash"`
+	// IsTemporary stays true until an article referencing the file is saved
+	IsTemporary bool `json:"is_temporary"`
+	// Image is set for images variants were generated for
+	Image *ResponsiveImage `json:"image,omitempty"`
+}
+
+func NewFileUpload(file domain.FileUpload, key string) FileUpload {
+	return FileUpload{
+		ID:           file.ID,
+		Filename:     key,
+		Path:         file.FilePath,
+		OriginalName: file.FileName,
+		Size:         file.FileSize,
+		ContentType:  file.ContentType,
+		ContentHash:  file.ContentHash,
+		IsTemporary:  file.IsTemporary,
+	}
+}
+
+// PresignedUpload is the form the client posts the file to, the file goes in a field
+// named file after every entry of Fields. Key is sent to the completion endpoint afterwards.
+type PresignedUpload struct {
+	Key       string            `json:"key"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Fields    map[string]string `json:"fields"`
+	MaxSize   int64             `json:"max_size"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// ResumableUpload is the state of a tus upload, FileID is set once the last chunk arrived
+type ResumableUpload struct {
+	ID        string
+	Offset    int64
+	Length    int64
+	Metadata  string
+	ExpiresAt time.Time
+	FileID    string
+}
+
+func NewResumableUpload(session domain.UploadSession, expiresAt time.Time) ResumableUpload {
+	res := ResumableUpload{
+		ID:        session.ID,
+		Offset:    session.UploadOffset,
+		Length:    session.UploadLength,
+		Metadata:  session.Metadata,
+		ExpiresAt: expiresAt,
+	}
+	if session.FileID != nil {
+		res.FileID = *session.FileID
+	}
+	return res
+}
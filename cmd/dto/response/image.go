@@ -0,0 +1,34 @@
+package response
+
+type (
+	// ResponsiveImage lists the stored sizes of an image, ready for a <picture> element
+	// or the srcset of an <img>
+	ResponsiveImage struct {
+		Src    string `json:"src"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+		// Sources holds one entry per format, WebP first so browsers supporting it pick it
+		Sources []ImageSource `json:"sources"`
+	}
+	ImageSource struct {
+		Type     string         `json:"type"`
+		Srcset   string         `json:"srcset"`
+		Variants []ImageVariant `json:"variants"`
+	}
+	ImageVariant struct {
+		Name   string `json:"name"`
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+		Size   int64  `json:"size"`
+	}
+)
+
+// VariantBackfill counts the files a variant backfill went through
+type VariantBackfill struct {
+	Scanned    int `json:"scanned"`
+	Registered int `json:"registered"` // files found without a record
+	Generated  int `json:"generated"`
+	Skipped    int `json:"skipped"`
+	Failed     int `json:"failed"`
+}
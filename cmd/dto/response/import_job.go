@@ -0,0 +1,70 @@
+package response
+
+import (
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"time"
+)
+
+type (
+	// ExternalImport is the outcome of importing a single external URL
+	ExternalImport struct {
+		ArticleID string                 `json:"article_id"`
+		Slug      string                 `json:"slug"`
+		Status    constants.ImportStatus `json:"status"`
+	}
+
+	// ExternalSync is the outcome of re-syncing an imported article with its source
+	ExternalSync struct {
+		ArticleID string    `json:"article_id"`
+		Changed   bool      `json:"changed"`
+		SyncedAt  time.Time `json:"synced_at"`
+	}
+
+	ImportJob struct {
+		ID         string                 `json:"id"`
+		Status     constants.ImportStatus `json:"status"`
+		AsDraft    bool                   `json:"as_draft"`
+		Total      int                    `json:"total"`
+		Processed  int                    `json:"processed"`
+		CreatedAt  time.Time              `json:"created_at"`
+		FinishedAt *time.Time             `json:"finished_at,omitempty"`
+		Items      []ImportJobItem        `json:"items"`
+	}
+
+	ImportJobItem struct {
+		ID        string                 `json:"id"`
+		URL       string                 `json:"url"`
+		Status    constants.ImportStatus `json:"status"`
+		ArticleID *string                `json:"article_id,omitempty"`
+		Error     string                 `json:"error,omitempty"`
+	}
+)
+
+func NewImportJob(job domain.ImportJob) ImportJob {
+	res := ImportJob{
+		ID:        job.ID,
+		Status:    job.Status,
+		AsDraft:   job.AsDraft,
+		Total:     job.Total,
+		CreatedAt: job.CreatedAt,
+		Items:     make([]ImportJobItem, len(job.Items)),
+	}
+	if !job.FinishedAt.IsZero() {
+		res.FinishedAt = &job.FinishedAt
+	}
+
+	for i, item := range job.Items {
+		res.Items[i] = ImportJobItem{
+			ID:        item.ID,
+			URL:       item.URL,
+			Status:    item.Status,
+			ArticleID: item.ArticleID,
+			Error:     item.Error,
+		}
+		if item.Status != constants.ImportStatusPending && item.Status != constants.ImportStatusProcessing {
+			res.Processed++
+		}
+	}
+	return res
+}
@@ -0,0 +1,63 @@
+package response
+
+import (
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"time"
+)
+
+type (
+	// ArticleLinks is the link audit of one article
+	ArticleLinks struct {
+		ArticleID string                  `json:"article_id"`
+		Title     string                  `json:"title"`
+		Slug      string                  `json:"slug"`
+		Status    constants.ArticleStatus `json:"status"`
+		CheckedAt *time.Time              `json:"checked_at"`
+		Links     []ArticleLink           `json:"links"`
+	}
+	ArticleLink struct {
+		URL        string               `json:"url"`
+		Kind       constants.LinkKind   `json:"kind"`
+		Internal   bool                 `json:"internal"`
+		Status     constants.LinkStatus `json:"status"`
+		StatusCode int                  `json:"status_code,omitempty"`
+		FinalURL   string               `json:"final_url,omitempty"`
+		Error      string               `json:"error,omitempty"`
+		CheckedAt  time.Time            `json:"checked_at"`
+	}
+)
+
+func NewArticleLinks(article domain.BlogArtikel, links []*domain.ArticleLink) ArticleLinks {
+	res := ArticleLinks{
+		ArticleID: article.ID,
+		Title:     article.Title,
+		Slug:      article.Slug,
+		Status:    article.Status,
+		Links:     make([]ArticleLink, 0, len(links)),
+	}
+	if !article.LinksCheckedAt.IsZero() {
+		res.CheckedAt = &article.LinksCheckedAt
+	}
+	for _, link := range links {
+		res.Links = append(res.Links, ArticleLink{
+			URL:        link.URL,
+			Kind:       link.Kind,
+			Internal:   link.Internal,
+			Status:     link.Status,
+			StatusCode: link.StatusCode,
+			FinalURL:   link.FinalURL,
+			Error:      link.Error,
+			CheckedAt:  link.CheckedAt,
+		})
+	}
+	return res
+}
+
+func NewListArticleLinks(articles []domain.BlogArtikel) []ArticleLinks {
+	res := make([]ArticleLinks, 0, len(articles))
+	for _, article := range articles {
+		res = append(res, NewArticleLinks(article, article.Links))
+	}
+	return res
+}
@@ -0,0 +1,98 @@
+package response
+
+import (
+	"path"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto"
+	"time"
+)
+
+type (
+	MediaFile struct {
+		ID           string    `json:"id"`
+		Filename     string    `json:"filename"`
+		Path         string    `json:"path"`
+		URL          string    `json:"url"`
+		OriginalName string    `json:"original_name"`
+		Size         int64     `json:"size"`
+		ContentType  string    `json:"content_type"`
+		ContentHash  string    `json:"content_hash"`
+		Title        string    `json:"title"`
+		AltText      string    `json:"alt_text"`
+		Caption      string    `json:"caption"`
+		IsPublic     bool      `json:"is_public"`
+		IsTemporary  bool      `json:"is_temporary"`
+		Uploader     *User     `json:"uploader,omitempty"`
+		UsageCount   int       `json:"usage_count"`
+		CreatedAt    time.Time `json:"created_at"`
+
+		Image *ResponsiveImage `json:"image,omitempty"`
+	}
+
+	// MediaDetail is a file with the content showing it
+	MediaDetail struct {
+		MediaFile
+		Usages []MediaUsage `json:"usages"`
+	}
+	MediaUsage struct {
+		Type   string                  `json:"type"` // article, category or tag
+		ID     string                  `json:"id"`
+		Title  string                  `json:"title"`
+		Slug   string                  `json:"slug"`
+		Status constants.ArticleStatus `json:"status,omitempty"`
+		Usage  constants.FileUsage     `json:"usage"`
+	}
+)
+
+func NewMediaFile(file domain.FileUpload, usageCount int) MediaFile {
+	res := MediaFile{
+		ID:           file.ID,
+		Filename:     path.Base(file.FilePath),
+		Path:         file.FilePath,
+		OriginalName: file.FileName,
+		Size:         file.FileSize,
+		ContentType:  file.ContentType,
+		ContentHash:  file.ContentHash,
+		Title:        file.Title,
+		AltText:      file.AltText,
+		Caption:      file.Caption,
+		IsPublic:     file.IsPublic,
+		IsTemporary:  file.IsTemporary,
+		UsageCount:   usageCount,
+		CreatedAt:    file.CreatedAt,
+	}
+	if file.Uploader != nil {
+		res.Uploader = &User{
+			ID:   file.Uploader.ID,
+			Name: file.Uploader.Name,
+		}
+	}
+	return res
+}
+
+func NewListMediaFile(files []dto.MediaFile) []MediaFile {
+	res := make([]MediaFile, 0, len(files))
+	for _, file := range files {
+		res = append(res, NewMediaFile(file.FileUpload, file.UsageCount))
+	}
+	return res
+}
+
+func NewMediaDetail(file domain.FileUpload, usages []dto.FileUsage) MediaDetail {
+	res := MediaDetail{
+		MediaFile: NewMediaFile(file, len(usages)),
+		Usages:    make([]MediaUsage, 0, len(usages)),
+	}
+	for _, usage := range usages {
+		res.Usages = append(res.Usages, MediaUsage{
+			Type:   usage.Type,
+			ID:     usage.ID,
+			Title:  usage.Title,
+			Slug:   usage.Slug,
+			Status: usage.Status,
+			Usage:  usage.Usage,
+		})
+	}
+	return res
+}
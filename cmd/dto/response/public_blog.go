@@ -14,6 +14,8 @@ type PublicArticleList struct {
 	ImageURL    string     `json:"image_url"`
 	Views       int64      `json:"views"`
 	PublishedAt *time.Time `json:"published_at"`
+	// Image lists the generated sizes of the cover image
+	Image *ResponsiveImage `json:"image,omitempty"`
 
 	// Simplified related data
 	Category *CategoryResponse   `json:"category"`
@@ -31,14 +33,23 @@ type PublicArticleDetail struct {
 	ImageURL    string     `json:"image_url"`
 	Views       int64      `json:"views"`
 	PublishedAt *time.Time `json:"published_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 	Source      string     `json:"from_url"`
+	Seo         *Seo       `json:"seo"`
+	// Image lists the generated sizes of the cover image
+	Image *ResponsiveImage `json:"image,omitempty"`
 	// Related data
 	Category *CategoryResponse   `json:"category"`
 	Author   *PublicAuthorDetail `json:"author"`
 	Tags     []Tag               `json:"tags"`
+	// Breadcrumbs lead from the root category to the article's category
+	Breadcrumbs []CategoryBreadcrumb `json:"breadcrumbs"`
 
 	// Related articles
 	RelatedArticles []PublicArticleList `json:"related_articles"`
+
+	// StructuredData is the JSON-LD to embed in the page
+	StructuredData *StructuredData `json:"structured_data"`
 }
 
 // PublicAuthorDetail contains non-sensitive author information
@@ -46,6 +57,7 @@ type PublicAuthorDetail struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
 	Username string `json:"username"`
+	Slug     string `json:"slug"`
 }
 
 func (p *PublicArticleList) FromDomain(article *domain.BlogArtikel) {
@@ -64,12 +76,14 @@ func (p *PublicArticleList) FromDomain(article *domain.BlogArtikel) {
 			ID:   article.Category.ID,
 			Name: article.Category.Name,
 			Slug: article.Category.Slug,
+			Path: article.Category.Path,
 		}
 	}
 	if article.Author != nil {
 		p.Author = &PublicAuthorDetail{
 			ID:   article.Author.ID,
 			Name: article.Author.Name,
+			Slug: article.Author.Slug,
 		}
 	}
 	p.Tags = make([]Tag, len(article.Tags))
@@ -91,6 +105,8 @@ func (p *PublicArticleDetail) FromDomain(article *domain.BlogArtikel, relatedArt
 	p.ImageURL = article.ImageURL
 	p.Views = article.Views
 	p.Source = article.Source
+	p.UpdatedAt = article.UpdatedAt
+	p.Seo = NewSeo(article.SeoMeta)
 	if !article.PublishedAt.IsZero() {
 		p.PublishedAt = &article.PublishedAt
 	}
@@ -100,12 +116,14 @@ func (p *PublicArticleDetail) FromDomain(article *domain.BlogArtikel, relatedArt
 			ID:   article.Category.ID,
 			Name: article.Category.Name,
 			Slug: article.Category.Slug,
+			Path: article.Category.Path,
 		}
 	}
 	if article.Author != nil {
 		p.Author = &PublicAuthorDetail{
 			ID:   article.Author.ID,
 			Name: article.Author.Name,
+			Slug: article.Author.Slug,
 		}
 	}
 	p.Tags = make([]Tag, len(article.Tags))
@@ -0,0 +1,21 @@
+package response
+
+import "sora_landing_be/cmd/domain"
+
+type Seo struct {
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+	CanonicalURL    string `json:"canonical_url"`
+	OgImage         string `json:"og_image"`
+	NoIndex         bool   `json:"noindex"`
+}
+
+func NewSeo(seo domain.SeoMeta) *Seo {
+	return &Seo{
+		MetaTitle:       seo.MetaTitle,
+		MetaDescription: seo.MetaDescription,
+		CanonicalURL:    seo.CanonicalURL,
+		OgImage:         seo.OgImage,
+		NoIndex:         seo.NoIndex,
+	}
+}
@@ -0,0 +1,17 @@
+package response
+
+// SharePage holds the metadata rendered into the share page served to social crawlers
+type SharePage struct {
+	Title         string
+	Description   string
+	URL           string
+	CanonicalURL  string
+	Image         string
+	SiteName      string
+	PublishedTime string
+	ModifiedTime  string
+	Author        string
+	Section       string
+	Tags          []string
+	NoIndex       bool
+}
@@ -0,0 +1,73 @@
+package response
+
+// JSON-LD types from https://schema.org, only the properties we fill are declared
+
+const schemaContext = "https://schema.org"
+
+type (
+	// StructuredData is a JSON-LD document holding one or more schema.org nodes
+	StructuredData struct {
+		Context string `json:"@context"`
+		Graph   []any  `json:"@graph"`
+	}
+
+	SchemaBlogPosting struct {
+		Type             string              `json:"@type"`
+		ID               string              `json:"@id,omitempty"`
+		URL              string              `json:"url,omitempty"`
+		Headline         string              `json:"headline"`
+		Description      string              `json:"description,omitempty"`
+		Image            []string            `json:"image,omitempty"`
+		DatePublished    string              `json:"datePublished,omitempty"`
+		DateModified     string              `json:"dateModified,omitempty"`
+		Author           *SchemaThing        `json:"author,omitempty"`
+		Publisher        *SchemaOrganization `json:"publisher,omitempty"`
+		MainEntityOfPage *SchemaThing        `json:"mainEntityOfPage,omitempty"`
+		ArticleSection   string              `json:"articleSection,omitempty"`
+		Keywords         []string            `json:"keywords,omitempty"`
+		IsBasedOn        string              `json:"isBasedOn,omitempty"`
+		InLanguage       string              `json:"inLanguage,omitempty"`
+	}
+
+	// SchemaThing is a minimal node such as a Person or WebPage
+	SchemaThing struct {
+		Type string `json:"@type"`
+		ID   string `json:"@id,omitempty"`
+		Name string `json:"name,omitempty"`
+		URL  string `json:"url,omitempty"`
+	}
+
+	SchemaOrganization struct {
+		Type string `json:"@type"`
+		Name string `json:"name"`
+		URL  string `json:"url,omitempty"`
+	}
+
+	SchemaBreadcrumbList struct {
+		Type            string           `json:"@type"`
+		ItemListElement []SchemaListItem `json:"itemListElement"`
+	}
+
+	SchemaItemList struct {
+		Type            string           `json:"@type"`
+		Name            string           `json:"name,omitempty"`
+		URL             string           `json:"url,omitempty"`
+		NumberOfItems   int              `json:"numberOfItems"`
+		ItemListElement []SchemaListItem `json:"itemListElement"`
+	}
+
+	SchemaListItem struct {
+		Type     string `json:"@type"`
+		Position int    `json:"position"`
+		Name     string `json:"name,omitempty"`
+		Item     string `json:"item,omitempty"`
+		URL      string `json:"url,omitempty"`
+	}
+)
+
+func NewStructuredData(nodes ...any) *StructuredData {
+	return &StructuredData{
+		Context: schemaContext,
+		Graph:   nodes,
+	}
+}
@@ -7,12 +7,15 @@ import (
 
 type (
 	Tag struct {
-		ID        string    `json:"id"`
-		Name      string    `json:"name"`
-		Slug      string    `json:"slug"`
-		CreatedBy string    `json:"created_by"`
-		UpdatedBy *string   `json:"updated_by"`
-		UpdatedAt time.Time `json:"updated_at"`
+		ID          string    `json:"id"`
+		Name        string    `json:"name"`
+		Slug        string    `json:"slug"`
+		Description string    `json:"description,omitempty"`
+		ImageURL    string    `json:"image_url,omitempty"`
+		CreatedBy   string    `json:"created_by"`
+		UpdatedBy   *string   `json:"updated_by"`
+		UpdatedAt   time.Time `json:"updated_at"`
+		Seo         *Seo      `json:"seo,omitempty"`
 	}
 )
 
@@ -24,12 +27,15 @@ func NewListTag(tags []domain.Tag) []Tag {
 			updatedBy = &tag.EditedBy.Name
 		}
 		res = append(res, Tag{
-			ID:        tag.ID,
-			Name:      tag.Name,
-			Slug:      tag.Slug,
-			CreatedBy: tag.CreatedBy.Name,
-			UpdatedBy: updatedBy,
-			UpdatedAt: tag.UpdatedAt,
+			ID:          tag.ID,
+			Name:        tag.Name,
+			Slug:        tag.Slug,
+			Description: tag.Description,
+			ImageURL:    tag.ImageURL,
+			CreatedBy:   tag.CreatedBy.Name,
+			UpdatedBy:   updatedBy,
+			UpdatedAt:   tag.UpdatedAt,
+			Seo:         NewSeo(tag.SeoMeta),
 		})
 	}
 
@@ -43,12 +49,15 @@ func NewListTags(tags []*domain.Tag) []*Tag {
 			updatedBy = &tag.EditedBy.Name
 		}
 		res = append(res, &Tag{
-			ID:        tag.ID,
-			Name:      tag.Name,
-			Slug:      tag.Slug,
-			CreatedBy: tag.CreatedBy.Name,
-			UpdatedBy: updatedBy,
-			UpdatedAt: tag.UpdatedAt,
+			ID:          tag.ID,
+			Name:        tag.Name,
+			Slug:        tag.Slug,
+			Description: tag.Description,
+			ImageURL:    tag.ImageURL,
+			CreatedBy:   tag.CreatedBy.Name,
+			UpdatedBy:   updatedBy,
+			UpdatedAt:   tag.UpdatedAt,
+			Seo:         NewSeo(tag.SeoMeta),
 		})
 	}
 
@@ -61,12 +70,42 @@ func NewTag(tag domain.Tag) Tag {
 		updatedBy = &tag.EditedBy.Name
 	}
 	return Tag{
-		ID:        tag.ID,
-		Name:      tag.Name,
-		Slug:      tag.Slug,
-		CreatedBy: tag.CreatedBy.Name,
-		UpdatedBy: updatedBy,
-		UpdatedAt: tag.UpdatedAt,
+		ID:          tag.ID,
+		Name:        tag.Name,
+		Slug:        tag.Slug,
+		Description: tag.Description,
+		ImageURL:    tag.ImageURL,
+		CreatedBy:   tag.CreatedBy.Name,
+		UpdatedBy:   updatedBy,
+		UpdatedAt:   tag.UpdatedAt,
+		Seo:         NewSeo(tag.SeoMeta),
+	}
+
+}
+
+type (
+	// TagMerge summarizes a merge, Retagged counts articles that gained the target tag
+	TagMerge struct {
+		Target   Tag      `json:"target"`
+		Merged   []string `json:"merged"`
+		Retagged int      `json:"retagged"`
 	}
 
+	TagSynonym struct {
+		ID        string    `json:"id"`
+		Name      string    `json:"name"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+)
+
+func NewTagSynonyms(synonyms []domain.TagSynonym) []TagSynonym {
+	res := make([]TagSynonym, len(synonyms))
+	for i, s := range synonyms {
+		res[i] = TagSynonym{
+			ID:        s.ID,
+			Name:      s.Name,
+			CreatedAt: s.CreatedAt,
+		}
+	}
+	return res
 }
@@ -0,0 +1,21 @@
+package response
+
+type (
+	TagSuggestions struct {
+		Tags     []SuggestedTag     `json:"tags"`
+		Keywords []SuggestedKeyword `json:"keywords"`
+	}
+	SuggestedTag struct {
+		ID    string  `json:"id"`
+		Name  string  `json:"name"`
+		Slug  string  `json:"slug"`
+		Score float64 `json:"score"`
+		// MatchedBy is the tag name or synonym found in the content
+		MatchedBy string `json:"matched_by"`
+	}
+	// SuggestedKeyword is a frequent term no existing tag covers, a candidate for a new tag
+	SuggestedKeyword struct {
+		Keyword string  `json:"keyword"`
+		Score   float64 `json:"score"`
+	}
+)
@@ -0,0 +1,82 @@
+package response
+
+import (
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto"
+)
+
+type (
+	PublicCategory struct {
+		ID           string  `json:"id"`
+		Name         string  `json:"name"`
+		Slug         string  `json:"slug"`
+		Path         string  `json:"path"`
+		ParentID     *string `json:"parent_id"`
+		Description  string  `json:"description"`
+		ImageURL     string  `json:"image_url"`
+		ArticleCount int     `json:"article_count"`
+		Seo          *Seo    `json:"seo"`
+	}
+
+	// PublicCategoryDetail backs a category landing page
+	PublicCategoryDetail struct {
+		PublicCategory
+		Breadcrumbs []CategoryBreadcrumb `json:"breadcrumbs"`
+		Children    []PublicCategory     `json:"children"`
+	}
+
+	PublicTag struct {
+		ID           string `json:"id"`
+		Name         string `json:"name"`
+		Slug         string `json:"slug"`
+		Description  string `json:"description"`
+		ImageURL     string `json:"image_url"`
+		ArticleCount int    `json:"article_count"`
+		// Weight is only set in tag cloud mode, from 1 for the least used tags up to TagCloudLevels
+		Weight int  `json:"weight,omitempty"`
+		Seo    *Seo `json:"seo"`
+	}
+)
+
+func NewPublicCategory(c dto.CategoryCount) PublicCategory {
+	return PublicCategory{
+		ID:           c.ID,
+		Name:         c.Name,
+		Slug:         c.Slug,
+		Path:         c.Path,
+		ParentID:     c.ParentID,
+		Description:  c.Description,
+		ImageURL:     c.ImageURL,
+		ArticleCount: c.ArticleCount,
+		Seo:          NewSeo(c.SeoMeta),
+	}
+}
+
+func NewPublicTag(t dto.TagCount) PublicTag {
+	return PublicTag{
+		ID:           t.ID,
+		Name:         t.Name,
+		Slug:         t.Slug,
+		Description:  t.Description,
+		ImageURL:     t.ImageURL,
+		ArticleCount: t.ArticleCount,
+		Seo:          NewSeo(t.SeoMeta),
+	}
+}
+
+// NewPublicCategoryDetail builds a landing page from the category, its ancestors (root first) and its children
+func NewPublicCategoryDetail(category dto.CategoryCount, ancestors, children []dto.CategoryCount) PublicCategoryDetail {
+	trail := make([]domain.Category, 0, len(ancestors)+1)
+	for _, c := range ancestors {
+		trail = append(trail, c.Category)
+	}
+	res := PublicCategoryDetail{
+		PublicCategory: NewPublicCategory(category),
+		Breadcrumbs:    NewCategoryBreadcrumbs(append(trail, category.Category)),
+		Children:       make([]PublicCategory, len(children)),
+	}
+	for i, c := range children {
+		res.Children[i] = NewPublicCategory(c)
+	}
+	return res
+}
@@ -9,6 +9,7 @@ type (
 	User struct {
 		ID        string    `json:"id"`
 		Name      string    `json:"name"`
+		Slug      string    `json:"slug"`
 		Email     string    `json:"email"`
 		CreatedAt time.Time `json:"created_at"`
 		UpdatedAt time.Time `json:"updated_at"`
@@ -36,6 +37,7 @@ func NewListUser(users []domain.User) []User {
 		res = append(res, User{
 			ID:        user.ID,
 			Name:      user.Name,
+			Slug:      user.Slug,
 			Email:     user.Email,
 			CreatedAt: user.CreatedAt,
 			UpdatedAt: user.UpdatedAt,
@@ -49,6 +51,7 @@ func NewUser(user domain.User) User {
 	return User{
 		ID:        user.ID,
 		Name:      user.Name,
+		Slug:      user.Slug,
 		Email:     user.Email,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
@@ -0,0 +1,15 @@
+package dto
+
+import "sora_landing_be/cmd/domain"
+
+// CategoryCount is a category with the number of published articles in it and its subcategories
+type CategoryCount struct {
+	domain.Category `bun:",extend"`
+	ArticleCount    int `bun:"article_count"`
+}
+
+// TagCount is a tag with the number of published articles carrying it
+type TagCount struct {
+	domain.Tag   `bun:",extend"`
+	ArticleCount int `bun:"article_count"`
+}
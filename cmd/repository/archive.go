@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/pkg/database"
+
+	"github.com/uptrace/bun"
+)
+
+type ArchiveRepository interface {
+	// Export
+	ListAllArticles(ctx context.Context) ([]domain.BlogArtikel, error)
+	ListAllCategories(ctx context.Context) ([]domain.Category, error)
+	ListAllTags(ctx context.Context) ([]domain.Tag, error)
+	ListUsersByIDs(ctx context.Context, ids []string) ([]domain.User, error)
+
+	// Import
+	IsContentEmpty(ctx context.Context) (bool, error)
+	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	UserExists(ctx context.Context, id string) (bool, error)
+	UserSlugExists(ctx context.Context, slug string) (bool, error)
+	InsertUser(ctx context.Context, data *domain.User) error
+	InsertCategories(ctx context.Context, data []domain.Category) error
+	InsertTags(ctx context.Context, data []domain.Tag) error
+	InsertArticle(ctx context.Context, data *domain.BlogArtikel) error
+	InsertArticleTags(ctx context.Context, data []domain.ArticleTag) error
+}
+
+type archiveRepository struct {
+	db *database.Database
+}
+
+func NewArchiveRepository(db *database.Database) ArchiveRepository {
+	return &archiveRepository{
+		db: db,
+	}
+}
+
+func (r *archiveRepository) ListAllArticles(ctx context.Context) ([]domain.BlogArtikel, error) {
+	var res []domain.BlogArtikel
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Relation("Tags").
+		Order("ba.created_at ASC").
+		Scan(ctx)
+	return res, err
+}
+
+func (r *archiveRepository) ListAllCategories(ctx context.Context) ([]domain.Category, error) {
+	var res []domain.Category
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Order("category.created_at ASC").
+		Scan(ctx)
+	return res, err
+}
+
+func (r *archiveRepository) ListAllTags(ctx context.Context) ([]domain.Tag, error) {
+	var res []domain.Tag
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Order("tag.created_at ASC").
+		Scan(ctx)
+	return res, err
+}
+
+func (r *archiveRepository) ListUsersByIDs(ctx context.Context, ids []string) ([]domain.User, error) {
+	var res []domain.User
+	if len(ids) == 0 {
+		return res, nil
+	}
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Where(`"user"."id" IN (?)`, bun.In(ids)).
+		Scan(ctx)
+	return res, err
+}
+
+func (r *archiveRepository) IsContentEmpty(ctx context.Context) (bool, error) {
+	var total int
+	err := r.db.InitQuery(ctx).
+		NewRaw(`SELECT
+			(SELECT COUNT(*) FROM blog_artikels) +
+			(SELECT COUNT(*) FROM categories) +
+			(SELECT COUNT(*) FROM tags)`).
+		Scan(ctx, &total)
+	return total == 0, err
+}
+
+func (r *archiveRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	var res []domain.User
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Where("email = ?", email).
+		Limit(1).
+		Scan(ctx)
+	if err != nil || len(res) == 0 {
+		return nil, err
+	}
+	return &res[0], nil
+}
+
+func (r *archiveRepository) UserExists(ctx context.Context, id string) (bool, error) {
+	return r.db.InitQuery(ctx).
+		NewSelect().
+		Model((*domain.User)(nil)).
+		Where("id = ?", id).
+		Exists(ctx)
+}
+
+func (r *archiveRepository) UserSlugExists(ctx context.Context, slug string) (bool, error) {
+	return r.db.InitQuery(ctx).
+		NewSelect().
+		Model((*domain.User)(nil)).
+		Where("slug = ?", slug).
+		Exists(ctx)
+}
+
+func (r *archiveRepository) InsertUser(ctx context.Context, data *domain.User) error {
+	_, err := r.db.InitQuery(ctx).NewInsert().Model(data).Returning("id").Exec(ctx)
+	return err
+}
+
+func (r *archiveRepository) InsertCategories(ctx context.Context, data []domain.Category) error {
+	if len(data) == 0 {
+		return nil
+	}
+	_, err := r.db.InitQuery(ctx).NewInsert().Model(&data).Returning("id").Exec(ctx)
+	return err
+}
+
+func (r *archiveRepository) InsertTags(ctx context.Context, data []domain.Tag) error {
+	if len(data) == 0 {
+		return nil
+	}
+	_, err := r.db.InitQuery(ctx).NewInsert().Model(&data).Returning("id").Exec(ctx)
+	return err
+}
+
+func (r *archiveRepository) InsertArticle(ctx context.Context, data *domain.BlogArtikel) error {
+	_, err := r.db.InitQuery(ctx).NewInsert().Model(data).Returning("id").Exec(ctx)
+	return err
+}
+
+func (r *archiveRepository) InsertArticleTags(ctx context.Context, data []domain.ArticleTag) error {
+	if len(data) == 0 {
+		return nil
+	}
+	_, err := r.db.InitQuery(ctx).
+		NewInsert().
+		Model(&data).
+		On("CONFLICT DO NOTHING").
+		Exec(ctx)
+	return err
+}
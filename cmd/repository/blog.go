@@ -12,8 +12,11 @@ import (
 	"sora_landing_be/pkg/database"
 	"sora_landing_be/pkg/errors"
 	"sora_landing_be/pkg/logger"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/uptrace/bun"
 	"go.uber.org/zap"
 )
 
@@ -22,6 +25,10 @@ type BlogRepository interface {
 	CreateArticle(ctx context.Context, data *domain.BlogArtikel) error
 	CreateArticlefromURL(ctx context.Context, data *domain.BlogArtikel) error
 	UpdateArticle(ctx context.Context, data *domain.BlogArtikel) error
+	UpdateArticleSeo(ctx context.Context, id string, seo domain.SeoMeta, generated domain.SeoGenerated) error
+	UpdateArticleExcerpt(ctx context.Context, id, excerpt string, generated bool) error
+	UpdateArticleUnpublishAt(ctx context.Context, id string, unpublishAt time.Time) error
+	ListExpiredArticles(ctx context.Context, now time.Time) ([]domain.BlogArtikel, error)
 	UpdateArticleStatus(ctx context.Context, id string, status constants.ArticleStatus, publishAt *time.Time) error
 	IncrementViews(ctx context.Context, id string) error
 	SlugExists(ctx context.Context, slug string) (bool, error)
@@ -29,13 +36,17 @@ type BlogRepository interface {
 	// Read operations
 	GetArticle(ctx context.Context, id string) (domain.BlogArtikel, error)
 	GetArticleBySlug(ctx context.Context, slug string) (domain.BlogArtikel, error)
+	GetArticleBySource(ctx context.Context, sources ...string) (*domain.BlogArtikel, error)
 	ListArticles(ctx context.Context, req requests.ListArtikel) ([]domain.BlogArtikel, int, error)
 	GetArticleStats(ctx context.Context) (dto.BlogStats, error)
 
 	// Public endpoints
 	ListPublicArticles(ctx context.Context, req requests.ListArtikel) ([]domain.BlogArtikel, int, error)
+	ListPublicArticlesCursor(ctx context.Context, req requests.ListArtikel, cursor *dto.Cursor, limit int) ([]domain.BlogArtikel, error)
 	GetPublicArticleWithRelated(ctx context.Context, slug string) (article domain.BlogArtikel, related []domain.BlogArtikel, err error)
 	GetFeaturedArticle(ctx context.Context) ([]domain.BlogArtikel, error)
+	CountPublishedByMonth(ctx context.Context, timezone string) ([]dto.ArchiveMonth, error)
+	ListPublishedTexts(ctx context.Context) ([]domain.BlogArtikel, error)
 
 	// Tag related operations
 	AddArticleTags(ctx context.Context, articleID string, tagIDs []string) error
@@ -92,6 +103,61 @@ func (r *blogRepository) UpdateArticle(ctx context.Context, data *domain.BlogArt
 	return err
 }
 
+// UpdateArticleExcerpt overwrites the excerpt and whether it was generated, including empty values
+func (r *blogRepository) UpdateArticleExcerpt(ctx context.Context, id, excerpt string, generated bool) error {
+	_, err := r.db.InitQuery(ctx).NewUpdate().
+		Table("blog_artikels").
+		Set("excerpt = NULLIF(?, '')", excerpt).
+		Set("excerpt_generated = ?", generated).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+// UpdateArticleUnpublishAt sets the expiry of an article, a zero time removes it
+func (r *blogRepository) UpdateArticleUnpublishAt(ctx context.Context, id string, unpublishAt time.Time) error {
+	_, err := r.db.InitQuery(ctx).NewUpdate().
+		Table("blog_artikels").
+		Set("unpublish_at = ?", bun.NullTime{Time: unpublishAt}).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+// ListExpiredArticles returns the public articles whose unpublish date has passed
+func (r *blogRepository) ListExpiredArticles(ctx context.Context, now time.Time) ([]domain.BlogArtikel, error) {
+	var res []domain.BlogArtikel
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Column("ba.id", "ba.featured").
+		Where("ba.status IN (?)", bun.In([]constants.ArticleStatus{constants.StatusPublished, constants.StatusScheduled})).
+		Where("ba.unpublish_at <= ?", now).
+		Order("ba.unpublish_at ASC").
+		Scan(ctx)
+	return res, err
+}
+
+// UpdateArticleSeo overwrites all SEO fields, including empty ones, and which of them were generated
+func (r *blogRepository) UpdateArticleSeo(ctx context.Context, id string, seo domain.SeoMeta, generated domain.SeoGenerated) error {
+	_, err := r.db.InitQuery(ctx).NewUpdate().
+		Table("blog_artikels").
+		Set("meta_title = NULLIF(?, '')", seo.MetaTitle).
+		Set("meta_description = NULLIF(?, '')", seo.MetaDescription).
+		Set("canonical_url = NULLIF(?, '')", seo.CanonicalURL).
+		Set("og_image = NULLIF(?, '')", seo.OgImage).
+		Set("noindex = ?", seo.NoIndex).
+		Set("meta_title_generated = ?", generated.MetaTitleGenerated).
+		Set("meta_description_generated = ?", generated.MetaDescriptionGenerated).
+		Set("og_image_generated = ?", generated.OgImageGenerated).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
 func (r *blogRepository) UpdateArticleStatus(ctx context.Context, id string, status constants.ArticleStatus, publishAt *time.Time) error {
 	query := r.db.InitQuery(ctx).NewUpdate().
 		Table("blog_artikels").
@@ -140,6 +206,23 @@ func (r *blogRepository) GetArticleBySlug(ctx context.Context, slug string) (res
 	return res, err
 }
 
+func (r *blogRepository) GetArticleBySource(ctx context.Context, sources ...string) (*domain.BlogArtikel, error) {
+	var res domain.BlogArtikel
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Where("ba.source IN (?)", bun.In(sources)).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if global_err.Is(err, sql.ErrNoRows) {
+			return nil, nil // not imported yet
+		}
+		return nil, err
+	}
+	return &res, nil
+}
+
 func (r *blogRepository) SlugExists(ctx context.Context, slug string) (bool, error) {
 	return r.db.InitQuery(ctx).
 		NewSelect().
@@ -167,15 +250,12 @@ func (r *blogRepository) ListArticles(ctx context.Context, req requests.ListArti
 	if req.CategoryID != "" {
 		q.Where("category_id = ?", req.CategoryID)
 	}
-	if req.TagID != "" {
-		q.Join("JOIN article_tags at ON at.blog_article_id = blog_artikels.id").
-			Where("at.tag_id = ?", req.TagID)
-	}
+	applyTagFilters(q, req)
 	if req.Status != "" {
 		q.Where("ba.status = ?", req.Status)
 	}
 	if req.Search != "" {
-		q.Where("ba.title ILIKE ? OR ba.content ILIKE ?",
+		q.Where("(ba.title ILIKE ? OR ba.content ILIKE ?)",
 			fmt.Sprintf("%%%s%%", req.Search),
 			fmt.Sprintf("%%%s%%", req.Search))
 	}
@@ -233,7 +313,8 @@ func (r *blogRepository) AddArticleTags(ctx context.Context, articleID string, t
 
 	_, err := r.db.InitQuery(ctx).NewRaw(`
 		INSERT INTO article_tags (blog_article_id, tag_id)
-		VALUES `+placeholders[0], values...).
+		VALUES `+strings.Join(placeholders, ", ")+`
+		ON CONFLICT DO NOTHING`, values...).
 		Exec(ctx)
 
 	return err
@@ -302,32 +383,8 @@ func (r *blogRepository) ListPublicArticles(ctx context.Context, req requests.Li
 		Model(&res).
 		Relation("Category").
 		Relation("Author").
-		Relation("Tags").
-		Where("ba.featured IS NULL").
-		Where("ba.status = ? ", constants.StatusPublished)
-
-	// Apply filters
-	if req.CategoryID != "" {
-		q.Where("category_id = ?", req.CategoryID)
-	}
-	if req.TagID != "" {
-		q.Join("JOIN article_tags at ON at.blog_article_id = blog_artikels.id").
-			Where("at.tag_id = ?", req.TagID)
-	}
-	if req.Status != "" {
-		q.Where("status = ?", req.Status)
-	}
-	if req.Search != "" {
-		q.Where("title ILIKE ? OR content ILIKE ?",
-			fmt.Sprintf("%%%s%%", req.Search),
-			fmt.Sprintf("%%%s%%", req.Search))
-	}
-	if req.StartDate != nil {
-		q.Where("created_at >= ?", req.StartDate)
-	}
-	if req.EndDate != nil {
-		q.Where("created_at <= ?", req.EndDate)
-	}
+		Relation("Tags")
+	applyPublicArticleFilters(q, req)
 
 	// Apply sorting
 	order := "DESC"
@@ -338,7 +395,7 @@ func (r *blogRepository) ListPublicArticles(ctx context.Context, req requests.Li
 			order = "ASC"
 		}
 	}
-	q.Order(fmt.Sprintf("%s %s", orderBy, order))
+	q.Order(fmt.Sprintf("ba.%s %s", orderBy, order))
 
 	// Apply pagination
 	q.Limit(req.PageSize).
@@ -348,6 +405,189 @@ func (r *blogRepository) ListPublicArticles(ctx context.Context, req requests.Li
 	return res, total, err
 }
 
+// ListPublicArticlesCursor returns up to limit articles after the cursor (or before it when paging backward),
+// ordered in the scan direction with the id as tie breaker
+func (r *blogRepository) ListPublicArticlesCursor(ctx context.Context, req requests.ListArtikel, cursor *dto.Cursor, limit int) ([]domain.BlogArtikel, error) {
+	var res []domain.BlogArtikel
+
+	column, ok := publicCursorColumns[req.SortBy]
+	if !ok {
+		return nil, dto.ErrInvalidCursor
+	}
+	desc := req.SortOrder != "asc"
+
+	q := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Relation("Category").
+		Relation("Author").
+		Relation("Tags")
+	applyPublicArticleFilters(q, req)
+
+	if cursor != nil {
+		if cursor.Backward {
+			desc = !desc
+		}
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		value, err := cursorValue(req.SortBy, cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+		q.Where(fmt.Sprintf("(%s, ba.id) %s (?, ?)", column, op), value, cursor.ID)
+	}
+
+	order := "ASC"
+	if desc {
+		order = "DESC"
+	}
+	err := q.OrderExpr(fmt.Sprintf("%s %s, ba.id %s", column, order, order)).
+		Limit(limit).
+		Scan(ctx)
+	return res, err
+}
+
+// publicCursorColumns are the sort keys allowed for keyset pagination, published_at falls back to
+// created_at so rows without a publish date still get a stable position
+var publicCursorColumns = map[string]string{
+	"published_at": "COALESCE(ba.published_at, ba.created_at)",
+	"created_at":   "ba.created_at",
+	"views":        "ba.views",
+	"title":        "ba.title",
+}
+
+// cursorValue converts the encoded boundary value back to the type of its sort key
+func cursorValue(sortBy, value string) (any, error) {
+	switch sortBy {
+	case "published_at", "created_at":
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, dto.ErrInvalidCursor
+		}
+		return t, nil
+	case "views":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, dto.ErrInvalidCursor
+		}
+		return n, nil
+	}
+	return value, nil
+}
+
+// notExpired hides articles past their unpublish date, readers must not see them in the
+// time between the expiry and the job archiving them
+const notExpired = "(ba.unpublish_at IS NULL OR ba.unpublish_at > NOW())"
+
+// applyPublicArticleFilters restricts a query on published, non featured articles to the requested filters
+func applyPublicArticleFilters(q *bun.SelectQuery, req requests.ListArtikel) {
+	q.Where("ba.status = ?", constants.StatusPublished).Where(notExpired)
+	if !req.IncludeFeatured {
+		q.Where("ba.featured IS NULL")
+	}
+
+	if req.CategoryID != "" {
+		applyCategoryFilter(q, "id", req.CategoryID, req.IncludeDescendants)
+	}
+	if req.Category != "" {
+		applyCategoryFilter(q, "slug", req.Category, req.IncludeDescendants)
+	}
+	if req.Author != "" {
+		q.Where("ba.author_id IN (SELECT u.id FROM users u WHERE u.slug = ?)", req.Author)
+	}
+	applyTagFilters(q, req)
+	if req.Status != "" {
+		q.Where("ba.status = ?", req.Status)
+	}
+	if req.Search != "" {
+		q.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("ba.title ILIKE ?", fmt.Sprintf("%%%s%%", req.Search)).
+				WhereOr("ba.content ILIKE ?", fmt.Sprintf("%%%s%%", req.Search))
+		})
+	}
+	if req.StartDate != nil {
+		q.Where("ba.created_at >= ?", req.StartDate)
+	}
+	if req.EndDate != nil {
+		q.Where("ba.created_at <= ?", req.EndDate)
+	}
+	if req.PublishedFrom != nil {
+		q.Where("ba.published_at >= ?", req.PublishedFrom)
+	}
+	if req.PublishedTo != nil {
+		q.Where("ba.published_at <= ?", req.PublishedTo)
+	}
+}
+
+// applyCategoryFilter restricts articles to the category matching column = value, and optionally to
+// every category below it in the tree
+func applyCategoryFilter(q *bun.SelectQuery, column, value string, descendants bool) {
+	scope := "c.id = p.id"
+	if descendants {
+		scope = "(c.id = p.id OR c.path LIKE p.path || '/%')"
+	}
+	q.Where(`ba.category_id IN (SELECT c.id FROM categories c
+		JOIN categories p ON `+scope+`
+		WHERE p.`+column+` = ? AND p.deleted_at IS NULL AND c.deleted_at IS NULL)`, value)
+}
+
+// applyTagFilters matches tags with correlated subqueries on article_tags rather than joins,
+// so an article carrying several of the requested tags is still returned (and counted) once
+func applyTagFilters(q *bun.SelectQuery, req requests.ListArtikel) {
+	if req.TagID != "" {
+		q.Where("EXISTS (SELECT 1 FROM article_tags at WHERE at.blog_article_id = ba.id AND at.tag_id = ?)", req.TagID)
+	}
+	if slugs := req.TagSlugs(); len(slugs) > 0 {
+		if req.MatchAllTags() {
+			q.Where(`(SELECT COUNT(DISTINCT t.id) FROM article_tags at
+				JOIN tags t ON t.id = at.tag_id AND t.deleted_at IS NULL
+				WHERE at.blog_article_id = ba.id AND t.slug IN (?)) = ?`, bun.In(slugs), len(slugs))
+		} else {
+			q.Where(`EXISTS (SELECT 1 FROM article_tags at
+				JOIN tags t ON t.id = at.tag_id AND t.deleted_at IS NULL
+				WHERE at.blog_article_id = ba.id AND t.slug IN (?))`, bun.In(slugs))
+		}
+	}
+	if slugs := req.ExcludedTagSlugs(); len(slugs) > 0 {
+		q.Where(`NOT EXISTS (SELECT 1 FROM article_tags at
+			JOIN tags t ON t.id = at.tag_id
+			WHERE at.blog_article_id = ba.id AND t.slug IN (?))`, bun.In(slugs))
+	}
+}
+
+// CountPublishedByMonth counts published articles per calendar month of the given timezone, newest first
+func (r *blogRepository) CountPublishedByMonth(ctx context.Context, timezone string) ([]dto.ArchiveMonth, error) {
+	var res []dto.ArchiveMonth
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model((*domain.BlogArtikel)(nil)).
+		ColumnExpr("EXTRACT(YEAR FROM ba.published_at AT TIME ZONE ?)::int AS year", timezone).
+		ColumnExpr("EXTRACT(MONTH FROM ba.published_at AT TIME ZONE ?)::int AS month", timezone).
+		ColumnExpr("COUNT(*) AS count").
+		Where("ba.status = ?", constants.StatusPublished).
+		Where(notExpired).
+		Where("ba.published_at IS NOT NULL").
+		GroupExpr("year, month").
+		OrderExpr("year DESC, month DESC").
+		Scan(ctx, &res)
+	return res, err
+}
+
+// ListPublishedTexts loads only the title and content of every published article
+func (r *blogRepository) ListPublishedTexts(ctx context.Context) ([]domain.BlogArtikel, error) {
+	var res []domain.BlogArtikel
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Column("ba.id", "ba.title", "ba.content").
+		Where("ba.status = ?", constants.StatusPublished).
+		Where(notExpired).
+		Scan(ctx)
+	return res, err
+}
+
 func (r *blogRepository) GetPublicArticleWithRelated(ctx context.Context, slug string) (article domain.BlogArtikel, related []domain.BlogArtikel, err error) {
 	// Get the main article
 	err = r.db.InitQuery(ctx).
@@ -358,26 +598,19 @@ func (r *blogRepository) GetPublicArticleWithRelated(ctx context.Context, slug s
 		Relation("Tags").
 		Where(`"ba"."slug" = ?`, slug).
 		Where("ba.status = ?", constants.StatusPublished).
+		Where(notExpired).
 		Scan(ctx)
 	if err != nil {
 		return article, nil, err
 	}
 
 	// Get related articles based on category and tags
-	tagConditions := []string{"category_id = ?"}
-	params := []interface{}{article.CategoryID}
-
-	// Add tag conditions if article has tags
-	if len(article.Tags) > 0 {
-		tagIDs := make([]string, len(article.Tags))
-		for i, tag := range article.Tags {
-			tagIDs[i] = tag.ID
-		}
-		tagConditions = append(tagConditions, "EXISTS (SELECT 1 FROM article_tags at WHERE at.blog_article_id = ba.id AND at.tag_id IN (?))")
-		params = append(params, tagIDs)
+	var tagIDs []string
+	for _, tag := range article.Tags {
+		tagIDs = append(tagIDs, tag.ID)
 	}
 
-	// Build the query
+	// Build the query, sharing the category or a tag never lifts the publication filters
 	q := r.db.InitQuery(ctx).
 		NewSelect().
 		Model(&related).
@@ -388,12 +621,14 @@ func (r *blogRepository) GetPublicArticleWithRelated(ctx context.Context, slug s
 		Where("ba.status = ?", constants.StatusPublished).
 		Where("ba.deleted_at IS NULL").
 		Where("ba.published_at <= ?", time.Now()).
-		WhereOr(tagConditions[0], params[0])
-
-	// Add tag conditions if present
-	if len(tagConditions) > 1 {
-		q.WhereOr(tagConditions[1], params[1])
-	}
+		Where(notExpired).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			q = q.Where("ba.category_id = ?", article.CategoryID)
+			if len(tagIDs) > 0 {
+				q = q.WhereOr("EXISTS (SELECT 1 FROM article_tags at WHERE at.blog_article_id = ba.id AND at.tag_id IN (?))", bun.In(tagIDs))
+			}
+			return q
+		})
 
 	// Order by publish date and limit to 2 articles
 	q.Order("published_at DESC").
@@ -416,6 +651,7 @@ func (r *blogRepository) GetFeaturedArticle(ctx context.Context) ([]domain.BlogA
 		Relation("Author").
 		Relation("Tags").
 		Where("ba.featured IS NOT NULL").
+		Where(notExpired).
 		Order("ba.featured ASC").
 		Limit(3).
 		Scan(ctx)
@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"slices"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto/requests"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// filterQuery renders the public listing query for req, sql.Open does not connect so no database is needed
+func filterQuery(t *testing.T, req requests.ListArtikel, apply func(*bun.SelectQuery, requests.ListArtikel)) string {
+	t.Helper()
+	sqlDB, err := sql.Open("postgres", "postgres://test@localhost/test?sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	db := bun.NewDB(sqlDB, pgdialect.New())
+	db.RegisterModel((*domain.ArticleTag)(nil))
+
+	q := db.NewSelect().Model((*domain.BlogArtikel)(nil))
+	apply(q, req)
+	query := q.String()
+	// the filters follow WHERE, compare them with whitespace collapsed
+	_, where, _ := strings.Cut(query, " WHERE ")
+	return strings.Join(strings.Fields(where), " ")
+}
+
+func TestApplyTagFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     requests.ListArtikel
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "no tags",
+			req:     requests.ListArtikel{},
+			notWant: []string{"article_tags"},
+		},
+		{
+			name:    "any tag",
+			req:     requests.ListArtikel{Tags: "go, News"},
+			want:    []string{"EXISTS (SELECT 1 FROM article_tags at JOIN tags t ON t.id = at.tag_id AND t.deleted_at IS NULL WHERE at.blog_article_id = ba.id AND t.slug IN ('go', 'news'))"},
+			notWant: []string{"COUNT(DISTINCT t.id)", "NOT EXISTS"},
+		},
+		{
+			name: "all tags",
+			req:  requests.ListArtikel{Tags: "go,news", TagMode: "all"},
+			want: []string{"t.slug IN ('go', 'news')) = 2"},
+		},
+		{
+			name: "all tags with a duplicate slug counts it once",
+			req:  requests.ListArtikel{Tags: "go,Go , news,go", TagMode: "all"},
+			want: []string{"t.slug IN ('go', 'news')) = 2"},
+		},
+		{
+			// an unknown slug still has to be matched, so requiring all tags returns nothing
+			name: "all tags with an unknown slug",
+			req:  requests.ListArtikel{Tags: "go,missing", TagMode: "all"},
+			want: []string{"t.slug IN ('go', 'missing')) = 2"},
+		},
+		{
+			name: "excluded tags",
+			req:  requests.ListArtikel{ExcludeTags: "draft,draft,old"},
+			want: []string{"NOT EXISTS (SELECT 1 FROM article_tags at JOIN tags t ON t.id = at.tag_id WHERE at.blog_article_id = ba.id AND t.slug IN ('draft', 'old'))"},
+		},
+		{
+			name: "tag id",
+			req:  requests.ListArtikel{TagID: "tag1"},
+			want: []string{"at.tag_id = 'tag1'"},
+		},
+		{
+			name:    "blank slugs are ignored",
+			req:     requests.ListArtikel{Tags: " , ,", ExcludeTags: ","},
+			notWant: []string{"article_tags"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where := filterQuery(t, tt.req, applyTagFilters)
+			for _, want := range tt.want {
+				if !strings.Contains(where, want) {
+					t.Errorf("filters %q\nmissing %q", where, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(where, notWant) {
+					t.Errorf("filters %q\nshould not contain %q", where, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyPublicArticleFilters(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		req     requests.ListArtikel
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "defaults",
+			req:     requests.ListArtikel{},
+			want:    []string{"ba.status = 'published'", notExpired, "ba.featured IS NULL"},
+			notWant: []string{"categories", "users", "published_at"},
+		},
+		{
+			name:    "featured included",
+			req:     requests.ListArtikel{IncludeFeatured: true},
+			notWant: []string{"ba.featured IS NULL"},
+		},
+		{
+			name:    "category slug",
+			req:     requests.ListArtikel{Category: "news"},
+			want:    []string{"JOIN categories p ON c.id = p.id WHERE p.slug = 'news'"},
+			notWant: []string{"c.path LIKE"},
+		},
+		{
+			name: "category slug with descendants",
+			req:  requests.ListArtikel{Category: "news", IncludeDescendants: true},
+			want: []string{"JOIN categories p ON (c.id = p.id OR c.path LIKE p.path || '/%') WHERE p.slug = 'news'"},
+		},
+		{
+			// unknown slugs are filtered on as given, matching no category instead of lifting the filter
+			name: "unknown category slug",
+			req:  requests.ListArtikel{Category: "missing"},
+			want: []string{"WHERE p.slug = 'missing'"},
+		},
+		{
+			name: "author",
+			req:  requests.ListArtikel{Author: "jane"},
+			want: []string{"ba.author_id IN (SELECT u.id FROM users u WHERE u.slug = 'jane')"},
+		},
+		{
+			name: "published range",
+			req:  requests.ListArtikel{PublishedFrom: &from, PublishedTo: &to},
+			want: []string{
+				"ba.published_at >= '2026-01-01 00:00:00+00:00'",
+				"ba.published_at <= '2026-02-01 00:00:00+00:00'",
+			},
+		},
+		{
+			name:    "published from only",
+			req:     requests.ListArtikel{PublishedFrom: &from},
+			want:    []string{"ba.published_at >= '2026-01-01 00:00:00+00:00'"},
+			notWant: []string{"ba.published_at <="},
+		},
+		{
+			name: "tags combined with the other filters",
+			req:  requests.ListArtikel{Category: "news", Author: "jane", Tags: "go,go", TagMode: "all", ExcludeTags: "old"},
+			want: []string{
+				"WHERE p.slug = 'news'",
+				"u.slug = 'jane'",
+				"t.slug IN ('go')) = 1",
+				"NOT EXISTS",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where := filterQuery(t, tt.req, applyPublicArticleFilters)
+			for _, want := range tt.want {
+				if !strings.Contains(where, want) {
+					t.Errorf("filters %q\nmissing %q", where, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(where, notWant) {
+					t.Errorf("filters %q\nshould not contain %q", where, notWant)
+				}
+			}
+		})
+	}
+}
+
+// tagFilterDB returns the database named by TEST_DATABASE_URL with a few tagged articles in
+// temporary tables, which shadow the real ones and are dropped with the connection
+func tagFilterDB(t *testing.T) *bun.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	sqlDB, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// temporary tables only exist on the connection that created them
+	sqlDB.SetMaxOpenConns(1)
+	db := bun.NewDB(sqlDB, pgdialect.New())
+	t.Cleanup(func() { db.Close() })
+
+	for _, stmt := range []string{
+		`CREATE TEMPORARY TABLE blog_artikels (id TEXT PRIMARY KEY)`,
+		`CREATE TEMPORARY TABLE tags (id TEXT PRIMARY KEY, slug TEXT NOT NULL, deleted_at TIMESTAMPTZ)`,
+		`CREATE TEMPORARY TABLE article_tags (blog_article_id TEXT NOT NULL, tag_id TEXT NOT NULL)`,
+		`INSERT INTO blog_artikels (id) VALUES ('go-news'), ('go'), ('news-old'), ('untagged'), ('go-deleted-news')`,
+		`INSERT INTO tags (id, slug, deleted_at) VALUES
+			('t-go', 'go', NULL), ('t-news', 'news', NULL), ('t-old', 'old', NULL), ('t-news-deleted', 'news', NOW())`,
+		`INSERT INTO article_tags (blog_article_id, tag_id) VALUES
+			('go-news', 't-go'), ('go-news', 't-news'),
+			('go', 't-go'),
+			('news-old', 't-news'), ('news-old', 't-old'),
+			('go-deleted-news', 't-go'), ('go-deleted-news', 't-news-deleted')`,
+	} {
+		if _, err := db.ExecContext(context.Background(), stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestApplyTagFiltersOnDatabase(t *testing.T) {
+	db := tagFilterDB(t)
+
+	tests := []struct {
+		name string
+		req  requests.ListArtikel
+		want []string
+	}{
+		{
+			name: "any tag",
+			req:  requests.ListArtikel{Tags: "go,news"},
+			want: []string{"go", "go-deleted-news", "go-news", "news-old"},
+		},
+		{
+			// the deleted news tag does not count towards matching all of them
+			name: "all tags",
+			req:  requests.ListArtikel{Tags: "go,news", TagMode: "all"},
+			want: []string{"go-news"},
+		},
+		{
+			name: "all tags with a duplicate slug",
+			req:  requests.ListArtikel{Tags: "go,go", TagMode: "all"},
+			want: []string{"go", "go-deleted-news", "go-news"},
+		},
+		{
+			name: "all tags with an unknown slug",
+			req:  requests.ListArtikel{Tags: "go,missing", TagMode: "all"},
+			want: []string{},
+		},
+		{
+			name: "excluded tag",
+			req:  requests.ListArtikel{ExcludeTags: "old"},
+			want: []string{"go", "go-deleted-news", "go-news", "untagged"},
+		},
+		{
+			name: "any tag except an excluded one",
+			req:  requests.ListArtikel{Tags: "news", ExcludeTags: "old"},
+			want: []string{"go-news"},
+		},
+		{
+			name: "all tags except an excluded one",
+			req:  requests.ListArtikel{Tags: "news,old", TagMode: "all", ExcludeTags: "go"},
+			want: []string{"news-old"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := db.NewSelect().TableExpr("blog_artikels AS ba").ColumnExpr("ba.id").OrderExpr("ba.id ASC")
+			applyTagFilters(q, tt.req)
+			got := []string{}
+			if err := q.Scan(context.Background(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("articles %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
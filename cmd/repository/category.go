@@ -5,10 +5,14 @@ import (
 	"database/sql"
 	default_err "errors"
 	"fmt"
+	"sora_landing_be/cmd/constants"
 	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto"
 	"sora_landing_be/cmd/dto/requests"
 	"sora_landing_be/pkg/database"
 	"sora_landing_be/pkg/errors"
+
+	"github.com/uptrace/bun"
 )
 
 type CategoryRepository interface {
@@ -20,6 +24,10 @@ type CategoryRepository interface {
 	GetCategory(ctx context.Context, id string) (res domain.Category, err error)
 	SlugExists(ctx context.Context, slug string) (bool, error)
 	GetCategoryByName(ctx context.Context, name string) (res *domain.Category, err error)
+	ListCategoriesByPaths(ctx context.Context, paths []string) ([]domain.Category, error)
+	HasChildren(ctx context.Context, id string) (bool, error)
+	MoveSubtree(ctx context.Context, oldPath, newPath string) error
+	ListCategoryCounts(ctx context.Context) ([]dto.CategoryCount, error)
 }
 
 type categoryRepository struct {
@@ -123,3 +131,54 @@ func (t *categoryRepository) SlugExists(ctx context.Context, slug string) (bool,
 		Where("slug = ?", slug).
 		Exists(ctx)
 }
+
+// ListCategoriesByPaths loads the categories at the given paths, shallowest first
+func (r *categoryRepository) ListCategoriesByPaths(ctx context.Context, paths []string) ([]domain.Category, error) {
+	var res []domain.Category
+	if len(paths) == 0 {
+		return res, nil
+	}
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Where("category.path IN (?)", bun.In(paths)).
+		OrderExpr("LENGTH(category.path) ASC").
+		Scan(ctx)
+	return res, err
+}
+
+func (r *categoryRepository) HasChildren(ctx context.Context, id string) (bool, error) {
+	return r.db.InitQuery(ctx).
+		NewSelect().
+		Model((*domain.Category)(nil)).
+		Where("parent_id = ?", id).
+		Exists(ctx)
+}
+
+// MoveSubtree rewrites the paths of every descendant of oldPath to live under newPath
+func (r *categoryRepository) MoveSubtree(ctx context.Context, oldPath, newPath string) error {
+	_, err := r.db.InitQuery(ctx).
+		NewUpdate().
+		Model((*domain.Category)(nil)).
+		Set("path = ? || SUBSTRING(path FROM ?)", newPath, len(oldPath)+1).
+		Set("updated_at = NOW()").
+		Where("path LIKE ?", oldPath+"/%").
+		Exec(ctx)
+	return err
+}
+
+// ListCategoryCounts returns every category with its published article count, subcategories included, in tree order
+func (r *categoryRepository) ListCategoryCounts(ctx context.Context) ([]dto.CategoryCount, error) {
+	var res []dto.CategoryCount
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		ColumnExpr("category.*").
+		ColumnExpr(`(SELECT COUNT(*) FROM blog_artikels ba
+			JOIN categories c ON c.id = ba.category_id AND c.deleted_at IS NULL
+			WHERE (c.id = category.id OR c.path LIKE category.path || '/%')
+			AND ba.status = ? AND ba.deleted_at IS NULL AND `+notExpired+`) AS article_count`, constants.StatusPublished).
+		Order("category.path ASC").
+		Scan(ctx)
+	return res, err
+}
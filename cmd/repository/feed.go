@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/pkg/database"
+	"sora_landing_be/pkg/errors"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type FeedRepository interface {
+	CreateSubscription(ctx context.Context, data *domain.FeedSubscription) error
+	UpdateSubscription(ctx context.Context, data *domain.FeedSubscription) error
+	UpdatePollState(ctx context.Context, data *domain.FeedSubscription) error
+	DeleteSubscription(ctx context.Context, id string) error
+	GetSubscription(ctx context.Context, id string) (domain.FeedSubscription, error)
+	ListSubscriptions(ctx context.Context, req requests.ListFeedSubscription) ([]domain.FeedSubscription, int, error)
+	ListDueSubscriptions(ctx context.Context, polledBefore time.Time) ([]domain.FeedSubscription, error)
+
+	GetEntries(ctx context.Context, subscriptionID string, guids []string) ([]domain.FeedEntry, error)
+	SaveEntry(ctx context.Context, data *domain.FeedEntry) error
+}
+
+type feedRepository struct {
+	db *database.Database
+}
+
+func NewFeedRepository(db *database.Database) FeedRepository {
+	return &feedRepository{
+		db: db,
+	}
+}
+
+func (r *feedRepository) CreateSubscription(ctx context.Context, data *domain.FeedSubscription) error {
+	_, err := r.db.InitQuery(ctx).NewInsert().Model(data).Returning("id").Exec(ctx)
+	if err != nil {
+		return errors.CheckUniqueViolation(err)
+	}
+	return nil
+}
+
+// UpdateSubscription saves the settings of a subscription. A new url starts its poll state over,
+// the validators of the old feed could otherwise answer the first poll with a false 304.
+func (r *feedRepository) UpdateSubscription(ctx context.Context, data *domain.FeedSubscription) error {
+	_, err := r.db.InitQuery(ctx).
+		NewUpdate().
+		Model((*domain.FeedSubscription)(nil)).
+		Set("etag = NULL").
+		Set("last_modified = NULL").
+		Set("last_polled_at = NULL").
+		Set("last_error = NULL").
+		Where("id = ?", data.ID).
+		Where("url <> ?", data.URL).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.InitQuery(ctx).
+		NewUpdate().
+		Model(data).
+		Column("url", "title", "category_id", "tag_ids", "auto_publish", "active", "edited_by_id", "updated_at").
+		Where("id = ?", data.ID).
+		Exec(ctx)
+	if err != nil {
+		return errors.CheckUniqueViolation(err)
+	}
+	return nil
+}
+
+// UpdatePollState stores the conditional GET validators and outcome of the last poll
+func (r *feedRepository) UpdatePollState(ctx context.Context, data *domain.FeedSubscription) error {
+	_, err := r.db.InitQuery(ctx).
+		NewUpdate().
+		Model(data).
+		Column("title", "etag", "last_modified", "last_polled_at", "last_error", "updated_at").
+		Where("id = ?", data.ID).
+		Exec(ctx)
+	return err
+}
+
+func (r *feedRepository) DeleteSubscription(ctx context.Context, id string) error {
+	_, err := r.db.InitQuery(ctx).
+		NewDelete().
+		Model((*domain.FeedSubscription)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+func (r *feedRepository) GetSubscription(ctx context.Context, id string) (res domain.FeedSubscription, err error) {
+	err = r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Relation("Category").
+		Where(`"fs"."id" = ?`, id).
+		Scan(ctx)
+	return res, err
+}
+
+func (r *feedRepository) ListSubscriptions(ctx context.Context, req requests.ListFeedSubscription) ([]domain.FeedSubscription, int, error) {
+	var res []domain.FeedSubscription
+	q := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Relation("Category")
+	if req.Search != "" {
+		q.Where("(fs.title ILIKE ? OR fs.url ILIKE ?)",
+			fmt.Sprintf("%%%s%%", req.Search), fmt.Sprintf("%%%s%%", req.Search))
+	}
+
+	q.Limit(req.PageSize).
+		Offset(req.CalculateOffset()).
+		Order(fmt.Sprintf("fs.%s %s", req.OrderBy, req.OrderDir))
+
+	total, err := q.ScanAndCount(ctx)
+	return res, total, err
+}
+
+func (r *feedRepository) ListDueSubscriptions(ctx context.Context, polledBefore time.Time) ([]domain.FeedSubscription, error) {
+	var res []domain.FeedSubscription
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Where("fs.active = TRUE").
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("fs.last_polled_at IS NULL").
+				WhereOr("fs.last_polled_at < ?", polledBefore)
+		}).
+		Order("fs.last_polled_at ASC NULLS FIRST").
+		Scan(ctx)
+	return res, err
+}
+
+func (r *feedRepository) GetEntries(ctx context.Context, subscriptionID string, guids []string) ([]domain.FeedEntry, error) {
+	var res []domain.FeedEntry
+	if len(guids) == 0 {
+		return res, nil
+	}
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Where("fe.subscription_id = ?", subscriptionID).
+		Where("fe.guid IN (?)", bun.In(guids)).
+		Scan(ctx)
+	return res, err
+}
+
+// SaveEntry inserts an entry or updates the outcome of a retried one
+func (r *feedRepository) SaveEntry(ctx context.Context, data *domain.FeedEntry) error {
+	_, err := r.db.InitQuery(ctx).
+		NewInsert().
+		Model(data).
+		On("CONFLICT (subscription_id, guid) DO UPDATE").
+		Set("status = EXCLUDED.status").
+		Set("article_id = EXCLUDED.article_id").
+		Set("attempts = EXCLUDED.attempts").
+		Set("error = EXCLUDED.error").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	return err
+}
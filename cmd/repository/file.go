@@ -0,0 +1,404 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/pkg/database"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type FileRepository interface {
+	CreateFile(ctx context.Context, data *domain.FileUpload) error
+	ListFilesByPaths(ctx context.Context, paths []string) ([]domain.FileUpload, error)
+	DeleteFileByPath(ctx context.Context, filePath string) error
+
+	ListMedia(ctx context.Context, req requests.ListMedia) ([]dto.MediaFile, int, error)
+	GetFile(ctx context.Context, id string) (domain.FileUpload, error)
+	UpdateFileDetails(ctx context.Context, file domain.FileUpload) error
+	ListFileUsages(ctx context.Context, id string) ([]dto.FileUsage, error)
+
+	SaveFileVariants(ctx context.Context, file domain.FileUpload) error
+	ListVariantPaths(ctx context.Context) ([]string, error)
+
+	ReplaceArticleFiles(ctx context.Context, articleID string, files []domain.ArticleFile) error
+
+	PromoteFiles(ctx context.Context, ids []string) error
+	PromoteReferencedFiles(ctx context.Context, before time.Time) ([]string, error)
+	ListAbandonedFiles(ctx context.Context, before time.Time, limit int) ([]domain.FileUpload, error)
+	ReleaseUnreferencedFile(ctx context.Context, filePath string) error
+
+	CreateUploadSession(ctx context.Context, session *domain.UploadSession) error
+	GetUploadSession(ctx context.Context, id string) (domain.UploadSession, error)
+	AppendUploadPart(ctx context.Context, id string, offset, newOffset int64, part string) (bool, error)
+	FinishUploadSession(ctx context.Context, id, fileID string) error
+	FinishDirectUpload(ctx context.Context, fileKey, fileID string) error
+	DeleteUploadSession(ctx context.Context, id string) error
+	ListStaleUploadSessions(ctx context.Context, before time.Time, limit int) ([]domain.UploadSession, error)
+}
+
+type fileRepository struct {
+	db *database.Database
+}
+
+func NewFileRepository(db *database.Database) FileRepository {
+	return &fileRepository{
+		db: db,
+	}
+}
+
+func (r *fileRepository) CreateFile(ctx context.Context, data *domain.FileUpload) error {
+	_, err := r.db.InitQuery(ctx).NewInsert().Model(data).Returning("id").Exec(ctx)
+	return err
+}
+
+func (r *fileRepository) ListFilesByPaths(ctx context.Context, paths []string) ([]domain.FileUpload, error) {
+	var res []domain.FileUpload
+	if len(paths) == 0 {
+		return res, nil
+	}
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Relation("Variants").
+		Where("fu.file_path IN (?)", bun.In(paths)).
+		Scan(ctx)
+	return res, err
+}
+
+func (r *fileRepository) DeleteFileByPath(ctx context.Context, filePath string) error {
+	_, err := r.db.InitQuery(ctx).
+		NewDelete().
+		Model((*domain.FileUpload)(nil)).
+		Where("file_path = ?", filePath).
+		Exec(ctx)
+	return err
+}
+
+// liveFileUsages lists where content that is not deleted shows a file: articles through
+// article_files and their Open Graph image, categories and tags through their image URLs
+const liveFileUsages = `SELECT 'article' AS type, ba.id, ba.title, ba.slug, ba.status, af.usage
+	FROM article_files af
+	JOIN blog_artikels ba ON ba.id = af.article_id AND ba.deleted_at IS NULL
+	WHERE af.file_id = fu.id
+	UNION ALL SELECT 'article', ba.id, ba.title, ba.slug, ba.status, 'og_image'
+	FROM blog_artikels ba
+	WHERE ba.deleted_at IS NULL AND ba.og_image LIKE '%' || fu.file_path
+	UNION ALL SELECT 'category', c.id, c.name, c.slug, NULL, img.usage
+	FROM categories c CROSS JOIN LATERAL (VALUES ('image', c.image_url), ('og_image', c.og_image)) AS img(usage, url)
+	WHERE c.deleted_at IS NULL AND img.url LIKE '%' || fu.file_path
+	UNION ALL SELECT 'tag', t.id, t.name, t.slug, NULL, img.usage
+	FROM tags t CROSS JOIN LATERAL (VALUES ('image', t.image_url), ('og_image', t.og_image)) AS img(usage, url)
+	WHERE t.deleted_at IS NULL AND img.url LIKE '%' || fu.file_path`
+
+var mediaSortColumns = map[string]string{
+	"created_at": "fu.created_at",
+	"file_name":  "fu.file_name",
+	"file_size":  "fu.file_size",
+}
+
+func (r *fileRepository) ListMedia(ctx context.Context, req requests.ListMedia) ([]dto.MediaFile, int, error) {
+	var res []dto.MediaFile
+
+	q := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		ColumnExpr("fu.*").
+		ColumnExpr("(SELECT COUNT(*) FROM (" + liveFileUsages + ") AS u) AS usage_count").
+		Relation("Uploader").
+		Relation("Variants")
+
+	if req.Search != "" {
+		search := fmt.Sprintf("%%%s%%", req.Search)
+		q.Where("(fu.file_name ILIKE ? OR fu.title ILIKE ? OR fu.alt_text ILIKE ? OR fu.caption ILIKE ?)",
+			search, search, search, search)
+	}
+	if prefixes := constants.MediaTypePrefixes[req.Type]; len(prefixes) > 0 {
+		q.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			for _, prefix := range prefixes {
+				q.WhereOr("fu.content_type LIKE ?", prefix+"%")
+			}
+			return q
+		})
+	}
+	if req.UploadedBy != "" {
+		q.Where("fu.uploaded_by = ?", req.UploadedBy)
+	}
+	if req.StartDate != nil {
+		q.Where("fu.created_at >= ?", req.StartDate)
+	}
+	if req.EndDate != nil {
+		q.Where("fu.created_at <= ?", req.EndDate)
+	}
+
+	orderBy, ok := mediaSortColumns[req.SortBy]
+	if !ok {
+		orderBy = mediaSortColumns["created_at"]
+	}
+	order := "DESC"
+	if req.SortOrder == "asc" {
+		order = "ASC"
+	}
+	q.Order(fmt.Sprintf("%s %s", orderBy, order), "fu.id ASC")
+
+	q.Limit(req.PageSize).
+		Offset(req.CalculateOffset())
+
+	total, err := q.ScanAndCount(ctx)
+	return res, total, err
+}
+
+// GetFile returns a file with its uploader and variants
+func (r *fileRepository) GetFile(ctx context.Context, id string) (res domain.FileUpload, err error) {
+	err = r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Relation("Uploader").
+		Relation("Variants").
+		Where("fu.id = ?", id).
+		Scan(ctx)
+	return res, err
+}
+
+func (r *fileRepository) UpdateFileDetails(ctx context.Context, file domain.FileUpload) error {
+	_, err := r.db.InitQuery(ctx).
+		NewUpdate().
+		Model(&file).
+		Column("title", "alt_text", "caption", "updated_at").
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+// ListFileUsages returns where content that is not deleted shows a file
+func (r *fileRepository) ListFileUsages(ctx context.Context, id string) ([]dto.FileUsage, error) {
+	res := []dto.FileUsage{}
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		TableExpr("file_uploads AS fu").
+		Join("CROSS JOIN LATERAL ("+liveFileUsages+") AS u").
+		ColumnExpr("u.*").
+		Where("fu.id = ?", id).
+		OrderExpr("u.type ASC, u.title ASC, u.usage ASC").
+		Scan(ctx, &res)
+	return res, err
+}
+
+// SaveFileVariants stores the dimensions of an image and replaces its variants with file.Variants
+func (r *fileRepository) SaveFileVariants(ctx context.Context, file domain.FileUpload) error {
+	_, err := r.db.InitQuery(ctx).
+		NewUpdate().
+		Model(&file).
+		Column("width", "height", "updated_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.InitQuery(ctx).
+		NewDelete().
+		Model((*domain.FileVariant)(nil)).
+		Where("file_id = ?", file.ID).
+		Exec(ctx)
+	if err != nil || len(file.Variants) == 0 {
+		return err
+	}
+
+	for _, variant := range file.Variants {
+		variant.FileID = file.ID
+	}
+	_, err = r.db.InitQuery(ctx).NewInsert().Model(&file.Variants).Exec(ctx)
+	return err
+}
+
+// ListVariantPaths returns the paths of every stored variant, variants are not uploads of their own
+func (r *fileRepository) ListVariantPaths(ctx context.Context) ([]string, error) {
+	var res []string
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model((*domain.FileVariant)(nil)).
+		Column("fv.file_path").
+		Scan(ctx, &res)
+	return res, err
+}
+
+// ReplaceArticleFiles sets the uploads an article shows, dropping the ones it no longer uses
+func (r *fileRepository) ReplaceArticleFiles(ctx context.Context, articleID string, files []domain.ArticleFile) error {
+	_, err := r.db.InitQuery(ctx).
+		NewDelete().
+		Model((*domain.ArticleFile)(nil)).
+		Where("article_id = ?", articleID).
+		Exec(ctx)
+	if err != nil || len(files) == 0 {
+		return err
+	}
+
+	for i := range files {
+		files[i].ArticleID = articleID
+	}
+	_, err = r.db.InitQuery(ctx).NewInsert().Model(&files).Exec(ctx)
+	return err
+}
+
+// PromoteFiles marks temporary uploads as used by saved content, the sweeper keeps them from then on
+func (r *fileRepository) PromoteFiles(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.db.InitQuery(ctx).
+		NewUpdate().
+		Model((*domain.FileUpload)(nil)).
+		Set("is_temporary = FALSE").
+		Set("updated_at = NOW()").
+		Where("id IN (?)", bun.In(ids)).
+		Where("is_temporary").
+		Exec(ctx)
+	return err
+}
+
+// fileReferenced matches uploads some content still points at. Articles record their cover and
+// inline images in article_files, other images are only known by their URL.
+const fileReferenced = `(EXISTS (SELECT 1 FROM article_files af WHERE af.file_id = fu.id)
+	OR EXISTS (SELECT 1 FROM blog_artikels ba WHERE ba.og_image LIKE '%' || fu.file_path)
+	OR EXISTS (SELECT 1 FROM categories c WHERE c.image_url LIKE '%' || fu.file_path OR c.og_image LIKE '%' || fu.file_path)
+	OR EXISTS (SELECT 1 FROM tags t WHERE t.image_url LIKE '%' || fu.file_path OR t.og_image LIKE '%' || fu.file_path))`
+
+// PromoteReferencedFiles keeps the expired temporary uploads that content saved without
+// promoting them refers to, such as category images, and returns their paths
+func (r *fileRepository) PromoteReferencedFiles(ctx context.Context, before time.Time) ([]string, error) {
+	var res []string
+	_, err := r.db.InitQuery(ctx).
+		NewUpdate().
+		TableExpr("file_uploads AS fu").
+		Set("is_temporary = FALSE").
+		Set("updated_at = NOW()").
+		Where("fu.is_temporary").
+		Where("fu.deleted_at IS NULL").
+		Where("fu.created_at < ?", before).
+		Where(fileReferenced).
+		Returning("fu.file_path").
+		Exec(ctx, &res)
+	return res, err
+}
+
+// ListAbandonedFiles returns temporary uploads created before the given time that nothing references
+func (r *fileRepository) ListAbandonedFiles(ctx context.Context, before time.Time, limit int) ([]domain.FileUpload, error) {
+	var res []domain.FileUpload
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Relation("Variants").
+		Where("fu.is_temporary").
+		Where("fu.created_at < ?", before).
+		Where("NOT " + fileReferenced).
+		OrderExpr("fu.created_at ASC").
+		Limit(limit).
+		Scan(ctx)
+	return res, err
+}
+
+// ReleaseUnreferencedFile turns a file back into a temporary upload when no content references
+// it anymore. Only the record changes, the storage flag stays clear as the release may be rolled back.
+func (r *fileRepository) ReleaseUnreferencedFile(ctx context.Context, filePath string) error {
+	_, err := r.db.InitQuery(ctx).
+		NewUpdate().
+		TableExpr("file_uploads AS fu").
+		Set("is_temporary = TRUE").
+		Set("updated_at = NOW()").
+		Where("fu.file_path = ?", filePath).
+		Where("NOT fu.is_temporary").
+		Where("fu.deleted_at IS NULL").
+		Where("NOT " + fileReferenced).
+		Exec(ctx)
+	return err
+}
+
+func (r *fileRepository) CreateUploadSession(ctx context.Context, session *domain.UploadSession) error {
+	_, err := r.db.InitQuery(ctx).NewInsert().Model(session).Returning("id").Exec(ctx)
+	return err
+}
+
+func (r *fileRepository) GetUploadSession(ctx context.Context, id string) (res domain.UploadSession, err error) {
+	err = r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Where("us.id = ?", id).
+		Scan(ctx)
+	return res, err
+}
+
+// AppendUploadPart records a stored chunk, only while the upload is still at offset. It reports
+// false when another request moved the upload on or finished it first.
+func (r *fileRepository) AppendUploadPart(ctx context.Context, id string, offset, newOffset int64, part string) (bool, error) {
+	res, err := r.db.InitQuery(ctx).
+		NewUpdate().
+		Model((*domain.UploadSession)(nil)).
+		Set("upload_offset = ?", newOffset).
+		Set("parts = array_append(parts, ?)", part).
+		Set("updated_at = NOW()").
+		Where("us.id = ?", id).
+		Where("us.upload_offset = ?", offset).
+		Where("us.file_id IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// FinishUploadSession links an upload to the file its chunks were joined into, the parts are gone
+func (r *fileRepository) FinishUploadSession(ctx context.Context, id, fileID string) error {
+	_, err := r.db.InitQuery(ctx).
+		NewUpdate().
+		Model((*domain.UploadSession)(nil)).
+		Set("file_id = ?", fileID).
+		Set("parts = '{}'").
+		Set("updated_at = NOW()").
+		Where("us.id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+// FinishDirectUpload links a presigned upload to the file recorded for its object
+func (r *fileRepository) FinishDirectUpload(ctx context.Context, fileKey, fileID string) error {
+	_, err := r.db.InitQuery(ctx).
+		NewUpdate().
+		Model((*domain.UploadSession)(nil)).
+		Set("file_id = ?", fileID).
+		Set("updated_at = NOW()").
+		Where("us.file_key = ?", fileKey).
+		Where("us.direct").
+		Where("us.file_id IS NULL").
+		Exec(ctx)
+	return err
+}
+
+func (r *fileRepository) DeleteUploadSession(ctx context.Context, id string) error {
+	_, err := r.db.InitQuery(ctx).
+		NewDelete().
+		Model((*domain.UploadSession)(nil)).
+		Where("id = ?", id).
+		ForceDelete().
+		Exec(ctx)
+	return err
+}
+
+// ListStaleUploadSessions returns uploads, finished or not, that received nothing since before
+func (r *fileRepository) ListStaleUploadSessions(ctx context.Context, before time.Time, limit int) ([]domain.UploadSession, error) {
+	var res []domain.UploadSession
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Where("us.updated_at < ?", before).
+		OrderExpr("us.updated_at ASC").
+		Limit(limit).
+		Scan(ctx)
+	return res, err
+}
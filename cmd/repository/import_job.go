@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/pkg/database"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type ImportJobRepository interface {
+	CreateJob(ctx context.Context, data *domain.ImportJob) error
+	GetJob(ctx context.Context, id string) (domain.ImportJob, error)
+	UpdateJobStatus(ctx context.Context, id string, status constants.ImportStatus, finishedAt *time.Time) error
+	UpdateItem(ctx context.Context, data *domain.ImportJobItem) error
+}
+
+type importJobRepository struct {
+	db *database.Database
+}
+
+func NewImportJobRepository(db *database.Database) ImportJobRepository {
+	return &importJobRepository{
+		db: db,
+	}
+}
+
+func (r *importJobRepository) CreateJob(ctx context.Context, data *domain.ImportJob) error {
+	_, err := r.db.InitQuery(ctx).NewInsert().Model(data).Returning("id").Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(data.Items) == 0 {
+		return nil
+	}
+	for _, item := range data.Items {
+		item.JobID = data.ID
+	}
+	_, err = r.db.InitQuery(ctx).NewInsert().Model(&data.Items).Returning("id").Exec(ctx)
+	return err
+}
+
+func (r *importJobRepository) GetJob(ctx context.Context, id string) (res domain.ImportJob, err error) {
+	err = r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Relation("Items", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("iji.created_at ASC")
+		}).
+		Where(`"ij"."id" = ?`, id).
+		Scan(ctx)
+	return res, err
+}
+
+func (r *importJobRepository) UpdateJobStatus(ctx context.Context, id string, status constants.ImportStatus, finishedAt *time.Time) error {
+	query := r.db.InitQuery(ctx).NewUpdate().
+		Table("import_jobs").
+		Set("status = ?", status).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id)
+
+	if finishedAt != nil {
+		query.Set("finished_at = ?", finishedAt)
+	}
+
+	_, err := query.Exec(ctx)
+	return err
+}
+
+func (r *importJobRepository) UpdateItem(ctx context.Context, data *domain.ImportJobItem) error {
+	_, err := r.db.InitQuery(ctx).
+		NewUpdate().
+		Model(data).
+		Column("status", "article_id", "error", "updated_at").
+		Where("id = ?", data.ID).
+		Exec(ctx)
+	return err
+}
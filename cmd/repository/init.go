@@ -15,6 +15,11 @@ type PoolRepository struct {
 	CategoryRepository       CategoryRepository
 	BlogRepository           BlogRepository
 	DemoRepository           DemoRepository
+	ArchiveRepository        ArchiveRepository
+	ImportJobRepository      ImportJobRepository
+	FeedRepository           FeedRepository
+	LinkRepository           LinkRepository
+	FileRepository           FileRepository
 }
 
 func Init(db *database.Database) {
@@ -26,6 +31,11 @@ func Init(db *database.Database) {
 			CategoryRepository:       NewCatRepository(db),
 			BlogRepository:           NewBlogRepository(db),
 			DemoRepository:           NewDemoRepository(db),
+			ArchiveRepository:        NewArchiveRepository(db),
+			ImportJobRepository:      NewImportJobRepository(db),
+			FeedRepository:           NewFeedRepository(db),
+			LinkRepository:           NewLinkRepository(db),
+			FileRepository:           NewFileRepository(db),
 		}
 	})
 }
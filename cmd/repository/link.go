@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/pkg/database"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type LinkRepository interface {
+	ReplaceArticleLinks(ctx context.Context, articleID string, links []*domain.ArticleLink, checkedAt time.Time) error
+	ListArticleLinks(ctx context.Context, articleID string) ([]domain.ArticleLink, error)
+	ListArticlesWithLinkIssues(ctx context.Context, req requests.ListLinkReport) ([]domain.BlogArtikel, int, error)
+	ListArticlesDueForLinkCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]domain.BlogArtikel, error)
+
+	ExistingArticleSlugs(ctx context.Context, slugs []string) ([]string, error)
+	ExistingCategorySlugs(ctx context.Context, slugs []string) ([]string, error)
+	ExistingTagSlugs(ctx context.Context, slugs []string) ([]string, error)
+}
+
+type linkRepository struct {
+	db *database.Database
+}
+
+func NewLinkRepository(db *database.Database) LinkRepository {
+	return &linkRepository{
+		db: db,
+	}
+}
+
+// ReplaceArticleLinks stores the result of a check, dropping the links of the previous one
+func (r *linkRepository) ReplaceArticleLinks(ctx context.Context, articleID string, links []*domain.ArticleLink, checkedAt time.Time) error {
+	_, err := r.db.InitQuery(ctx).
+		NewDelete().
+		Model((*domain.ArticleLink)(nil)).
+		Where("article_id = ?", articleID).
+		ForceDelete().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(links) > 0 {
+		for _, link := range links {
+			link.ArticleID = articleID
+			link.CheckedAt = checkedAt
+		}
+		if _, err := r.db.InitQuery(ctx).NewInsert().Model(&links).Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	_, err = r.db.InitQuery(ctx).NewUpdate().
+		Table("blog_artikels").
+		Set("links_checked_at = ?", checkedAt).
+		Where("id = ?", articleID).
+		Exec(ctx)
+	return err
+}
+
+func (r *linkRepository) ListArticleLinks(ctx context.Context, articleID string) ([]domain.ArticleLink, error) {
+	var res []domain.ArticleLink
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Where("al.article_id = ?", articleID).
+		Order("al.status ASC", "al.url ASC").
+		Scan(ctx)
+	return res, err
+}
+
+// ListArticlesWithLinkIssues pages through the articles having links in one of the requested
+// statuses, each loaded with those links only
+func (r *linkRepository) ListArticlesWithLinkIssues(ctx context.Context, req requests.ListLinkReport) ([]domain.BlogArtikel, int, error) {
+	var res []domain.BlogArtikel
+	statuses := bun.In(req.LinkStatuses())
+
+	q := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Column("ba.id", "ba.title", "ba.slug", "ba.status", "ba.links_checked_at").
+		Relation("Links", func(q *bun.SelectQuery) *bun.SelectQuery {
+			q.Where("al.status IN (?)", statuses)
+			if req.Internal != nil {
+				q.Where("al.internal = ?", *req.Internal)
+			}
+			return q.Order("al.url ASC")
+		}).
+		Where(`EXISTS (SELECT 1 FROM article_links al
+			WHERE al.article_id = ba.id AND al.deleted_at IS NULL AND al.status IN (?)`+internalLinkFilter(req.Internal)+`)`, statuses).
+		Order("ba.links_checked_at DESC", "ba.id ASC")
+
+	q.Limit(req.PageSize).
+		Offset(req.CalculateOffset())
+
+	total, err := q.ScanAndCount(ctx)
+	return res, total, err
+}
+
+func internalLinkFilter(internal *bool) string {
+	switch {
+	case internal == nil:
+		return ""
+	case *internal:
+		return " AND al.internal"
+	default:
+		return " AND NOT al.internal"
+	}
+}
+
+// ListArticlesDueForLinkCheck returns public articles never checked, checked before the given
+// time or edited since their last check, the longest unchecked first
+func (r *linkRepository) ListArticlesDueForLinkCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]domain.BlogArtikel, error) {
+	var res []domain.BlogArtikel
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Column("ba.id", "ba.content", "ba.image_url").
+		Where("ba.status = ?", constants.StatusPublished).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("ba.links_checked_at IS NULL").
+				WhereOr("ba.links_checked_at < ?", checkedBefore).
+				WhereOr("ba.updated_at > ba.links_checked_at")
+		}).
+		OrderExpr("ba.links_checked_at ASC NULLS FIRST").
+		Limit(limit).
+		Scan(ctx)
+	return res, err
+}
+
+// ExistingArticleSlugs returns which of the slugs belong to an article readers can open
+func (r *linkRepository) ExistingArticleSlugs(ctx context.Context, slugs []string) ([]string, error) {
+	var res []string
+	if len(slugs) == 0 {
+		return res, nil
+	}
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model((*domain.BlogArtikel)(nil)).
+		Column("ba.slug").
+		Where("ba.slug IN (?)", bun.In(slugs)).
+		Where("ba.status = ?", constants.StatusPublished).
+		Where(notExpired).
+		Scan(ctx, &res)
+	return res, err
+}
+
+func (r *linkRepository) ExistingCategorySlugs(ctx context.Context, slugs []string) ([]string, error) {
+	var res []string
+	if len(slugs) == 0 {
+		return res, nil
+	}
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model((*domain.Category)(nil)).
+		Column("category.slug").
+		Where("category.slug IN (?)", bun.In(slugs)).
+		Scan(ctx, &res)
+	return res, err
+}
+
+func (r *linkRepository) ExistingTagSlugs(ctx context.Context, slugs []string) ([]string, error) {
+	var res []string
+	if len(slugs) == 0 {
+		return res, nil
+	}
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model((*domain.Tag)(nil)).
+		Column("tag.slug").
+		Where("tag.slug IN (?)", bun.In(slugs)).
+		Scan(ctx, &res)
+	return res, err
+}
@@ -5,10 +5,15 @@ import (
 	"database/sql"
 	default_err "errors"
 	"fmt"
+	"sora_landing_be/cmd/constants"
 	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto"
 	"sora_landing_be/cmd/dto/requests"
 	"sora_landing_be/pkg/database"
 	"sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/utils"
+
+	"github.com/uptrace/bun"
 )
 
 type TagRepository interface {
@@ -20,6 +25,21 @@ type TagRepository interface {
 	GetTag(ctx context.Context, id string) (res domain.Tag, err error)
 	GetTagByName(ctx context.Context, name string) (*domain.Tag, error)
 	SlugExists(ctx context.Context, slug string) (bool, error)
+	CountTagsByIDs(ctx context.Context, ids []string) (int, error)
+	GetTagsByIDs(ctx context.Context, ids []string) ([]domain.Tag, error)
+	MergeTags(ctx context.Context, targetID string, sourceIDs []string) (int, error)
+
+	// Synonyms and redirects of merged or renamed tags
+	ListSynonyms(ctx context.Context, tagID string) ([]domain.TagSynonym, error)
+	CreateSynonym(ctx context.Context, data *domain.TagSynonym) error
+	SaveSynonyms(ctx context.Context, data []domain.TagSynonym) error
+	DeleteSynonym(ctx context.Context, tagID, id string) error
+	SaveRedirects(ctx context.Context, data []domain.TagRedirect) error
+	DeleteRedirect(ctx context.Context, oldSlug string) error
+	GetRedirectedSlugs(ctx context.Context, slugs []string) (map[string]string, error)
+
+	ListTagCounts(ctx context.Context, limit int) ([]dto.TagCount, error)
+	ListTagsWithSynonyms(ctx context.Context) ([]domain.Tag, error)
 }
 
 type tagRepository struct {
@@ -97,14 +117,24 @@ func (r *tagRepository) GetTag(ctx context.Context, id string) (res domain.Tag,
 		Where(`"tag"."id" = ?`, id).Scan(ctx)
 	return res, err
 }
+
+// GetTagByName finds a tag by its exact name, its slug or one of its synonyms, preferring the exact name
 func (r *tagRepository) GetTagByName(ctx context.Context, name string) (*domain.Tag, error) {
 	var res domain.Tag
+	normalized := utils.Slugify(name)
 	err := r.db.InitQuery(ctx).
 		NewSelect().
 		Model(&res).
 		Relation("CreatedBy").
 		Relation("EditedBy").
-		Where(`"tag"."name" = ?`, name).Scan(ctx)
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where(`"tag"."name" = ?`, name).
+				WhereOr(`"tag"."slug" = ?`, normalized).
+				WhereOr(`"tag"."id" IN (SELECT ts.tag_id FROM tag_synonyms ts WHERE ts.normalized = ? AND ts.deleted_at IS NULL)`, normalized)
+		}).
+		OrderExpr(`"tag"."name" = ? DESC`, name).
+		Limit(1).
+		Scan(ctx)
 	if err != nil {
 		if default_err.Is(err, sql.ErrNoRows) {
 			return nil, nil // category not found
@@ -121,3 +151,208 @@ func (t *tagRepository) SlugExists(ctx context.Context, slug string) (bool, erro
 		Where("slug = ?", slug).
 		Exists(ctx)
 }
+
+func (r *tagRepository) CountTagsByIDs(ctx context.Context, ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return r.db.InitQuery(ctx).
+		NewSelect().
+		Model((*domain.Tag)(nil)).
+		Where(`"tag"."id" IN (?)`, bun.In(ids)).
+		Count(ctx)
+}
+
+func (r *tagRepository) GetTagsByIDs(ctx context.Context, ids []string) ([]domain.Tag, error) {
+	var res []domain.Tag
+	if len(ids) == 0 {
+		return res, nil
+	}
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Where(`"tag"."id" IN (?)`, bun.In(ids)).
+		Scan(ctx)
+	return res, err
+}
+
+// MergeTags moves every reference to the source tags onto the target and soft deletes the sources,
+// it returns how many articles gained the target tag
+func (r *tagRepository) MergeTags(ctx context.Context, targetID string, sourceIDs []string) (int, error) {
+	q := r.db.InitQuery(ctx)
+
+	// articles already carrying the target keep a single row
+	result, err := q.NewRaw(`INSERT INTO article_tags (blog_article_id, tag_id)
+		SELECT DISTINCT at.blog_article_id, ? FROM article_tags at WHERE at.tag_id IN (?)
+		ON CONFLICT DO NOTHING`, targetID, bun.In(sourceIDs)).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	retagged, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err = q.NewDelete().
+		Model((*domain.ArticleTag)(nil)).
+		Where("tag_id IN (?)", bun.In(sourceIDs)).
+		Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	// feed subscriptions keep tagging their imports with the surviving tag
+	for _, sourceID := range sourceIDs {
+		if _, err = q.NewRaw(`UPDATE feed_subscriptions
+			SET tag_ids = ARRAY(SELECT DISTINCT UNNEST(ARRAY_REPLACE(tag_ids, ?, ?))), updated_at = NOW()
+			WHERE ? = ANY(tag_ids)`, sourceID, targetID, sourceID).
+			Exec(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, model := range []any{(*domain.TagSynonym)(nil), (*domain.TagRedirect)(nil)} {
+		if _, err = q.NewUpdate().
+			Model(model).
+			Set("tag_id = ?", targetID).
+			Set("updated_at = NOW()").
+			Where("tag_id IN (?)", bun.In(sourceIDs)).
+			Exec(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	_, err = q.NewDelete().
+		Model((*domain.Tag)(nil)).
+		Where("id IN (?)", bun.In(sourceIDs)).
+		Exec(ctx)
+	return int(retagged), err
+}
+
+func (r *tagRepository) ListSynonyms(ctx context.Context, tagID string) ([]domain.TagSynonym, error) {
+	var res []domain.TagSynonym
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Where("ts.tag_id = ?", tagID).
+		Order("ts.name ASC").
+		Scan(ctx)
+	return res, err
+}
+
+func (r *tagRepository) CreateSynonym(ctx context.Context, data *domain.TagSynonym) error {
+	_, err := r.db.InitQuery(ctx).NewInsert().Model(data).Returning("id").Exec(ctx)
+	if err != nil {
+		return errors.CheckUniqueViolation(err)
+	}
+	return nil
+}
+
+// SaveSynonyms inserts synonyms, pointing names that already exist at the new tag
+func (r *tagRepository) SaveSynonyms(ctx context.Context, data []domain.TagSynonym) error {
+	if len(data) == 0 {
+		return nil
+	}
+	_, err := r.db.InitQuery(ctx).
+		NewInsert().
+		Model(&data).
+		On("CONFLICT (normalized) WHERE deleted_at IS NULL DO UPDATE").
+		Set("tag_id = EXCLUDED.tag_id").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	return err
+}
+
+func (r *tagRepository) DeleteSynonym(ctx context.Context, tagID, id string) error {
+	result, err := r.db.InitQuery(ctx).
+		NewDelete().
+		Model((*domain.TagSynonym)(nil)).
+		Where("id = ?", id).
+		Where("tag_id = ?", tagID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SaveRedirects records old slugs, an old slug that was already redirected follows the newest tag
+func (r *tagRepository) SaveRedirects(ctx context.Context, data []domain.TagRedirect) error {
+	if len(data) == 0 {
+		return nil
+	}
+	_, err := r.db.InitQuery(ctx).
+		NewInsert().
+		Model(&data).
+		On("CONFLICT (old_slug) WHERE deleted_at IS NULL DO UPDATE").
+		Set("tag_id = EXCLUDED.tag_id").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	return err
+}
+
+func (r *tagRepository) DeleteRedirect(ctx context.Context, oldSlug string) error {
+	_, err := r.db.InitQuery(ctx).
+		NewDelete().
+		Model((*domain.TagRedirect)(nil)).
+		Where("old_slug = ?", oldSlug).
+		Exec(ctx)
+	return err
+}
+
+// GetRedirectedSlugs maps the given slugs that belonged to merged or renamed tags to the current slug,
+// a slug a live tag uses now is never redirected
+func (r *tagRepository) GetRedirectedSlugs(ctx context.Context, slugs []string) (map[string]string, error) {
+	res := make(map[string]string)
+	if len(slugs) == 0 {
+		return res, nil
+	}
+	var rows []struct {
+		OldSlug string `bun:"old_slug"`
+		Slug    string `bun:"slug"`
+	}
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model((*domain.TagRedirect)(nil)).
+		ColumnExpr("tr.old_slug, t.slug").
+		Join("JOIN tags t ON t.id = tr.tag_id AND t.deleted_at IS NULL").
+		Where("tr.old_slug IN (?)", bun.In(slugs)).
+		Where("NOT EXISTS (SELECT 1 FROM tags lt WHERE lt.slug = tr.old_slug AND lt.deleted_at IS NULL)").
+		Scan(ctx, &rows)
+	for _, row := range rows {
+		res[row.OldSlug] = row.Slug
+	}
+	return res, err
+}
+
+// ListTagCounts returns tags used by published articles, most used first, limit <= 0 returns all of them
+func (r *tagRepository) ListTagCounts(ctx context.Context, limit int) ([]dto.TagCount, error) {
+	var res []dto.TagCount
+	q := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		ColumnExpr("tag.*").
+		ColumnExpr("COUNT(ba.id) AS article_count").
+		Join("JOIN article_tags at ON at.tag_id = tag.id").
+		Join("JOIN blog_artikels ba ON ba.id = at.blog_article_id AND ba.status = ? AND ba.deleted_at IS NULL AND "+notExpired, constants.StatusPublished).
+		Group("tag.id").
+		OrderExpr("article_count DESC, tag.name ASC")
+	if limit > 0 {
+		q.Limit(limit)
+	}
+	err := q.Scan(ctx)
+	return res, err
+}
+
+func (r *tagRepository) ListTagsWithSynonyms(ctx context.Context) ([]domain.Tag, error) {
+	var res []domain.Tag
+	err := r.db.InitQuery(ctx).
+		NewSelect().
+		Model(&res).
+		Relation("Synonyms").
+		Scan(ctx)
+	return res, err
+}
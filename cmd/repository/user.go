@@ -15,6 +15,7 @@ type UserRepository interface {
 	UpdateUser(ctx context.Context, data *domain.User) error
 	DeleteUser(ctx context.Context, id string) error
 	GetUser(ctx context.Context, id string) (res domain.User, err error)
+	SlugExists(ctx context.Context, slug string) (bool, error)
 }
 
 type userRepository struct {
@@ -57,7 +58,7 @@ func (r *userRepository) UpdateUser(ctx context.Context, data *domain.User) erro
 		NewUpdate().
 		Model(data).
 		Where("id = ?", data.ID).
-		ExcludeColumn("created_at").
+		ExcludeColumn("created_at", "slug").
 		Returning("id").
 		Exec(ctx)
 	return err
@@ -79,3 +80,11 @@ func (r *userRepository) GetUser(ctx context.Context, id string) (res domain.Use
 		Where(`"user"."id" = ?`, id).Scan(ctx)
 	return res, err
 }
+
+func (r *userRepository) SlugExists(ctx context.Context, slug string) (bool, error) {
+	return r.db.InitQuery(ctx).
+		NewSelect().
+		Model((*domain.User)(nil)).
+		Where("slug = ?", slug).
+		Exists(ctx)
+}
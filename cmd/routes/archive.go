@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/controllers"
+	"sora_landing_be/cmd/services"
+	"sora_landing_be/pkg/http/server/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerArchive(router *gin.RouterGroup) {
+	archiveCtl := controllers.NewArchiveController(services.ServicePool.ArchiveService)
+
+	archive := router.Group("/archive", middlewares.RoleHandler(constants.UserRoleSuperAdmin))
+	{
+		archive.GET("export", archiveCtl.Export)
+		archive.POST("import", archiveCtl.Import)
+	}
+}
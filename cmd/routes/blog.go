@@ -17,10 +17,14 @@ func registerBlog(router *gin.RouterGroup) {
 		blog.GET("stats", blogCtl.GetArticleStats)
 		blog.GET(":id", blogCtl.GetArticle)
 		blog.GET("by-slug/:slug", blogCtl.GetArticleBySlug)
+		blog.GET("external/batch/:id", blogCtl.GetImportJob)
 
 		// Write operations
 		blog.POST("", blogCtl.CreateArticle)
 		blog.POST("external", blogCtl.CreateArticleFromURL)
+		blog.POST("external/batch", blogCtl.CreateArticlesFromURLs)
+		blog.POST("suggest-tags", blogCtl.SuggestTags)
+		blog.POST(":id/resync", blogCtl.ResyncExternalArticle)
 		blog.PUT(":id", blogCtl.UpdateArticle)
 		blog.PATCH(":id/status", blogCtl.UpdateArticleStatus)
 		blog.PUT(":id/tags", blogCtl.UpdateArticleTags)
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"sora_landing_be/cmd/controllers"
+	"sora_landing_be/cmd/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerFeed(router *gin.RouterGroup) {
+	feedCtl := controllers.NewFeedController(services.ServicePool.FeedService)
+
+	feed := router.Group("/feeds")
+	{
+		feed.GET("", feedCtl.List)
+		feed.GET(":id", feedCtl.Get)
+		feed.POST("", feedCtl.Create)
+		feed.POST(":id/poll", feedCtl.Poll)
+		feed.PUT(":id", feedCtl.Update)
+		feed.DELETE(":id", feedCtl.Delete)
+	}
+}
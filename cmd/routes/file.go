@@ -2,16 +2,28 @@ package routes
 
 import (
 	"sora_landing_be/cmd/controllers"
+	"sora_landing_be/cmd/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterFileRoutes(router *gin.RouterGroup) {
-	userCtl := controllers.NewFileController()
+	userCtl := controllers.NewFileController(services.ServicePool.FileService)
 
 	publicFiles := router.Group("/files")
 	{
 		publicFiles.POST("", userCtl.UploadFile)
+		publicFiles.POST("/presign", userCtl.PresignUpload)
+		publicFiles.POST("/complete", userCtl.CompleteUpload)
 		publicFiles.DELETE(":filename", userCtl.DeleteFile)
 	}
+
+	// tus 1.0 resumable uploads
+	tus := publicFiles.Group("/tus")
+	{
+		tus.POST("", userCtl.CreateResumableUpload)
+		tus.HEAD(":id", userCtl.GetResumableUpload)
+		tus.PATCH(":id", userCtl.AppendResumableUpload)
+		tus.DELETE(":id", userCtl.TerminateResumableUpload)
+	}
 }
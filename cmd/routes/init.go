@@ -41,7 +41,13 @@ func RegisterV1(router *gin.Engine) {
 		registerUser(v1)
 		registerBlog(v1)
 		RegisterFileRoutes(v1)
+		registerArchive(v1)
+		registerFeed(v1)
+		registerLinkAudit(v1)
+		registerMedia(v1)
 
 	}
 
+	registerShare(router)
+
 }
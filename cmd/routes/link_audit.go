@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"sora_landing_be/cmd/controllers"
+	"sora_landing_be/cmd/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerLinkAudit(router *gin.RouterGroup) {
+	linkCtl := controllers.NewLinkAuditController(services.ServicePool.LinkService)
+
+	links := router.Group("/articles")
+	{
+		links.GET("links", linkCtl.ListReport)
+		links.GET(":id/links", linkCtl.GetArticleLinks)
+		links.POST(":id/links/check", linkCtl.CheckArticle)
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"sora_landing_be/cmd/controllers"
+	"sora_landing_be/cmd/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerMedia(router *gin.RouterGroup) {
+	mediaCtl := controllers.NewMediaController(services.ServicePool.FileService)
+
+	media := router.Group("/media")
+	{
+		media.GET("", mediaCtl.ListMedia)
+		media.GET(":id", mediaCtl.GetMedia)
+		media.PUT(":id", mediaCtl.UpdateMedia)
+		media.DELETE(":id", mediaCtl.DeleteMedia)
+	}
+}
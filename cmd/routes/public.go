@@ -10,6 +10,8 @@ import (
 func registerPublic(router *gin.RouterGroup) {
 	ctl := controllers.NewDemoController(services.ServicePool.DemoService)
 	bctl := controllers.NewBlogController(services.ServicePool.BlogService)
+	cctl := controllers.NewCatController(services.ServicePool.CategoryService)
+	tctl := controllers.NewTagController(services.ServicePool.TagService)
 
 	demo := router.Group("/demo")
 	{
@@ -21,5 +23,21 @@ func registerPublic(router *gin.RouterGroup) {
 		blog.GET(":id", bctl.GetPublicArticleBySlug)
 		blog.GET("", bctl.ListPublicArticles)
 		blog.GET("/featured", bctl.GetFeaturedArticle)
+		blog.GET("/schema", bctl.ListPublicArticlesSchema)
+		blog.GET("/archive", bctl.GetBlogArchive)
+		blog.GET("/archive/:year", bctl.ListArchiveArticles)
+		blog.GET("/archive/:year/:month", bctl.ListArchiveArticles)
+		blog.GET(":id/schema", bctl.GetPublicArticleSchema)
+	}
+
+	categories := router.Group("/categories")
+	{
+		categories.GET("", cctl.ListPublic)
+		categories.GET(":slug", cctl.GetPublic)
+	}
+
+	tags := router.Group("/tags")
+	{
+		tags.GET("", tctl.ListPublic)
 	}
 }
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"sora_landing_be/cmd/controllers"
+	"sora_landing_be/cmd/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerShare serves the pages link preview crawlers see when an article is shared
+func registerShare(router *gin.Engine) {
+	shareCtl := controllers.NewShareController(services.ServicePool.BlogService)
+
+	share := router.Group("/share")
+	{
+		share.GET("blog/:slug", shareCtl.Article)
+	}
+}
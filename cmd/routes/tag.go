@@ -17,5 +17,9 @@ func registerTag(router *gin.RouterGroup) {
 		tag.GET(":id", userCtl.Get)
 		tag.PUT(":id", userCtl.Update)
 		tag.DELETE(":id", userCtl.Delete)
+		tag.POST(":id/merge", userCtl.Merge)
+		tag.GET(":id/synonyms", userCtl.ListSynonyms)
+		tag.POST(":id/synonyms", userCtl.CreateSynonym)
+		tag.DELETE(":id/synonyms/:synonym_id", userCtl.DeleteSynonym)
 	}
 }
@@ -32,6 +32,7 @@ func SeedCategories(ctx context.Context) error {
 			},
 			Name: name,
 			Slug: utils.Slugify(name),
+			Path: utils.Slugify(name),
 		}
 
 		if _, err := db.NewInsert().Model(category).Exec(ctx); err != nil {
@@ -33,6 +33,7 @@ func SeedUsers(ctx context.Context) error {
 
 	user := &domain.User{
 		Name:   "Admin User",
+		Slug:   "admin-user",
 		Email:  "admin@example.com",
 		Status: "Active",
 		Roles:  []constants.UserRole{constants.UserRoleSuperAdmin},
@@ -0,0 +1,689 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto/response"
+	"sora_landing_be/cmd/repository"
+	"sora_landing_be/pkg/database"
+	internal_err "sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/storage"
+	"sora_landing_be/pkg/utils"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	archiveVersion        = 1
+	archiveManifestFile   = "manifest.json"
+	archiveAuthorsFile    = "authors.json"
+	archiveCategoriesFile = "categories.json"
+	archiveTagsFile       = "tags.json"
+	archiveArticlesDir    = "articles/"
+	archiveUploadsDir     = "uploads/"
+	frontMatterDelimiter  = "---"
+)
+
+// uploadRefPattern matches references to locally stored files inside article content
+var uploadRefPattern = regexp.MustCompile(`/?uploads/([A-Za-z0-9._\-]+)`)
+
+type ArchiveService interface {
+	Export(ctx context.Context, w io.Writer) error
+	Import(ctx context.Context, r io.ReaderAt, size int64) (response.ArchiveImport, error)
+}
+
+type archiveService struct {
+	archiveRepo repository.ArchiveRepository
+}
+
+func NewArchiveService(archiveRepo repository.ArchiveRepository) ArchiveService {
+	return &archiveService{
+		archiveRepo: archiveRepo,
+	}
+}
+
+type (
+	archiveManifest struct {
+		Version    int       `json:"version"`
+		ExportedAt time.Time `json:"exported_at"`
+		Authors    int       `json:"authors"`
+		Categories int       `json:"categories"`
+		Tags       int       `json:"tags"`
+		Articles   int       `json:"articles"`
+		Files      int       `json:"files"`
+	}
+
+	archiveAuthor struct {
+		ID        string               `json:"id"`
+		Name      string               `json:"name"`
+		Slug      string               `json:"slug,omitempty"`
+		Email     string               `json:"email"`
+		Roles     []constants.UserRole `json:"roles"`
+		Status    constants.UserStatus `json:"status"`
+		CreatedAt time.Time            `json:"created_at"`
+	}
+
+	archiveTerm struct {
+		ID          string      `json:"id"`
+		Name        string      `json:"name"`
+		Slug        string      `json:"slug"`
+		Description string      `json:"description,omitempty"`
+		ImageURL    string      `json:"image_url,omitempty"`
+		ParentID    *string     `json:"parent_id,omitempty"`
+		Path        string      `json:"path,omitempty"`
+		CreatedByID string      `json:"created_by_id,omitempty"`
+		EditedByID  *string     `json:"edited_by_id,omitempty"`
+		CreatedAt   time.Time   `json:"created_at"`
+		UpdatedAt   time.Time   `json:"updated_at"`
+		Seo         *archiveSeo `json:"seo,omitempty"`
+	}
+
+	archiveSeo struct {
+		MetaTitle       string `json:"meta_title,omitempty" yaml:"meta_title,omitempty"`
+		MetaDescription string `json:"meta_description,omitempty" yaml:"meta_description,omitempty"`
+		CanonicalURL    string `json:"canonical_url,omitempty" yaml:"canonical_url,omitempty"`
+		OgImage         string `json:"og_image,omitempty" yaml:"og_image,omitempty"`
+		NoIndex         bool   `json:"noindex,omitempty" yaml:"noindex,omitempty"`
+	}
+
+	// archiveArticle is the front matter written above each article body
+	archiveArticle struct {
+		ID          string                  `yaml:"id"`
+		Title       string                  `yaml:"title"`
+		Slug        string                  `yaml:"slug"`
+		Excerpt     string                  `yaml:"excerpt,omitempty"`
+		Generated   bool                    `yaml:"excerpt_generated,omitempty"`
+		ImageURL    string                  `yaml:"image_url,omitempty"`
+		CategoryID  string                  `yaml:"category_id"`
+		AuthorID    string                  `yaml:"author_id"`
+		Status      constants.ArticleStatus `yaml:"status"`
+		Views       int64                   `yaml:"views"`
+		Source      string                  `yaml:"source,omitempty"`
+		Featured    *int                    `yaml:"featured,omitempty"`
+		PublishedAt *time.Time              `yaml:"published_at,omitempty"`
+		UnpublishAt *time.Time              `yaml:"unpublish_at,omitempty"`
+		CreatedAt   time.Time               `yaml:"created_at"`
+		UpdatedAt   time.Time               `yaml:"updated_at"`
+		TagIDs      []string                `yaml:"tag_ids,omitempty"`
+		Seo         *archiveSeo             `yaml:"seo,omitempty"`
+		// SeoGenerated names the seo fields generated from the article, like meta_title
+		SeoGenerated []string `yaml:"seo_generated,omitempty"`
+	}
+)
+
+func (s *archiveService) Export(ctx context.Context, w io.Writer) error {
+	articles, err := s.archiveRepo.ListAllArticles(ctx)
+	if err != nil {
+		return err
+	}
+	categories, err := s.archiveRepo.ListAllCategories(ctx)
+	if err != nil {
+		return err
+	}
+	tags, err := s.archiveRepo.ListAllTags(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Collect every user referenced by the exported content
+	userIDs := make(map[string]bool)
+	for _, a := range articles {
+		userIDs[a.AuthorID] = true
+	}
+	for _, c := range categories {
+		collectTermUsers(userIDs, c.CreatedByID, c.EditedByID)
+	}
+	for _, t := range tags {
+		collectTermUsers(userIDs, t.CreatedByID, t.EditedByID)
+	}
+	ids := make([]string, 0, len(userIDs))
+	for id := range userIDs {
+		ids = append(ids, id)
+	}
+	users, err := s.archiveRepo.ListUsersByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	var termImages []string
+	for _, c := range categories {
+		termImages = append(termImages, c.ImageURL, c.OgImage)
+	}
+	for _, t := range tags {
+		termImages = append(termImages, t.ImageURL, t.OgImage)
+	}
+	files := referencedUploads(articles, termImages...)
+
+	zw := zip.NewWriter(w)
+
+	manifest := archiveManifest{
+		Version:    archiveVersion,
+		ExportedAt: time.Now(),
+		Authors:    len(users),
+		Categories: len(categories),
+		Tags:       len(tags),
+		Articles:   len(articles),
+		Files:      len(files),
+	}
+	if err := writeZipJSON(zw, archiveManifestFile, manifest); err != nil {
+		return err
+	}
+
+	authors := make([]archiveAuthor, len(users))
+	for i, u := range users {
+		authors[i] = archiveAuthor{
+			ID:        u.ID,
+			Name:      u.Name,
+			Slug:      u.Slug,
+			Email:     u.Email,
+			Roles:     u.Roles,
+			Status:    u.Status,
+			CreatedAt: u.CreatedAt,
+		}
+	}
+	if err := writeZipJSON(zw, archiveAuthorsFile, authors); err != nil {
+		return err
+	}
+
+	cats := make([]archiveTerm, len(categories))
+	for i, c := range categories {
+		cats[i] = archiveTerm{ID: c.ID, Name: c.Name, Slug: c.Slug, Description: c.Description, ImageURL: c.ImageURL, ParentID: c.ParentID, Path: c.Path, CreatedByID: c.CreatedByID, EditedByID: c.EditedByID, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt, Seo: newArchiveSeo(c.SeoMeta)}
+	}
+	if err := writeZipJSON(zw, archiveCategoriesFile, cats); err != nil {
+		return err
+	}
+
+	terms := make([]archiveTerm, len(tags))
+	for i, t := range tags {
+		terms[i] = archiveTerm{ID: t.ID, Name: t.Name, Slug: t.Slug, Description: t.Description, ImageURL: t.ImageURL, CreatedByID: t.CreatedByID, EditedByID: t.EditedByID, CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt, Seo: newArchiveSeo(t.SeoMeta)}
+	}
+	if err := writeZipJSON(zw, archiveTagsFile, terms); err != nil {
+		return err
+	}
+
+	for i := range articles {
+		body, err := marshalArticle(&articles[i])
+		if err != nil {
+			return err
+		}
+		f, err := zw.Create(archiveArticlesDir + articles[i].Slug + ".md")
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(body); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range files {
+		if err := copyUploadToZip(ctx, zw, name); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func (s *archiveService) Import(ctx context.Context, r io.ReaderAt, size int64) (response.ArchiveImport, error) {
+	var res response.ArchiveImport
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return res, internal_err.NewDefaultError(http.StatusBadRequest, "invalid archive: "+err.Error())
+	}
+
+	entries := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		entries[f.Name] = f
+	}
+
+	var manifest archiveManifest
+	if err := readZipJSON(entries, archiveManifestFile, &manifest); err != nil {
+		return res, err
+	}
+	if manifest.Version != archiveVersion {
+		return res, internal_err.NewDefaultError(http.StatusBadRequest, fmt.Sprintf("unsupported archive version %d", manifest.Version))
+	}
+
+	var (
+		authors    []archiveAuthor
+		categories []archiveTerm
+		tags       []archiveTerm
+	)
+	if err := readZipJSON(entries, archiveAuthorsFile, &authors); err != nil {
+		return res, err
+	}
+	if err := readZipJSON(entries, archiveCategoriesFile, &categories); err != nil {
+		return res, err
+	}
+	if err := readZipJSON(entries, archiveTagsFile, &tags); err != nil {
+		return res, err
+	}
+
+	articles := make([]domain.BlogArtikel, 0, manifest.Articles)
+	articleTags := make([]domain.ArticleTag, 0)
+	for _, f := range zr.File {
+		if !strings.HasPrefix(f.Name, archiveArticlesDir) || !strings.HasSuffix(f.Name, ".md") {
+			continue
+		}
+		raw, err := readZipFile(f)
+		if err != nil {
+			return res, err
+		}
+		article, tagIDs, err := unmarshalArticle(raw)
+		if err != nil {
+			return res, internal_err.NewDefaultError(http.StatusBadRequest, fmt.Sprintf("invalid article %s: %s", f.Name, err.Error()))
+		}
+		articles = append(articles, *article)
+		for _, tagID := range tagIDs {
+			articleTags = append(articleTags, domain.ArticleTag{ArticleID: article.ID, TagID: tagID})
+		}
+	}
+
+	err = database.RunInTx(ctx, database.GetDB(), &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		empty, err := s.archiveRepo.IsContentEmpty(ctx)
+		if err != nil {
+			return err
+		}
+		if !empty {
+			return internal_err.NewDefaultError(http.StatusConflict, "archive can only be imported into an empty database")
+		}
+
+		// Authors may already exist (e.g. the admin running the import), so
+		// match on ID first, then on email, and only insert the rest.
+		userMap := make(map[string]string, len(authors))
+		for _, a := range authors {
+			exists, err := s.archiveRepo.UserExists(ctx, a.ID)
+			if err != nil {
+				return err
+			}
+			if exists {
+				userMap[a.ID] = a.ID
+				continue
+			}
+			if a.Email != "" {
+				existing, err := s.archiveRepo.GetUserByEmail(ctx, a.Email)
+				if err != nil {
+					return err
+				}
+				if existing != nil {
+					userMap[a.ID] = existing.ID
+					continue
+				}
+			}
+			slug, err := utils.GenerateUniqueSlug(ctx, utils.SlugCheckerFunc(s.archiveRepo.UserSlugExists), utils.Fallback(a.Slug, a.Name, a.Slug != ""))
+			if err != nil {
+				return err
+			}
+			user := &domain.User{
+				ID:        a.ID,
+				Name:      a.Name,
+				Slug:      slug,
+				Email:     a.Email,
+				Roles:     a.Roles,
+				Status:    a.Status,
+				CreatedAt: a.CreatedAt,
+			}
+			if err := s.archiveRepo.InsertUser(ctx, user); err != nil {
+				return err
+			}
+			userMap[a.ID] = a.ID
+			res.Authors++
+		}
+
+		cats := make([]domain.Category, len(categories))
+		for i, c := range categories {
+			cats[i] = domain.Category{
+				BaseEntity:  domain.BaseEntity{ID: c.ID, CreatedAt: c.CreatedAt},
+				Name:        c.Name,
+				Slug:        c.Slug,
+				Description: c.Description,
+				ImageURL:    c.ImageURL,
+				ParentID:    c.ParentID,
+				Path:        utils.Fallback(c.Path, c.Slug, c.Path != ""),
+				CreatedByID: userMap[c.CreatedByID],
+				EditedByID:  remapUser(userMap, c.EditedByID),
+				SeoMeta:     c.Seo.toDomain(),
+			}
+		}
+		if err := s.archiveRepo.InsertCategories(ctx, cats); err != nil {
+			return err
+		}
+		res.Categories = len(cats)
+
+		terms := make([]domain.Tag, len(tags))
+		for i, t := range tags {
+			terms[i] = domain.Tag{
+				BaseEntity:  domain.BaseEntity{ID: t.ID, CreatedAt: t.CreatedAt},
+				Name:        t.Name,
+				Slug:        t.Slug,
+				Description: t.Description,
+				ImageURL:    t.ImageURL,
+				CreatedByID: userMap[t.CreatedByID],
+				EditedByID:  remapUser(userMap, t.EditedByID),
+				SeoMeta:     t.Seo.toDomain(),
+			}
+		}
+		if err := s.archiveRepo.InsertTags(ctx, terms); err != nil {
+			return err
+		}
+		res.Tags = len(terms)
+
+		for i := range articles {
+			if mapped, ok := userMap[articles[i].AuthorID]; ok {
+				articles[i].AuthorID = mapped
+			}
+			if err := s.archiveRepo.InsertArticle(ctx, &articles[i]); err != nil {
+				return err
+			}
+		}
+		res.Articles = len(articles)
+
+		return s.archiveRepo.InsertArticleTags(ctx, articleTags)
+	})
+	if err != nil {
+		return res, err
+	}
+
+	// Restore files only once the content is committed
+	for _, f := range zr.File {
+		if !strings.HasPrefix(f.Name, archiveUploadsDir) || f.FileInfo().IsDir() {
+			continue
+		}
+		written, err := restoreUpload(ctx, f)
+		if err != nil {
+			return res, err
+		}
+		if written {
+			res.Files++
+		}
+	}
+
+	return res, nil
+}
+
+func collectTermUsers(ids map[string]bool, createdBy string, editedBy *string) {
+	if createdBy != "" {
+		ids[createdBy] = true
+	}
+	if editedBy != nil && *editedBy != "" {
+		ids[*editedBy] = true
+	}
+}
+
+func remapUser(userMap map[string]string, id *string) *string {
+	if id == nil {
+		return nil
+	}
+	if mapped, ok := userMap[*id]; ok {
+		return &mapped
+	}
+	return nil
+}
+
+// referencedUploads returns the local upload filenames used as cover or inline images,
+// and those among the extra images of categories and tags
+func referencedUploads(articles []domain.BlogArtikel, images ...string) []string {
+	seen := make(map[string]bool)
+	var res []string
+	add := func(name string) {
+		name = path.Base(name)
+		if name == "" || name == "." || name == "/" || seen[name] {
+			return
+		}
+		seen[name] = true
+		res = append(res, name)
+	}
+
+	for _, a := range articles {
+		if a.ImageURL != "" && !strings.Contains(a.ImageURL, "://") {
+			add(a.ImageURL)
+		}
+		if a.OgImage != "" && !strings.Contains(a.OgImage, "://") {
+			add(a.OgImage)
+		}
+		for _, match := range uploadRefPattern.FindAllStringSubmatch(a.Content, -1) {
+			add(match[1])
+		}
+	}
+	for _, image := range images {
+		if image != "" && !strings.Contains(image, "://") {
+			add(image)
+		}
+	}
+	return res
+}
+
+func marshalArticle(a *domain.BlogArtikel) ([]byte, error) {
+	meta := archiveArticle{
+		ID:         a.ID,
+		Title:      a.Title,
+		Slug:       a.Slug,
+		Excerpt:    a.Excerpt,
+		Generated:  a.ExcerptGenerated,
+		ImageURL:   a.ImageURL,
+		CategoryID: a.CategoryID,
+		AuthorID:   a.AuthorID,
+		Status:     a.Status,
+		Views:      a.Views,
+		Source:     a.Source,
+		Featured:   a.Featured,
+		CreatedAt:  a.CreatedAt,
+		UpdatedAt:  a.UpdatedAt,
+		Seo:        newArchiveSeo(a.SeoMeta),
+	}
+	meta.SeoGenerated = archiveSeoGenerated(a.SeoGenerated)
+	if !a.PublishedAt.IsZero() {
+		meta.PublishedAt = &a.PublishedAt
+	}
+	if !a.UnpublishAt.IsZero() {
+		meta.UnpublishAt = &a.UnpublishAt
+	}
+	for _, t := range a.Tags {
+		meta.TagIDs = append(meta.TagIDs, t.ID)
+	}
+
+	head, err := yaml.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterDelimiter + "\n")
+	buf.Write(head)
+	buf.WriteString(frontMatterDelimiter + "\n\n")
+	buf.WriteString(a.Content)
+	return buf.Bytes(), nil
+}
+
+func unmarshalArticle(raw []byte) (*domain.BlogArtikel, []string, error) {
+	content := strings.ReplaceAll(string(raw), "\r\n", "\n")
+	if !strings.HasPrefix(content, frontMatterDelimiter+"\n") {
+		return nil, nil, fmt.Errorf("missing front matter")
+	}
+	rest := content[len(frontMatterDelimiter)+1:]
+	end := strings.Index(rest, "\n"+frontMatterDelimiter+"\n")
+	if end < 0 {
+		return nil, nil, fmt.Errorf("unterminated front matter")
+	}
+
+	var meta archiveArticle
+	if err := yaml.Unmarshal([]byte(rest[:end]), &meta); err != nil {
+		return nil, nil, err
+	}
+	if meta.ID == "" || meta.Slug == "" {
+		return nil, nil, fmt.Errorf("id and slug are required")
+	}
+
+	body := strings.TrimPrefix(rest[end+len(frontMatterDelimiter)+2:], "\n")
+
+	article := &domain.BlogArtikel{
+		BaseEntity: domain.BaseEntity{ID: meta.ID, CreatedAt: meta.CreatedAt},
+		Title:      meta.Title,
+		Slug:       meta.Slug,
+		Content:    body,
+		Excerpt:    meta.Excerpt,
+		ImageURL:   meta.ImageURL,
+		CategoryID: meta.CategoryID,
+		AuthorID:   meta.AuthorID,
+		Status:     meta.Status,
+		Views:      meta.Views,
+		Source:     archiveSource(meta.Source),
+		Featured:   meta.Featured,
+		SeoMeta:    meta.Seo.toDomain(),
+	}
+	article.ExcerptGenerated = meta.Generated
+	article.SeoGenerated = domain.SeoGenerated{
+		MetaTitleGenerated:       utils.Contains(meta.SeoGenerated, "meta_title"),
+		MetaDescriptionGenerated: utils.Contains(meta.SeoGenerated, "meta_description"),
+		OgImageGenerated:         utils.Contains(meta.SeoGenerated, "og_image"),
+	}
+	if meta.PublishedAt != nil {
+		article.PublishedAt = *meta.PublishedAt
+	}
+	if meta.UnpublishAt != nil {
+		article.UnpublishAt = *meta.UnpublishAt
+	}
+	return article, meta.TagIDs, nil
+}
+
+func archiveSource(source string) string {
+	if source == "" {
+		return "-"
+	}
+	return source
+}
+
+func writeZipJSON(zw *zip.Writer, name string, v any) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func readZipJSON(entries map[string]*zip.File, name string, v any) error {
+	f, ok := entries[name]
+	if !ok {
+		return internal_err.NewDefaultError(http.StatusBadRequest, "invalid archive: missing "+name)
+	}
+	raw, err := readZipFile(f)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return internal_err.NewDefaultError(http.StatusBadRequest, fmt.Sprintf("invalid archive: %s: %s", name, err.Error()))
+	}
+	return nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func copyUploadToZip(ctx context.Context, zw *zip.Writer, name string) error {
+	src, err := storage.GetDriver().Open(ctx, name)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil // referenced file is already gone, nothing to export
+		}
+		return err
+	}
+	defer src.Close()
+
+	dst, err := zw.Create(archiveUploadsDir + name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+// restoreUpload writes an archived file into storage, keeping
+// any file that already exists with the same name
+func restoreUpload(ctx context.Context, f *zip.File) (bool, error) {
+	name := filepath.Base(f.Name)
+	if name == "." || name == string(filepath.Separator) {
+		return false, nil
+	}
+	driver := storage.GetDriver()
+	if exists, err := driver.Exists(ctx, name); err != nil || exists {
+		return false, err
+	}
+
+	src, err := f.Open()
+	if err != nil {
+		return false, err
+	}
+	defer src.Close()
+
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if err := driver.Put(ctx, name, src, int64(f.UncompressedSize64), contentType); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// newArchiveSeo returns nil for empty metadata so it is left out of the archive
+func newArchiveSeo(seo domain.SeoMeta) *archiveSeo {
+	if seo == (domain.SeoMeta{}) {
+		return nil
+	}
+	return &archiveSeo{
+		MetaTitle:       seo.MetaTitle,
+		MetaDescription: seo.MetaDescription,
+		CanonicalURL:    seo.CanonicalURL,
+		OgImage:         seo.OgImage,
+		NoIndex:         seo.NoIndex,
+	}
+}
+
+func archiveSeoGenerated(generated domain.SeoGenerated) []string {
+	var res []string
+	if generated.MetaTitleGenerated {
+		res = append(res, "meta_title")
+	}
+	if generated.MetaDescriptionGenerated {
+		res = append(res, "meta_description")
+	}
+	if generated.OgImageGenerated {
+		res = append(res, "og_image")
+	}
+	return res
+}
+
+func (a *archiveSeo) toDomain() domain.SeoMeta {
+	if a == nil {
+		return domain.SeoMeta{}
+	}
+	return domain.SeoMeta{
+		MetaTitle:       a.MetaTitle,
+		MetaDescription: a.MetaDescription,
+		CanonicalURL:    a.CanonicalURL,
+		OgImage:         a.OgImage,
+		NoIndex:         a.NoIndex,
+	}
+}
@@ -4,9 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"os"
 
 	"net/http"
+	"slices"
 	"sora_landing_be/cmd/constants"
 	"sora_landing_be/cmd/domain"
 	"sora_landing_be/cmd/dto"
@@ -16,32 +16,44 @@ import (
 	"sora_landing_be/pkg/database"
 	internal_err "sora_landing_be/pkg/errors"
 	"sora_landing_be/pkg/utils"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/go-shiori/go-readability"
 	"github.com/uptrace/bun"
 )
 
 type BlogService interface {
 	// Create and Update operations
 	CreateArticle(ctx context.Context, userID string, payload requests.BlogArtikel) error
-	CreateArticleFromURL(ctx context.Context, userID string, payload requests.FromURL) error
-	// CreateByLink(ctx context.Context, payl)
+	CreateArticleFromURL(ctx context.Context, userID string, payload requests.FromURL) (response.ExternalImport, error)
+	CreateArticlesFromURLs(ctx context.Context, userID string, payload requests.BatchFromURL) (response.ImportJob, error)
+	ImportExternal(ctx context.Context, userID, rawURL string, opts ExternalImportOptions) (response.ExternalImport, error)
+	ResyncExternalArticle(ctx context.Context, id string) (response.ExternalSync, error)
 	UpdateArticle(ctx context.Context, id string, payload requests.UpdateArtikel) error
 	UpdateArticleStatus(ctx context.Context, id string, payload requests.UpdateArticleStatus) error
 	SetFeaturedPosition(ctx context.Context, articleID string, pos int) error
 	RemoveFeaturedPosition(ctx context.Context, articleID string) error
+	ArchiveExpiredArticles(ctx context.Context) error
 
 	// Read operations
 	GetArticle(ctx context.Context, id string) (response.BlogArticle, error)
 	GetArticleBySlug(ctx context.Context, slug string) (response.BlogArticle, error)
 	ListArticles(ctx context.Context, params requests.ListArtikel) (dto.PaginationResponse[response.BlogArticleList], error)
 	GetArticleStats(ctx context.Context) (dto.BlogStats, error)
+	GetImportJob(ctx context.Context, id string) (response.ImportJob, error)
+	SuggestTags(ctx context.Context, payload requests.SuggestTags) (response.TagSuggestions, error)
 
 	// Public endpoints
 	ListPublicArticles(ctx context.Context, params requests.ListArtikel) (dto.PaginationResponse[response.PublicArticleList], error)
+	ListPublicArticlesCursor(ctx context.Context, params requests.ListArtikel) (dto.CursorPaginationResponse[response.PublicArticleList], error)
+	GetBlogArchive(ctx context.Context) ([]response.BlogArchiveYear, error)
+	ListArchiveArticles(ctx context.Context, period requests.ArchivePeriod, params requests.ListArtikel) (dto.PaginationResponse[response.PublicArticleList], error)
 	GetPublicArticleBySlug(ctx context.Context, slug string) (response.PublicArticleDetail, error)
 	GetFeaturedArticle(ctx context.Context) ([]response.PublicArticleList, error)
+	GetPublicArticleSchema(ctx context.Context, slug string) (*response.StructuredData, error)
+	GetSharePage(ctx context.Context, slug string) (response.SharePage, error)
+	ListPublicArticlesSchema(ctx context.Context, params requests.ListArtikel) (*response.StructuredData, error)
 
 	// Tag operations
 	UpdateArticleTags(ctx context.Context, articleID string, tagIDs []string) error
@@ -52,16 +64,22 @@ type BlogService interface {
 }
 
 type blogService struct {
-	blogRepo repository.BlogRepository
-	tagRepo  repository.TagRepository
-	catRepo  repository.CategoryRepository
+	blogRepo   repository.BlogRepository
+	tagRepo    repository.TagRepository
+	catRepo    repository.CategoryRepository
+	importRepo repository.ImportJobRepository
+	fileRepo   repository.FileRepository
+	corpus     *tagCorpus
 }
 
-func NewBlogService(blogRepo repository.BlogRepository, tagRepo repository.TagRepository, catRepo repository.CategoryRepository) BlogService {
+func NewBlogService(blogRepo repository.BlogRepository, tagRepo repository.TagRepository, catRepo repository.CategoryRepository, importRepo repository.ImportJobRepository, fileRepo repository.FileRepository) BlogService {
 	return &blogService{
-		blogRepo: blogRepo,
-		tagRepo:  tagRepo,
-		catRepo:  catRepo,
+		blogRepo:   blogRepo,
+		tagRepo:    tagRepo,
+		catRepo:    catRepo,
+		importRepo: importRepo,
+		fileRepo:   fileRepo,
+		corpus:     &tagCorpus{},
 	}
 }
 
@@ -75,6 +93,11 @@ func (s *blogService) CreateArticle(ctx context.Context, userID string, payload
 
 		// Convert to domain model
 		article := payload.ToDomain(userID, uniqueSlug)
+		if err := validateUnpublishAt(article.Status, article.PublishedAt, article.UnpublishAt); err != nil {
+			return err
+		}
+		applyExcerpt(article)
+		applySeoDefaults(article, isPublicStatus(article.Status))
 
 		// Create the article
 		err = s.blogRepo.CreateArticle(ctx, article)
@@ -90,117 +113,12 @@ func (s *blogService) CreateArticle(ctx context.Context, userID string, payload
 			}
 		}
 
-		return nil
+		return syncArticleFiles(ctx, s.fileRepo, *article)
 	})
 
 	return err
 }
 
-func (s *blogService) CreateArticleFromURL(ctx context.Context, userID string, payload requests.FromURL) error {
-	return database.RunInTx(ctx, database.GetDB(), &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-
-		// --- 1. Ensure "external" category exists ---
-		cat, err := s.catRepo.GetCategoryByName(ctx, "external")
-		if err != nil {
-			return err
-		}
-
-		if cat == nil {
-			slug, err := utils.GenerateUniqueSlug(ctx, s.catRepo, "external")
-			if err != nil {
-				return err
-			}
-
-			newCat := &domain.Category{
-				Name:        "external",
-				Slug:        slug,
-				CreatedByID: userID,
-			}
-
-			catID, err := s.catRepo.CreateCategoryReturnID(ctx, newCat)
-			if err != nil {
-				return err
-			}
-
-			cat = &domain.Category{
-				BaseEntity: domain.BaseEntity{ID: catID},
-				Name:       "external",
-				Slug:       slug,
-			}
-		}
-		extractedArticle, err := readability.FromURL(payload.URL, 30*time.Second)
-		if err != nil {
-			return err
-		}
-		// --- 2. Ensure "external" tag exists ---
-		tag, err := s.tagRepo.GetTagByName(ctx, extractedArticle.SiteName)
-		if err != nil {
-			return err
-		}
-
-		if tag == nil {
-			slugg, err := utils.GenerateUniqueSlug(ctx, s.tagRepo, extractedArticle.SiteName)
-			if err != nil {
-				return err
-			}
-
-			newTag := &domain.Tag{
-				Name:        extractedArticle.SiteName,
-				Slug:        slugg,
-				CreatedByID: userID,
-			}
-
-			tagID, err := s.tagRepo.CreateTagReturnID(ctx, newTag)
-			if err != nil {
-				return err
-			}
-
-			tag = &domain.Tag{
-				BaseEntity: domain.BaseEntity{ID: tagID},
-				Name:       extractedArticle.SiteName,
-				Slug:       slugg,
-			}
-		}
-
-		// --- 3. Fetch article from URL ---
-
-		// --- 4. Generate unique slug for the article ---
-		uniqueSlug, err := utils.GenerateUniqueSlug(ctx, s.blogRepo, extractedArticle.Title)
-		if err != nil {
-			return err
-		}
-
-		// --- 5. Convert to domain model ---
-		articleDomain := &domain.BlogArtikel{
-			Title:       extractedArticle.Title,
-			Slug:        uniqueSlug,
-			Content:     extractedArticle.Content,
-			Excerpt:     extractedArticle.Excerpt,
-			CategoryID:  cat.ID,
-			ImageURL:    extractedArticle.Image,
-			AuthorID:    userID,
-			Status:      constants.StatusPublished,
-			PublishedAt: time.Now(),
-			Tags:        []*domain.Tag{}, // start empty
-			Source:      payload.URL,
-		}
-
-		// --- 6. Save article ---
-		if err := s.blogRepo.CreateArticlefromURL(ctx, articleDomain); err != nil {
-			return err
-		}
-
-		// --- 7. Add "external" tag to article ---
-		if tag != nil && tag.ID != "" {
-			if err := s.blogRepo.AddArticleTags(ctx, articleDomain.ID, []string{tag.ID}); err != nil {
-				return err
-			}
-		}
-
-		return nil
-	})
-}
-
 func (s *blogService) UpdateArticle(ctx context.Context, id string, payload requests.UpdateArtikel) error {
 	err := database.RunInTx(ctx, database.GetDB(), &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
 		// Get existing article
@@ -223,21 +141,39 @@ func (s *blogService) UpdateArticle(ctx context.Context, id string, payload requ
 		} else {
 			uniqueSlug = existing.Slug
 		}
-		if existing.ImageURL != *payload.ImageURL && *payload.ImageURL != "" {
-			os.Remove("./uploads/" + existing.ImageURL)
-		}
-
 		// Update article
 		article := payload.ToDomain(existing.AuthorID, uniqueSlug)
 		article.ID = id
 		article.Views = existing.Views
 		article.PublishedAt = existing.PublishedAt
+		article.UnpublishAt = resolveUnpublishAt(existing.UnpublishAt, payload.UnpublishAt, payload.ClearUnpublishAt)
+		if err := validateUnpublishAt(utils.Fallback(article.Status, existing.Status, article.Status != ""), article.PublishedAt, article.UnpublishAt); err != nil {
+			return err
+		}
+		article.Excerpt, article.ExcerptGenerated = nextExcerpt(existing, payload.Excerpt, payload.ClearExcerpt, utils.Fallback(article.Content, existing.Content, article.Content != ""))
 
 		err = s.blogRepo.UpdateArticle(ctx, article)
 		if err != nil {
 			return err
 		}
 
+		// the update skips zero values, a cleared expiry and an excerpt turning authored or
+		// empty are written apart
+		if !article.UnpublishAt.Equal(existing.UnpublishAt) {
+			if err = s.blogRepo.UpdateArticleUnpublishAt(ctx, id, article.UnpublishAt); err != nil {
+				return err
+			}
+		}
+		if article.Excerpt != existing.Excerpt || article.ExcerptGenerated != existing.ExcerptGenerated {
+			if err = s.blogRepo.UpdateArticleExcerpt(ctx, id, article.Excerpt, article.ExcerptGenerated); err != nil {
+				return err
+			}
+		}
+
+		if err = s.updateArticleSeo(ctx, existing, article, payload.Seo); err != nil {
+			return err
+		}
+
 		// Update tags if provided
 		if payload.TagIDs != nil {
 			err = s.blogRepo.ClearArticleTags(ctx, id)
@@ -253,6 +189,18 @@ func (s *blogService) UpdateArticle(ctx context.Context, id string, payload requ
 			}
 		}
 
+		// the files in use follow the cover and content the article ends up with
+		effective := *article
+		effective.ImageURL = utils.Fallback(article.ImageURL, existing.ImageURL, article.ImageURL != "")
+		effective.Content = utils.Fallback(article.Content, existing.Content, article.Content != "")
+		if err = syncArticleFiles(ctx, s.fileRepo, effective); err != nil {
+			return err
+		}
+
+		// a replaced cover may still be used elsewhere, the sweep removes it once nothing does
+		if effective.ImageURL != existing.ImageURL {
+			return releaseUpload(ctx, s.fileRepo, existing.ImageURL)
+		}
 		return nil
 	})
 
@@ -286,8 +234,63 @@ func (s *blogService) UpdateArticleStatus(ctx context.Context, id string, payloa
 		publishAt = &article.PublishedAt
 	}
 
+	// an archived promo being republished needs a new expiry or none at all
+	unpublishAt := resolveUnpublishAt(article.UnpublishAt, payload.UnpublishAt, payload.ClearUnpublishAt)
+	var live time.Time
+	if publishAt != nil {
+		live = *publishAt
+	}
+	if err := validateUnpublishAt(payload.Status, live, unpublishAt); err != nil {
+		return err
+	}
+
 	// Update status and possibly publishAt
-	return s.blogRepo.UpdateArticleStatus(ctx, id, payload.Status, publishAt)
+	if err := s.blogRepo.UpdateArticleStatus(ctx, id, payload.Status, publishAt); err != nil {
+		return err
+	}
+	if !unpublishAt.Equal(article.UnpublishAt) {
+		if err := s.blogRepo.UpdateArticleUnpublishAt(ctx, id, unpublishAt); err != nil {
+			return err
+		}
+	}
+
+	if isPublicStatus(payload.Status) {
+		updated := article
+		applySeoDefaults(&updated, true)
+		if updated.SeoMeta != article.SeoMeta || updated.SeoGenerated != article.SeoGenerated {
+			return s.blogRepo.UpdateArticleSeo(ctx, id, updated.SeoMeta, updated.SeoGenerated)
+		}
+	}
+	return nil
+}
+
+// updateArticleSeo stores the SEO metadata after an update and leaves it on updated. The
+// request replaces the current metadata when it contains any, generated fields follow the
+// updated article and defaults are filled in when it is public.
+func (s *blogService) updateArticleSeo(ctx context.Context, existing domain.BlogArtikel, updated *domain.BlogArtikel, payload *requests.SeoMeta) error {
+	// the update only writes non-empty fields, so the effective article is the existing one
+	// with the updated values on top, the excerpt is always decided by the update
+	effective := existing
+	effective.Title = utils.Fallback(updated.Title, existing.Title, updated.Title != "")
+	effective.Content = utils.Fallback(updated.Content, existing.Content, updated.Content != "")
+	effective.Excerpt = updated.Excerpt
+	effective.ImageURL = utils.Fallback(updated.ImageURL, existing.ImageURL, updated.ImageURL != "")
+	effective.Status = utils.Fallback(updated.Status, existing.Status, updated.Status != "")
+
+	if payload != nil {
+		requested := payload.ToDomain()
+		effective.SeoMeta = requested
+		effective.MetaTitle, effective.MetaTitleGenerated = nextSeoField(existing.MetaTitle, existing.MetaTitleGenerated, requested.MetaTitle)
+		effective.MetaDescription, effective.MetaDescriptionGenerated = nextSeoField(existing.MetaDescription, existing.MetaDescriptionGenerated, requested.MetaDescription)
+		effective.OgImage, effective.OgImageGenerated = nextSeoField(existing.OgImage, existing.OgImageGenerated, requested.OgImage)
+	}
+	applySeoDefaults(&effective, isPublicStatus(effective.Status))
+
+	updated.SeoMeta, updated.SeoGenerated = effective.SeoMeta, effective.SeoGenerated
+	if effective.SeoMeta == existing.SeoMeta && effective.SeoGenerated == existing.SeoGenerated {
+		return nil
+	}
+	return s.blogRepo.UpdateArticleSeo(ctx, existing.ID, effective.SeoMeta, effective.SeoGenerated)
 }
 
 func (s *blogService) GetArticle(ctx context.Context, id string) (response.BlogArticle, error) {
@@ -298,7 +301,12 @@ func (s *blogService) GetArticle(ctx context.Context, id string) (response.BlogA
 		return res, err
 	}
 
+	images, err := coverImages(ctx, s.fileRepo, article)
+	if err != nil {
+		return res, err
+	}
 	res.FromDomain(&article)
+	res.Image = images[article.ImageURL]
 	return res, nil
 }
 
@@ -312,7 +320,12 @@ func (s *blogService) GetArticleBySlug(ctx context.Context, slug string) (respon
 
 	// Increment views asynchronously
 
+	images, err := coverImages(ctx, s.fileRepo, article)
+	if err != nil {
+		return res, err
+	}
 	res.FromDomain(&article)
+	res.Image = images[article.ImageURL]
 	return res, nil
 }
 
@@ -324,11 +337,17 @@ func (s *blogService) ListArticles(ctx context.Context, params requests.ListArti
 		return paginateRes, err
 	}
 
+	images, err := coverImages(ctx, s.fileRepo, articles...)
+	if err != nil {
+		return paginateRes, err
+	}
+
 	// Convert domain models to response DTOs
 	list := make([]response.BlogArticleList, len(articles))
 	for i, article := range articles {
 		var item response.BlogArticleList
 		item.FromDomain(&article)
+		item.Image = images[article.ImageURL]
 		list[i] = item
 	}
 
@@ -403,16 +422,25 @@ func (s *blogService) HardDeleteArticle(ctx context.Context, id string) error {
 func (s *blogService) ListPublicArticles(ctx context.Context, params requests.ListArtikel) (dto.PaginationResponse[response.PublicArticleList], error) {
 	var paginateRes dto.PaginationResponse[response.PublicArticleList]
 
+	if err := s.resolveTagSlugs(ctx, &params); err != nil {
+		return paginateRes, err
+	}
 	articles, count, err := s.blogRepo.ListPublicArticles(ctx, params)
 	if err != nil {
 		return paginateRes, err
 	}
 
+	images, err := coverImages(ctx, s.fileRepo, articles...)
+	if err != nil {
+		return paginateRes, err
+	}
+
 	// Convert domain models to response DTOs
 	list := make([]response.PublicArticleList, len(articles))
 	for i, article := range articles {
 		var item response.PublicArticleList
 		item.FromDomain(&article)
+		item.Image = images[article.ImageURL]
 		list[i] = item
 	}
 
@@ -420,7 +448,127 @@ func (s *blogService) ListPublicArticles(ctx context.Context, params requests.Li
 	return paginateRes, nil
 }
 
+func (s *blogService) ListPublicArticlesCursor(ctx context.Context, params requests.ListArtikel) (dto.CursorPaginationResponse[response.PublicArticleList], error) {
+	var res dto.CursorPaginationResponse[response.PublicArticleList]
+
+	params.SortBy = utils.Fallback(params.SortBy, "published_at", params.SortBy != "")
+	params.SortOrder = utils.Fallback(params.SortOrder, "desc", params.SortOrder != "")
+	desc := params.SortOrder == "desc"
+	if params.PageSize < 1 {
+		params.PageSize = 10
+	} else if params.PageSize > constants.CursorMaxPageSize {
+		params.PageSize = constants.CursorMaxPageSize
+	}
+
+	var cursor *dto.Cursor
+	if params.Cursor != "" {
+		c, err := dto.DecodeCursor(params.Cursor)
+		if err != nil {
+			return res, internal_err.NewDefaultError(http.StatusBadRequest, "invalid cursor")
+		}
+		if c.SortBy != params.SortBy || c.Desc != desc {
+			return res, internal_err.NewDefaultError(http.StatusBadRequest, "cursor does not match the requested sort")
+		}
+		cursor = &c
+	}
+	if err := s.resolveTagSlugs(ctx, &params); err != nil {
+		return res, err
+	}
+
+	// One extra row tells whether another page exists in the scan direction
+	articles, err := s.blogRepo.ListPublicArticlesCursor(ctx, params, cursor, params.PageSize+1)
+	if err != nil {
+		if errors.Is(err, dto.ErrInvalidCursor) {
+			return res, internal_err.NewDefaultError(http.StatusBadRequest, "invalid cursor")
+		}
+		return res, err
+	}
+
+	backward := cursor != nil && cursor.Backward
+	more := len(articles) > params.PageSize
+	if more {
+		articles = articles[:params.PageSize]
+	}
+	if backward {
+		slices.Reverse(articles)
+	}
+
+	res.PageSize = params.PageSize
+	res.HasNext = backward || more
+	res.HasPrevious = (backward && more) || (!backward && cursor != nil)
+	images, err := coverImages(ctx, s.fileRepo, articles...)
+	if err != nil {
+		return res, err
+	}
+	res.Data = make([]response.PublicArticleList, len(articles))
+	for i, article := range articles {
+		res.Data[i].FromDomain(&article)
+		res.Data[i].Image = images[article.ImageURL]
+	}
+	if len(articles) == 0 {
+		return res, nil
+	}
+
+	if res.HasNext {
+		next := articleCursor(articles[len(articles)-1], params.SortBy, desc, false)
+		res.NextCursor = &next
+	}
+	if res.HasPrevious {
+		prev := articleCursor(articles[0], params.SortBy, desc, true)
+		res.PrevCursor = &prev
+	}
+	return res, nil
+}
+
+// resolveTagSlugs replaces slugs of merged or renamed tags in the listing filters with the current ones
+func (s *blogService) resolveTagSlugs(ctx context.Context, params *requests.ListArtikel) error {
+	included, excluded := params.TagSlugs(), params.ExcludedTagSlugs()
+	if len(included)+len(excluded) == 0 {
+		return nil
+	}
+	redirects, err := s.tagRepo.GetRedirectedSlugs(ctx, append(included, excluded...))
+	if err != nil || len(redirects) == 0 {
+		return err
+	}
+	for i, slug := range included {
+		included[i] = utils.Fallback(redirects[slug], slug, redirects[slug] != "")
+	}
+	for i, slug := range excluded {
+		excluded[i] = utils.Fallback(redirects[slug], slug, redirects[slug] != "")
+	}
+	params.Tags = strings.Join(included, ",")
+	params.ExcludeTags = strings.Join(excluded, ",")
+	return nil
+}
+
+// articleCursor encodes the position of an article in a listing sorted by sortBy
+func articleCursor(article domain.BlogArtikel, sortBy string, desc, backward bool) string {
+	var value string
+	switch sortBy {
+	case "published_at":
+		value = utils.Fallback(article.PublishedAt, article.CreatedAt, !article.PublishedAt.IsZero()).Format(time.RFC3339Nano)
+	case "created_at":
+		value = article.CreatedAt.Format(time.RFC3339Nano)
+	case "views":
+		value = strconv.FormatInt(article.Views, 10)
+	case "title":
+		value = article.Title
+	}
+	return dto.Cursor{
+		SortBy:   sortBy,
+		Desc:     desc,
+		Value:    value,
+		ID:       article.ID,
+		Backward: backward,
+	}.Encode()
+}
+
 func (s *blogService) GetPublicArticleBySlug(ctx context.Context, slug string) (response.PublicArticleDetail, error) {
+	return s.getPublicArticle(ctx, slug, true)
+}
+
+// getPublicArticle loads a published article, countView is false for requests that are not reader visits
+func (s *blogService) getPublicArticle(ctx context.Context, slug string, countView bool) (response.PublicArticleDetail, error) {
 	var res response.PublicArticleDetail
 
 	article, related, err := s.blogRepo.GetPublicArticleWithRelated(ctx, slug)
@@ -432,16 +580,45 @@ func (s *blogService) GetPublicArticleBySlug(ctx context.Context, slug string) (
 	}
 
 	// Increment views asynchronously
-	go func() {
-		bgCtx := context.Background()
-		_ = s.blogRepo.IncrementViews(bgCtx, article.ID)
-	}()
+	if countView {
+		go func() {
+			bgCtx := context.Background()
+			_ = s.blogRepo.IncrementViews(bgCtx, article.ID)
+		}()
+	}
+
+	trail, err := s.categoryTrail(ctx, article.Category)
+	if err != nil {
+		return res, err
+	}
+
+	images, err := coverImages(ctx, s.fileRepo, append([]domain.BlogArtikel{article}, related...)...)
+	if err != nil {
+		return res, err
+	}
 
 	// Convert to response DTO with related articles
 	res.FromDomain(&article, related)
+	res.Image = images[article.ImageURL]
+	for i, rel := range related {
+		res.RelatedArticles[i].Image = images[rel.ImageURL]
+	}
+	res.Breadcrumbs = response.NewCategoryBreadcrumbs(trail)
+	res.StructuredData = articleStructuredData(article, trail)
 	return res, nil
 }
 
+// categoryTrail loads the ancestors of a category followed by the category itself
+func (s *blogService) categoryTrail(ctx context.Context, category *domain.Category) ([]domain.Category, error) {
+	if category == nil {
+		return nil, nil
+	}
+	if category.ParentID == nil {
+		return []domain.Category{*category}, nil
+	}
+	return s.catRepo.ListCategoriesByPaths(ctx, categoryAncestorPaths(category.Path))
+}
+
 func (s *blogService) GetFeaturedArticle(ctx context.Context) ([]response.PublicArticleList, error) {
 	articles, err := s.blogRepo.GetFeaturedArticle(ctx)
 
@@ -449,10 +626,16 @@ func (s *blogService) GetFeaturedArticle(ctx context.Context) ([]response.Public
 		return nil, err
 	}
 
+	images, err := coverImages(ctx, s.fileRepo, articles...)
+	if err != nil {
+		return nil, err
+	}
+
 	list := make([]response.PublicArticleList, len(articles))
 	for i, article := range articles {
 		var item response.PublicArticleList
 		item.FromDomain(&article)
+		item.Image = images[article.ImageURL]
 		list[i] = item
 	}
 	return list, nil
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sora_landing_be/cmd/dto"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/dto/response"
+	internal_err "sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/utils"
+	"time"
+)
+
+// GetBlogArchive counts published articles per month of the site calendar, so an article
+// published on 1 Oct 01:00 WIB belongs to October even though it is still September in UTC
+func (s *blogService) GetBlogArchive(ctx context.Context) ([]response.BlogArchiveYear, error) {
+	months, err := s.blogRepo.CountPublishedByMonth(ctx, utils.WIB)
+	if err != nil {
+		return nil, err
+	}
+	return response.NewBlogArchive(months), nil
+}
+
+// ListArchiveArticles lists the published articles of an archive year or month
+func (s *blogService) ListArchiveArticles(ctx context.Context, period requests.ArchivePeriod, params requests.ListArtikel) (dto.PaginationResponse[response.PublicArticleList], error) {
+	now, err := utils.GetCurrentTimeBasedOnLocation(utils.WIB)
+	if err != nil {
+		return dto.PaginationResponse[response.PublicArticleList]{}, err
+	}
+	if period.Year > now.Year() || period.Month < 0 || period.Month > 12 {
+		return dto.PaginationResponse[response.PublicArticleList]{}, internal_err.NewDefaultError(http.StatusNotFound, internal_err.DataNotFound)
+	}
+
+	from := time.Date(period.Year, time.January, 1, 0, 0, 0, 0, now.Location())
+	to := from.AddDate(1, 0, 0)
+	if period.Month != 0 {
+		from = time.Date(period.Year, time.Month(period.Month), 1, 0, 0, 0, 0, now.Location())
+		to = from.AddDate(0, 1, 0)
+	}
+	// the published range is inclusive, stop at the last microsecond postgres can store
+	to = to.Add(-time.Microsecond)
+
+	params.PublishedFrom = &from
+	params.PublishedTo = &to
+	params.IncludeFeatured = true
+	if params.SortBy == "" {
+		params.SortBy = "published_at"
+	}
+	return s.ListPublicArticles(ctx, params)
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/pkg/config"
+	"sora_landing_be/pkg/utils"
+)
+
+// excerptLength is how long generated excerpts may get
+func excerptLength() int {
+	length := config.LoadConfig().Application.ExcerptLength
+	return utils.Fallback(length, constants.ExcerptDefaultLength, length > 0)
+}
+
+// applyExcerpt generates the excerpt of a new article that was saved without one
+func applyExcerpt(article *domain.BlogArtikel) {
+	if article.Excerpt != "" {
+		return
+	}
+	article.Excerpt = utils.GenerateExcerpt(article.Content, excerptLength())
+	article.ExcerptGenerated = article.Excerpt != ""
+}
+
+// nextExcerpt returns the excerpt an existing article carries after an update. An excerpt is
+// authored, typed by an editor, or generated from the content. Generated excerpts follow the
+// content, authored ones are kept until cleared. Sending back the generated excerpt unchanged,
+// as the admin form does, does not make it authored.
+func nextExcerpt(existing domain.BlogArtikel, authored string, clear bool, content string) (string, bool) {
+	if authored == existing.Excerpt && existing.ExcerptGenerated {
+		authored = ""
+	}
+	if authored != "" {
+		return authored, false
+	}
+	if existing.Excerpt != "" && !existing.ExcerptGenerated && !clear {
+		return existing.Excerpt, false
+	}
+	excerpt := utils.GenerateExcerpt(content, excerptLength())
+	return excerpt, excerpt != ""
+}
+
+// importedExcerpt prefers the summary published by the source site, it is not written
+// by an editor of this blog so it counts as generated
+func importedExcerpt(source, content string) string {
+	if source != "" {
+		return utils.TruncateSentences(source, excerptLength())
+	}
+	return utils.GenerateExcerpt(content, excerptLength())
+}
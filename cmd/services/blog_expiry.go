@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/pkg/database"
+	internal_err "sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/logger"
+	"time"
+
+	"github.com/uptrace/bun"
+	"go.uber.org/zap"
+)
+
+// ArchiveExpiredArticles archives the public articles past their unpublish date and gives
+// up their featured slot. Public queries already hide them, this makes the state explicit.
+func (s *blogService) ArchiveExpiredArticles(ctx context.Context) error {
+	articles, err := s.blogRepo.ListExpiredArticles(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	archived := 0
+	for _, expired := range articles {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		err := database.RunInTx(ctx, database.GetDB(), &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+			// featured slots shift as earlier articles are released, read the current one
+			article, err := s.blogRepo.GetArticle(ctx, expired.ID)
+			if err != nil {
+				return err
+			}
+			if article.Featured != nil {
+				if err := s.blogRepo.RemoveFeaturedPosition(ctx, article.ID); err != nil {
+					return err
+				}
+				if err := s.blogRepo.ShiftUp(ctx, *article.Featured); err != nil {
+					return err
+				}
+			}
+			return s.blogRepo.UpdateArticleStatus(ctx, article.ID, constants.StatusArchived, nil)
+		})
+		if err != nil {
+			logger.Log.Warn("failed to archive expired article", zap.String("article_id", expired.ID), zap.Error(err))
+			continue
+		}
+		archived++
+	}
+
+	if archived > 0 {
+		logger.Log.Info("expired articles archived", zap.Int("archived", archived))
+	}
+	return nil
+}
+
+// isExpired mirrors the notExpired filter of the public repository queries
+func isExpired(article domain.BlogArtikel) bool {
+	return !article.UnpublishAt.IsZero() && !article.UnpublishAt.After(time.Now())
+}
+
+// resolveUnpublishAt returns the expiry an article carries after a write: a requested date
+// replaces the current one, clear removes it and otherwise the current one is kept
+func resolveUnpublishAt(current time.Time, requested *time.Time, clear bool) time.Time {
+	if requested != nil {
+		return *requested
+	}
+	if clear {
+		return time.Time{}
+	}
+	return current
+}
+
+// validateUnpublishAt rejects an expiry that would hide a public article as soon as it goes live
+func validateUnpublishAt(status constants.ArticleStatus, publishedAt, unpublishAt time.Time) error {
+	if unpublishAt.IsZero() || !isPublicStatus(status) {
+		return nil
+	}
+	live := time.Now()
+	if publishedAt.After(live) {
+		live = publishedAt
+	}
+	if !unpublishAt.After(live) {
+		return internal_err.NewDefaultError(http.StatusBadRequest, internal_err.ErrUnpublishBeforeLive)
+	}
+	return nil
+}
@@ -0,0 +1,554 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/dto/response"
+	"sora_landing_be/pkg/config"
+	"sora_landing_be/pkg/database"
+	internal_err "sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/http/client"
+	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/storage"
+	"sora_landing_be/pkg/utils"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/go-readability"
+	"github.com/uptrace/bun"
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+)
+
+const (
+	externalImportTimeout = 2 * time.Minute
+	maxRemoteImageBytes   = 5 * 1024 * 1024
+)
+
+// remoteImageExt maps the image types we accept from external sites to a file extension
+var remoteImageExt = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// ExternalImportOptions controls where an imported external article ends up
+type ExternalImportOptions struct {
+	AsDraft bool
+	// CategoryID defaults to the "external" category when empty
+	CategoryID string
+	// TagIDs are attached next to the tag named after the source site
+	TagIDs []string
+	// AutoTag also attaches the existing tags suggested for the content
+	AutoTag bool
+}
+
+// externalPage is a fetched and readability-parsed external article
+type externalPage struct {
+	article   readability.Article
+	pageURL   *url.URL
+	canonical string
+	hash      string
+}
+
+func (s *blogService) CreateArticleFromURL(ctx context.Context, userID string, payload requests.FromURL) (response.ExternalImport, error) {
+	return s.ImportExternal(ctx, userID, payload.URL, ExternalImportOptions{AsDraft: payload.AsDraft, AutoTag: payload.AutoTag})
+}
+
+func (s *blogService) CreateArticlesFromURLs(ctx context.Context, userID string, payload requests.BatchFromURL) (response.ImportJob, error) {
+	var res response.ImportJob
+
+	seen := make(map[string]bool, len(payload.URLs))
+	job := &domain.ImportJob{
+		CreatedByID: userID,
+		Status:      constants.ImportStatusPending,
+		AsDraft:     payload.AsDraft,
+	}
+	for _, rawURL := range payload.URLs {
+		rawURL = strings.TrimSpace(rawURL)
+		if rawURL == "" || seen[rawURL] {
+			continue
+		}
+		seen[rawURL] = true
+		job.Items = append(job.Items, &domain.ImportJobItem{
+			URL:    rawURL,
+			Status: constants.ImportStatusPending,
+		})
+	}
+	if len(job.Items) > constants.MaxImportBatchSize {
+		return res, internal_err.NewDefaultError(http.StatusBadRequest, fmt.Sprintf("a batch may contain at most %d urls", constants.MaxImportBatchSize))
+	}
+	job.Total = len(job.Items)
+
+	if err := s.importRepo.CreateJob(ctx, job); err != nil {
+		return res, err
+	}
+
+	go s.runImportJob(job.ID, userID, job.AsDraft, job.Items)
+
+	return response.NewImportJob(*job), nil
+}
+
+func (s *blogService) GetImportJob(ctx context.Context, id string) (response.ImportJob, error) {
+	job, err := s.importRepo.GetJob(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return response.ImportJob{}, internal_err.NewDefaultError(http.StatusNotFound, internal_err.DataNotFound)
+		}
+		return response.ImportJob{}, err
+	}
+	return response.NewImportJob(job), nil
+}
+
+func (s *blogService) ResyncExternalArticle(ctx context.Context, id string) (response.ExternalSync, error) {
+	var res response.ExternalSync
+
+	article, err := s.blogRepo.GetArticle(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return res, internal_err.NewDefaultError(http.StatusNotFound, internal_err.DataNotFound)
+		}
+		return res, err
+	}
+	if article.Source == "" || article.Source == "-" {
+		return res, internal_err.NewDefaultError(http.StatusBadRequest, "article was not imported from an external source")
+	}
+
+	page, err := s.fetchExternal(ctx, article.Source)
+	if err != nil {
+		return res, err
+	}
+
+	now := time.Now()
+	res.ArticleID = article.ID
+	res.SyncedAt = now
+
+	update := &domain.BlogArtikel{
+		BaseEntity: domain.BaseEntity{ID: article.ID},
+		SyncedAt:   now,
+	}
+	if page.hash != article.SourceHash {
+		cover, content := s.localizeImages(ctx, article.AuthorID, page)
+		update.Title = page.article.Title
+		update.Content = content
+		if article.Excerpt == "" || article.ExcerptGenerated {
+			update.Excerpt = importedExcerpt(page.article.Excerpt, content)
+			update.ExcerptGenerated = true
+		}
+		update.ImageURL = cover
+		update.SourceHash = page.hash
+		res.Changed = true
+	}
+
+	if err := s.blogRepo.UpdateArticle(ctx, update); err != nil {
+		return res, err
+	}
+	if res.Changed {
+		update.ImageURL = utils.Fallback(update.ImageURL, article.ImageURL, update.ImageURL != "")
+
+		// generated SEO fields follow the new title, excerpt and cover
+		synced := article
+		synced.Title, synced.Content, synced.ImageURL = update.Title, update.Content, update.ImageURL
+		synced.Excerpt = utils.Fallback(update.Excerpt, article.Excerpt, update.Excerpt != "")
+		applySeoDefaults(&synced, false)
+		if synced.SeoMeta != article.SeoMeta || synced.SeoGenerated != article.SeoGenerated {
+			if err := s.blogRepo.UpdateArticleSeo(ctx, article.ID, synced.SeoMeta, synced.SeoGenerated); err != nil {
+				return res, err
+			}
+		}
+		update.SeoMeta = synced.SeoMeta
+
+		if err := syncArticleFiles(ctx, s.fileRepo, *update); err != nil {
+			return res, err
+		}
+		if update.ImageURL != article.ImageURL {
+			if err := releaseUpload(ctx, s.fileRepo, article.ImageURL); err != nil {
+				return res, err
+			}
+		}
+	}
+	return res, nil
+}
+
+// ImportExternal fetches an external article, stores its images locally and
+// saves it under the target category unless the URL was already imported
+func (s *blogService) ImportExternal(ctx context.Context, userID, rawURL string, opts ExternalImportOptions) (response.ExternalImport, error) {
+	var res response.ExternalImport
+
+	requested, err := utils.CanonicalURL(rawURL)
+	if err != nil {
+		return res, internal_err.NewDefaultError(http.StatusBadRequest, "invalid url: "+err.Error())
+	}
+
+	if existing, err := s.blogRepo.GetArticleBySource(ctx, requested, rawURL); err != nil || existing != nil {
+		return duplicateImport(existing), err
+	}
+
+	page, err := s.fetchExternal(ctx, requested)
+	if err != nil {
+		return res, err
+	}
+
+	source := requested
+	if page.canonical != "" && page.canonical != requested {
+		if existing, err := s.blogRepo.GetArticleBySource(ctx, page.canonical); err != nil || existing != nil {
+			return duplicateImport(existing), err
+		}
+		source = page.canonical
+	}
+
+	cover, content := s.localizeImages(ctx, userID, page)
+
+	tagIDs := append([]string{}, opts.TagIDs...)
+	if opts.AutoTag {
+		suggested, err := s.suggestedTagIDs(ctx, page.article.Title, content)
+		if err != nil {
+			return res, err
+		}
+		for _, id := range suggested {
+			if !utils.Contains(tagIDs, id) {
+				tagIDs = append(tagIDs, id)
+			}
+		}
+	}
+
+	err = database.RunInTx(ctx, database.GetDB(), &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		categoryID := opts.CategoryID
+		if categoryID == "" {
+			cat, err := s.ensureExternalCategory(ctx, userID)
+			if err != nil {
+				return err
+			}
+			categoryID = cat.ID
+		}
+
+		siteName := page.article.SiteName
+		if siteName == "" {
+			siteName = strings.TrimPrefix(page.pageURL.Hostname(), "www.")
+		}
+		tag, err := s.ensureTag(ctx, userID, siteName)
+		if err != nil {
+			return err
+		}
+
+		uniqueSlug, err := utils.GenerateUniqueSlug(ctx, s.blogRepo, page.article.Title)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		article := &domain.BlogArtikel{
+			Title:      page.article.Title,
+			Slug:       uniqueSlug,
+			Content:    content,
+			Excerpt:    importedExcerpt(page.article.Excerpt, content),
+			CategoryID: categoryID,
+			ImageURL:   cover,
+			AuthorID:   userID,
+			Status:     constants.StatusPublished,
+			Tags:       []*domain.Tag{}, // start empty
+			Source:     source,
+			SourceHash: page.hash,
+			SyncedAt:   now,
+		}
+		article.ExcerptGenerated = article.Excerpt != ""
+		if opts.AsDraft {
+			article.Status = constants.StatusDraft
+		} else {
+			article.PublishedAt = now
+		}
+		// imported posts always point search engines at the original
+		article.CanonicalURL = source
+		applySeoDefaults(article, isPublicStatus(article.Status))
+
+		if err := s.blogRepo.CreateArticlefromURL(ctx, article); err != nil {
+			return err
+		}
+
+		if !utils.Contains(tagIDs, tag.ID) {
+			tagIDs = append([]string{tag.ID}, tagIDs...)
+		}
+		if err := s.blogRepo.AddArticleTags(ctx, article.ID, tagIDs); err != nil {
+			return err
+		}
+		if err := syncArticleFiles(ctx, s.fileRepo, *article); err != nil {
+			return err
+		}
+
+		res = response.ExternalImport{
+			ArticleID: article.ID,
+			Slug:      article.Slug,
+			Status:    constants.ImportStatusImported,
+		}
+		return nil
+	})
+	// a concurrent import of the same URL won the insert, answer like the lookup above would
+	if internal_err.IsUniqueViolation(err, articleSourceIndex) {
+		existing, err := s.blogRepo.GetArticleBySource(ctx, source)
+		return duplicateImport(existing), err
+	}
+
+	return res, err
+}
+
+// articleSourceIndex keeps a source URL to one live article
+const articleSourceIndex = "idx_blog_artikels_source"
+
+func duplicateImport(existing *domain.BlogArtikel) response.ExternalImport {
+	if existing == nil {
+		return response.ExternalImport{}
+	}
+	return response.ExternalImport{
+		ArticleID: existing.ID,
+		Slug:      existing.Slug,
+		Status:    constants.ImportStatusDuplicate,
+	}
+}
+
+func (s *blogService) runImportJob(jobID, userID string, asDraft bool, items []*domain.ImportJobItem) {
+	ctx := context.Background()
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Log.Error("import job panicked", zap.String("job_id", jobID), zap.Any("panic", r))
+		}
+		finishedAt := time.Now()
+		if err := s.importRepo.UpdateJobStatus(ctx, jobID, constants.ImportStatusDone, &finishedAt); err != nil {
+			logger.Log.Error("failed to finish import job", zap.String("job_id", jobID), zap.Error(err))
+		}
+	}()
+
+	if err := s.importRepo.UpdateJobStatus(ctx, jobID, constants.ImportStatusProcessing, nil); err != nil {
+		logger.Log.Error("failed to start import job", zap.String("job_id", jobID), zap.Error(err))
+		return
+	}
+
+	for _, item := range items {
+		item.Status = constants.ImportStatusProcessing
+		_ = s.importRepo.UpdateItem(ctx, item)
+
+		itemCtx, cancel := context.WithTimeout(ctx, externalImportTimeout)
+		res, err := s.ImportExternal(itemCtx, userID, item.URL, ExternalImportOptions{AsDraft: asDraft})
+		cancel()
+
+		if err != nil {
+			item.Status = constants.ImportStatusFailed
+			item.Error = err.Error()
+		} else {
+			item.Status = res.Status
+			item.ArticleID = &res.ArticleID
+		}
+		if err := s.importRepo.UpdateItem(ctx, item); err != nil {
+			logger.Log.Error("failed to update import item", zap.String("item_id", item.ID), zap.Error(err))
+		}
+	}
+}
+
+func (s *blogService) ensureExternalCategory(ctx context.Context, userID string) (*domain.Category, error) {
+	cat, err := s.catRepo.GetCategoryByName(ctx, constants.ExternalCategoryName)
+	if err != nil || cat != nil {
+		return cat, err
+	}
+
+	slug, err := utils.GenerateUniqueSlug(ctx, s.catRepo, constants.ExternalCategoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	newCat := &domain.Category{
+		Name:        constants.ExternalCategoryName,
+		Slug:        slug,
+		Path:        slug,
+		CreatedByID: userID,
+	}
+	catID, err := s.catRepo.CreateCategoryReturnID(ctx, newCat)
+	if err != nil {
+		return nil, err
+	}
+	newCat.ID = catID
+	return newCat, nil
+}
+
+func (s *blogService) ensureTag(ctx context.Context, userID, name string) (*domain.Tag, error) {
+	tag, err := s.tagRepo.GetTagByName(ctx, name)
+	if err != nil || tag != nil {
+		return tag, err
+	}
+
+	slug, err := utils.GenerateUniqueSlug(ctx, s.tagRepo, name)
+	if err != nil {
+		return nil, err
+	}
+
+	newTag := &domain.Tag{
+		Name:        name,
+		Slug:        slug,
+		CreatedByID: userID,
+	}
+	tagID, err := s.tagRepo.CreateTagReturnID(ctx, newTag)
+	if err != nil {
+		return nil, err
+	}
+	newTag.ID = tagID
+	// the slug may have belonged to a renamed tag, it now filters the new one
+	if err := s.tagRepo.DeleteRedirect(ctx, slug); err != nil {
+		return nil, err
+	}
+	return newTag, nil
+}
+
+// fetchExternal downloads a page and extracts its readable content
+func (s *blogService) fetchExternal(ctx context.Context, rawURL string) (*externalPage, error) {
+	fetched, err := client.GetSafeClient().Fetch(ctx, rawURL, client.FetchOptions{
+		ContentTypes: []string{"text/html", "application/xhtml+xml"},
+	})
+	if err != nil {
+		return nil, internal_err.NewDefaultError(http.StatusBadRequest, "failed to fetch url: "+err.Error())
+	}
+
+	pageURL, err := url.Parse(fetched.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := html.Parse(bytes.NewReader(fetched.Body))
+	if err != nil {
+		return nil, internal_err.NewDefaultError(http.StatusBadRequest, "failed to parse page: "+err.Error())
+	}
+
+	// Look up the canonical link before readability rewrites the document
+	canonical := findCanonicalLink(doc, pageURL)
+
+	article, err := readability.FromDocument(doc, pageURL)
+	if err != nil {
+		return nil, internal_err.NewDefaultError(http.StatusBadRequest, "failed to extract article: "+err.Error())
+	}
+	if strings.TrimSpace(article.Title) == "" {
+		return nil, internal_err.NewDefaultError(http.StatusBadRequest, "failed to extract article: page has no title")
+	}
+
+	sum := sha256.Sum256([]byte(article.Title + "\n" + article.TextContent))
+
+	return &externalPage{
+		article:   article,
+		pageURL:   pageURL,
+		canonical: canonical,
+		hash:      hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+func findCanonicalLink(doc *html.Node, pageURL *url.URL) string {
+	var href string
+	utils.WalkHTML(doc, func(n *html.Node) {
+		if href != "" || n.Type != html.ElementNode || n.Data != "link" {
+			return
+		}
+		if strings.EqualFold(utils.GetAttr(n, "rel"), "canonical") {
+			href = utils.GetAttr(n, "href")
+		}
+	})
+	if href == "" {
+		return ""
+	}
+
+	canonical, err := utils.CanonicalURL(utils.ResolveURL(pageURL, href))
+	if err != nil {
+		return ""
+	}
+	return canonical
+}
+
+// localizeImages downloads the lead image and inline images into our own
+// storage. Images that cannot be downloaded keep pointing at the source.
+func (s *blogService) localizeImages(ctx context.Context, userID string, page *externalPage) (cover string, content string) {
+	if page.article.Image != "" {
+		key, err := s.downloadRemoteImage(ctx, userID, utils.ResolveURL(page.pageURL, page.article.Image))
+		if err != nil {
+			logger.Log.Warn("failed to download lead image", zap.String("url", page.article.Image), zap.Error(err))
+			cover = page.article.Image
+		} else {
+			cover = key
+		}
+	}
+
+	content, err := utils.RewriteHTMLFragment(page.article.Content, func(n *html.Node) {
+		if n.Type != html.ElementNode || n.Data != "img" {
+			return
+		}
+		src := utils.GetAttr(n, "src")
+		if src == "" || strings.HasPrefix(src, "data:") {
+			return
+		}
+		absolute := utils.ResolveURL(page.pageURL, src)
+		utils.RemoveAttr(n, "srcset")
+
+		key, err := s.downloadRemoteImage(ctx, userID, absolute)
+		if err != nil {
+			logger.Log.Warn("failed to download inline image", zap.String("url", absolute), zap.Error(err))
+			utils.SetAttr(n, "src", absolute)
+			return
+		}
+		utils.SetAttr(n, "src", uploadURL(key))
+	})
+	if err != nil {
+		logger.Log.Warn("failed to rewrite article images", zap.Error(err))
+		content = page.article.Content
+	}
+
+	return cover, content
+}
+
+// downloadRemoteImage stores a remote image in the upload directory on behalf of the
+// importing user and returns its key
+func (s *blogService) downloadRemoteImage(ctx context.Context, userID, rawURL string) (string, error) {
+	fetched, err := client.GetSafeClient().Fetch(ctx, rawURL, client.FetchOptions{
+		ContentTypes: []string{"image/"},
+		MaxBytes:     maxRemoteImageBytes,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	ext, ok := remoteImageExt[fetched.ContentType]
+	if !ok {
+		return "", fmt.Errorf("unsupported image type %q", fetched.ContentType)
+	}
+
+	base := "image"
+	if u, err := url.Parse(fetched.URL); err == nil {
+		name := path.Base(u.Path)
+		if slug := utils.Slugify(strings.TrimSuffix(name, path.Ext(name))); slug != "" {
+			base = slug
+		}
+	}
+
+	key := utils.GenerateKeyFile(base + ext)
+	file := &domain.FileUpload{
+		FileName:   path.Base(fetched.URL),
+		Module:     constants.FileModuleArticle,
+		UploadedBy: userID,
+		IsPublic:   true,
+	}
+	if err := storeUpload(ctx, s.fileRepo, file, key, bytes.NewReader(fetched.Body)); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// uploadURL returns the stable /uploads address of an upload embedded in content,
+// served by the API whichever storage driver keeps the file
+func uploadURL(key string) string {
+	base := strings.TrimSuffix(config.LoadConfig().Application.BaseURL, "/")
+	return base + "/" + storage.LocalUploadDir + "/" + key
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/dto/response"
+	"sora_landing_be/pkg/config"
+	internal_err "sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/utils"
+	"strings"
+	"time"
+)
+
+const (
+	// schemaHeadlineMaxLength is the longest headline search engines accept for articles
+	schemaHeadlineMaxLength = 110
+	blogListName            = "Blog"
+)
+
+func (s *blogService) GetPublicArticleSchema(ctx context.Context, slug string) (*response.StructuredData, error) {
+	article, err := s.blogRepo.GetArticleBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, internal_err.NewDefaultError(http.StatusNotFound, internal_err.DataNotFound)
+		}
+		return nil, err
+	}
+	if article.Status != constants.StatusPublished || isExpired(article) {
+		return nil, internal_err.NewDefaultError(http.StatusNotFound, internal_err.DataNotFound)
+	}
+	trail, err := s.categoryTrail(ctx, article.Category)
+	if err != nil {
+		return nil, err
+	}
+	return articleStructuredData(article, trail), nil
+}
+
+func (s *blogService) ListPublicArticlesSchema(ctx context.Context, params requests.ListArtikel) (*response.StructuredData, error) {
+	articles, count, err := s.blogRepo.ListPublicArticles(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	list := response.SchemaItemList{
+		Type:          "ItemList",
+		Name:          blogListName,
+		URL:           frontendURL(constants.BlogPath),
+		NumberOfItems: count,
+	}
+	if params.CategoryID != "" {
+		category, err := s.catRepo.GetCategory(ctx, params.CategoryID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, internal_err.NewDefaultError(http.StatusNotFound, internal_err.DataNotFound)
+			}
+			return nil, err
+		}
+		list.Name = category.Name
+		list.URL = frontendURL(fmt.Sprintf(constants.CategoryPathFormat, category.Slug))
+	}
+
+	offset := 0
+	if params.Page > 1 {
+		offset = params.CalculateOffset()
+	}
+	list.ItemListElement = make([]response.SchemaListItem, len(articles))
+	for i, article := range articles {
+		list.ItemListElement[i] = response.SchemaListItem{
+			Type:     "ListItem",
+			Position: offset + i + 1,
+			Name:     article.Title,
+			URL:      ArticlePageURL(article.Slug),
+		}
+	}
+
+	return response.NewStructuredData(list), nil
+}
+
+// articleStructuredData builds the BlogPosting and BreadcrumbList nodes of an article page,
+// trail is the article's category preceded by its ancestors
+func articleStructuredData(article domain.BlogArtikel, trail []domain.Category) *response.StructuredData {
+	cfg := config.LoadConfig().Application
+	pageURL := ArticlePageURL(article.Slug)
+
+	posting := response.SchemaBlogPosting{
+		Type:         "BlogPosting",
+		ID:           pageURL + "#article",
+		URL:          pageURL,
+		Headline:     utils.TruncateText(article.Title, schemaHeadlineMaxLength),
+		Description:  utils.Fallback(article.MetaDescription, article.Excerpt, article.MetaDescription != ""),
+		DateModified: article.UpdatedAt.Format(time.RFC3339),
+		MainEntityOfPage: &response.SchemaThing{
+			Type: "WebPage",
+			ID:   pageURL,
+		},
+	}
+	if !article.PublishedAt.IsZero() {
+		posting.DatePublished = article.PublishedAt.Format(time.RFC3339)
+	}
+	if image := utils.Fallback(article.OgImage, article.ImageURL, article.OgImage != ""); image != "" {
+		posting.Image = []string{absoluteUploadURL(image)}
+	}
+	if article.Author != nil {
+		posting.Author = &response.SchemaThing{
+			Type: "Person",
+			Name: article.Author.Name,
+		}
+	}
+	if cfg.SiteName != "" {
+		posting.Publisher = &response.SchemaOrganization{
+			Type: "Organization",
+			Name: cfg.SiteName,
+			URL:  frontendURL("/"),
+		}
+	}
+	if article.Category != nil {
+		posting.ArticleSection = article.Category.Name
+	}
+	for _, tag := range article.Tags {
+		posting.Keywords = append(posting.Keywords, tag.Name)
+	}
+	if isExternalSource(article.Source) {
+		posting.IsBasedOn = article.Source
+	}
+
+	crumbs := []response.SchemaListItem{
+		{Name: utils.Fallback(cfg.SiteName, "Home", cfg.SiteName != ""), Item: frontendURL("/")},
+		{Name: blogListName, Item: frontendURL(constants.BlogPath)},
+	}
+	for _, category := range trail {
+		crumbs = append(crumbs, response.SchemaListItem{
+			Name: category.Name,
+			Item: frontendURL(fmt.Sprintf(constants.CategoryPathFormat, category.Slug)),
+		})
+	}
+	crumbs = append(crumbs, response.SchemaListItem{Name: article.Title, Item: pageURL})
+	for i := range crumbs {
+		crumbs[i].Type = "ListItem"
+		crumbs[i].Position = i + 1
+	}
+
+	return response.NewStructuredData(posting, response.SchemaBreadcrumbList{
+		Type:            "BreadcrumbList",
+		ItemListElement: crumbs,
+	})
+}
+
+// ArticlePageURL is the address of an article on the website
+func ArticlePageURL(slug string) string {
+	return frontendURL(fmt.Sprintf(constants.ArticlePathFormat, url.PathEscape(slug)))
+}
+
+// frontendURL returns an absolute link to a page on the website
+func frontendURL(pagePath string) string {
+	base := strings.TrimSuffix(config.LoadConfig().Application.FrontendURL, "/")
+	return base + pagePath
+}
+
+// absoluteUploadURL turns an uploaded file key into a link, leaving absolute URLs untouched
+func absoluteUploadURL(ref string) string {
+	if strings.Contains(ref, "://") {
+		return ref
+	}
+	return uploadURL(path.Base(ref))
+}
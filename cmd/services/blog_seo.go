@@ -0,0 +1,50 @@
+package services
+
+import (
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/pkg/utils"
+)
+
+// applySeoDefaults refreshes the SEO fields generated from the article so they follow its
+// title, excerpt and cover, and with fill also generates the ones the editor left empty.
+// Empty fields are filled when an article is published or scheduled.
+func applySeoDefaults(article *domain.BlogArtikel, fill bool) {
+	seo, generated := &article.SeoMeta, &article.SeoGenerated
+	if generated.MetaTitleGenerated || (fill && seo.MetaTitle == "") {
+		seo.MetaTitle = utils.TruncateText(article.Title, constants.MetaTitleMaxLength)
+		generated.MetaTitleGenerated = seo.MetaTitle != ""
+	}
+	if generated.MetaDescriptionGenerated || (fill && seo.MetaDescription == "") {
+		seo.MetaDescription = utils.TruncateSentences(article.Excerpt, constants.MetaDescriptionMaxLength)
+		if seo.MetaDescription == "" {
+			seo.MetaDescription = utils.GenerateExcerpt(article.Content, constants.MetaDescriptionMaxLength)
+		}
+		generated.MetaDescriptionGenerated = seo.MetaDescription != ""
+	}
+	if generated.OgImageGenerated || (fill && seo.OgImage == "") {
+		seo.OgImage = article.ImageURL
+		generated.OgImageGenerated = seo.OgImage != ""
+	}
+	if fill && seo.CanonicalURL == "" && isExternalSource(article.Source) {
+		seo.CanonicalURL = article.Source
+	}
+}
+
+// nextSeoField returns a field of the SEO metadata sent with an update and whether it stays
+// generated. The admin form sends generated values back, unchanged they are not authored.
+func nextSeoField(existing string, generated bool, requested string) (string, bool) {
+	if requested == existing && generated {
+		return existing, true
+	}
+	return requested, false
+}
+
+// isPublicStatus reports whether an article in this status is, or will become, visible to readers
+func isPublicStatus(status constants.ArticleStatus) bool {
+	return status == constants.StatusPublished || status == constants.StatusScheduled
+}
+
+func isExternalSource(source string) bool {
+	return source != "" && source != "-"
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"sora_landing_be/cmd/dto/response"
+	"sora_landing_be/pkg/config"
+	"sora_landing_be/pkg/utils"
+	"time"
+)
+
+// GetSharePage returns the metadata of the share page served to link preview crawlers.
+// Crawler requests are not reader visits so they do not count as a view.
+func (s *blogService) GetSharePage(ctx context.Context, slug string) (response.SharePage, error) {
+	var res response.SharePage
+
+	article, err := s.getPublicArticle(ctx, slug, false)
+	if err != nil {
+		return res, err
+	}
+
+	seo := article.Seo
+	res = response.SharePage{
+		Title:        utils.Fallback(seo.MetaTitle, article.Title, seo.MetaTitle != ""),
+		Description:  utils.Fallback(seo.MetaDescription, article.Excerpt, seo.MetaDescription != ""),
+		URL:          ArticlePageURL(article.Slug),
+		SiteName:     config.LoadConfig().Application.SiteName,
+		ModifiedTime: article.UpdatedAt.Format(time.RFC3339),
+		NoIndex:      seo.NoIndex,
+	}
+	res.CanonicalURL = utils.Fallback(seo.CanonicalURL, res.URL, seo.CanonicalURL != "")
+	if image := utils.Fallback(seo.OgImage, article.ImageURL, seo.OgImage != ""); image != "" {
+		res.Image = absoluteUploadURL(image)
+	}
+	if article.PublishedAt != nil {
+		res.PublishedTime = article.PublishedAt.Format(time.RFC3339)
+	}
+	if article.Author != nil {
+		res.Author = article.Author.Name
+	}
+	if article.Category != nil {
+		res.Section = article.Category.Name
+	}
+	for _, tag := range article.Tags {
+		res.Tags = append(res.Tags, tag.Name)
+	}
+	return res, nil
+}
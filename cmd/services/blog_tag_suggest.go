@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/dto/response"
+	internal_err "sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/keywords"
+	"sora_landing_be/pkg/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tagCorpus caches the document frequencies of the published articles, rebuilding them
+// on every suggestion would read the whole blog each time
+type tagCorpus struct {
+	mu      sync.Mutex
+	corpus  *keywords.Corpus
+	builtAt time.Time
+}
+
+func (s *blogService) SuggestTags(ctx context.Context, payload requests.SuggestTags) (response.TagSuggestions, error) {
+	var attached []string
+	if payload.ArticleID != "" {
+		article, err := s.blogRepo.GetArticle(ctx, payload.ArticleID)
+		if err != nil {
+			return response.TagSuggestions{}, err
+		}
+		payload.Title = utils.Fallback(payload.Title, article.Title, payload.Title != "")
+		payload.Content = utils.Fallback(payload.Content, article.Content, payload.Content != "")
+		for _, tag := range article.Tags {
+			attached = append(attached, tag.ID)
+		}
+	}
+	if strings.TrimSpace(payload.Title+payload.Content) == "" {
+		return response.TagSuggestions{}, internal_err.NewDefaultError(http.StatusBadRequest, "title or content is required")
+	}
+
+	limit := utils.Fallback(payload.Limit, constants.TagSuggestionDefaultLimit, payload.Limit > 0)
+	res, err := s.suggestTags(ctx, payload.Title, payload.Content, limit)
+	if err != nil {
+		return res, err
+	}
+
+	// tags already on the article are not news to the editor
+	tags := make([]response.SuggestedTag, 0, len(res.Tags))
+	for _, tag := range res.Tags {
+		if !utils.Contains(attached, tag.ID) {
+			tags = append(tags, tag)
+		}
+	}
+	res.Tags = tags
+	return res, nil
+}
+
+// suggestedTagIDs picks the few existing tags an imported article clearly talks about
+func (s *blogService) suggestedTagIDs(ctx context.Context, title, content string) ([]string, error) {
+	res, err := s.suggestTags(ctx, title, content, constants.AutoTagMaxTags)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, tag := range res.Tags {
+		if tag.Score >= constants.AutoTagMinScore {
+			ids = append(ids, tag.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *blogService) suggestTags(ctx context.Context, title, content string, limit int) (response.TagSuggestions, error) {
+	res := response.TagSuggestions{Tags: []response.SuggestedTag{}, Keywords: []response.SuggestedKeyword{}}
+
+	corpus, err := s.tagCorpus(ctx)
+	if err != nil {
+		return res, err
+	}
+	terms := documentTerms(title, content)
+	ranked := corpus.Score(terms)
+	scores := make(map[string]float64, len(ranked))
+	for _, term := range ranked {
+		scores[term.Text] = term.Score
+	}
+
+	tags, err := s.tagRepo.ListTagsWithSynonyms(ctx)
+	if err != nil {
+		return res, err
+	}
+	known := make(map[string]bool)
+	for _, tag := range tags {
+		names := []string{tag.Name}
+		for _, synonym := range tag.Synonyms {
+			names = append(names, synonym.Name)
+		}
+
+		best := response.SuggestedTag{ID: tag.ID, Name: tag.Name, Slug: tag.Slug}
+		for _, name := range names {
+			units := phraseTerms(name)
+			for _, unit := range units {
+				known[unit] = true
+			}
+			if score, ok := phraseScore(units, scores); ok && score > best.Score {
+				best.Score = score
+				best.MatchedBy = name
+			}
+		}
+		if best.Score > 0 {
+			res.Tags = append(res.Tags, best)
+		}
+	}
+	sort.Slice(res.Tags, func(i, j int) bool {
+		if res.Tags[i].Score != res.Tags[j].Score {
+			return res.Tags[i].Score > res.Tags[j].Score
+		}
+		return res.Tags[i].Name < res.Tags[j].Name
+	})
+	if len(res.Tags) > limit {
+		res.Tags = res.Tags[:limit]
+	}
+
+	for _, term := range ranked {
+		if len(res.Keywords) == limit {
+			break
+		}
+		// a term seen once is noise rather than a topic
+		if known[term.Text] || terms[term.Text] < 2 {
+			continue
+		}
+		res.Keywords = append(res.Keywords, response.SuggestedKeyword{Keyword: term.Text, Score: term.Score})
+	}
+	return res, nil
+}
+
+// tagCorpus returns the cached corpus, rebuilding it from the published articles once stale
+func (s *blogService) tagCorpus(ctx context.Context) (*keywords.Corpus, error) {
+	s.corpus.mu.Lock()
+	defer s.corpus.mu.Unlock()
+
+	if s.corpus.corpus != nil && time.Since(s.corpus.builtAt) < constants.TagCorpusTTL {
+		return s.corpus.corpus, nil
+	}
+
+	articles, err := s.blogRepo.ListPublishedTexts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	corpus := keywords.NewCorpus()
+	for _, article := range articles {
+		corpus.Add(documentTerms(article.Title, article.Content))
+	}
+	s.corpus.corpus = corpus
+	s.corpus.builtAt = time.Now()
+	return corpus, nil
+}
+
+// documentTerms counts the terms of an article, title terms weighted above body terms
+func documentTerms(title, content string) map[string]int {
+	terms := keywords.Terms(keywords.Tokenize(utils.HTMLToText(content)))
+	for term, n := range keywords.Terms(keywords.Tokenize(title)) {
+		terms[term] += n * constants.TagTitleWeight
+	}
+	return terms
+}
+
+// phraseTerms splits a tag name into the terms keywords.Terms would produce for it,
+// single words stand alone and longer runs are checked bigram by bigram
+func phraseTerms(name string) []string {
+	var units, run []string
+	flush := func() {
+		switch len(run) {
+		case 0:
+		case 1:
+			units = append(units, run[0])
+		default:
+			for i := 0; i+1 < len(run); i++ {
+				units = append(units, run[i]+" "+run[i+1])
+			}
+		}
+		run = nil
+	}
+	for _, token := range keywords.Tokenize(name) {
+		if token == "" {
+			flush()
+			continue
+		}
+		run = append(run, token)
+	}
+	flush()
+	return units
+}
+
+// phraseScore is the weakest term of the phrase, every part has to appear in the document
+func phraseScore(units []string, scores map[string]float64) (float64, bool) {
+	if len(units) == 0 {
+		return 0, false
+	}
+	lowest := 0.0
+	for i, unit := range units {
+		score, ok := scores[unit]
+		if !ok {
+			return 0, false
+		}
+		if i == 0 || score < lowest {
+			lowest = score
+		}
+	}
+	return lowest, true
+}
@@ -3,13 +3,18 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"net/http"
+	"sora_landing_be/cmd/domain"
 	"sora_landing_be/cmd/dto"
 	"sora_landing_be/cmd/dto/requests"
 	"sora_landing_be/cmd/dto/response"
 	"sora_landing_be/cmd/repository"
 	"sora_landing_be/pkg/authentication"
 	"sora_landing_be/pkg/database"
+	internal_err "sora_landing_be/pkg/errors"
 	"sora_landing_be/pkg/utils"
+	"strings"
 
 	"github.com/uptrace/bun"
 )
@@ -20,6 +25,10 @@ type CategoryService interface {
 	UpdateCategory(ctx context.Context, id string, payload requests.Category) error
 	DeleteCategory(ctx context.Context, id string) error
 	GetCategory(ctx context.Context, id string) (response.CategoryResponse, error)
+
+	// Public endpoints
+	ListPublicCategories(ctx context.Context) ([]response.PublicCategory, error)
+	GetPublicCategory(ctx context.Context, slug string) (response.PublicCategoryDetail, error)
 }
 
 type catService struct {
@@ -40,6 +49,15 @@ func (t *catService) CreateCategory(ctx context.Context, payload requests.Catego
 		}
 		data := payload.ToDomain(uniqueSlug)
 		data.CreatedByID = authentication.GetUserDataFromToken(ctx).UserID
+		data.Path = uniqueSlug
+		if payload.ParentID != nil && *payload.ParentID != "" {
+			parent, err := t.getParent(ctx, *payload.ParentID)
+			if err != nil {
+				return err
+			}
+			data.ParentID = &parent.ID
+			data.Path = childPath(parent.Path, uniqueSlug)
+		}
 		err = t.catRepo.CreateCategory(ctx, &data)
 		if err != nil {
 			return err
@@ -67,20 +85,60 @@ func (a *catService) ListCategory(ctx context.Context, payload requests.ListCate
 
 func (a *catService) UpdateCategory(ctx context.Context, id string, payload requests.Category) error {
 	err := database.RunInTx(ctx, database.GetDB(), &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		uniqueSlug, err := utils.GenerateUniqueSlug(ctx, a.catRepo, payload.Name)
+		existing, err := a.catRepo.GetCategory(ctx, id)
 		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return internal_err.NewDefaultError(http.StatusNotFound, internal_err.DataNotFound)
+			}
 			return err
 		}
-		data := payload.ToDomain(uniqueSlug)
+
+		// the slug is part of every descendant's path, only regenerate it on rename
+		slug := existing.Slug
+		if payload.Name != existing.Name && utils.Slugify(payload.Name) != utils.Slugify(existing.Name) {
+			slug, err = utils.GenerateUniqueSlug(ctx, a.catRepo, payload.Name)
+			if err != nil {
+				return err
+			}
+		}
+		data := payload.ToDomain(slug)
 		data.ID = id
 		edited := authentication.GetUserDataFromToken(ctx).UserID
 		data.EditedByID = &edited
 
+		// keep the current SEO metadata unless the request replaces it
+		if payload.Seo == nil {
+			data.SeoMeta = existing.SeoMeta
+		}
+
+		data.ParentID = existing.ParentID
+		parentPath := parentPathOf(existing.Path)
+		if payload.ParentID != nil {
+			data.ParentID = nil
+			parentPath = ""
+			if *payload.ParentID != "" {
+				parent, err := a.getParent(ctx, *payload.ParentID)
+				if err != nil {
+					return err
+				}
+				if parent.ID == id || strings.HasPrefix(parent.Path, existing.Path+categoryPathSeparator) {
+					return internal_err.NewDefaultError(http.StatusBadRequest, internal_err.ErrCategoryCycle)
+				}
+				data.ParentID = &parent.ID
+				parentPath = parent.Path
+			}
+		}
+		data.Path = childPath(parentPath, slug)
+
 		err = a.catRepo.UpdateCategory(ctx, &data)
 		if err != nil {
 			return err
 		}
 
+		// carry the subtree along when the category was moved or renamed
+		if data.Path != existing.Path {
+			return a.catRepo.MoveSubtree(ctx, existing.Path, data.Path)
+		}
 		return nil
 	})
 	if err != nil {
@@ -91,15 +149,21 @@ func (a *catService) UpdateCategory(ctx context.Context, id string, payload requ
 }
 
 func (a *catService) DeleteCategory(ctx context.Context, id string) error {
+	hasChildren, err := a.catRepo.HasChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if hasChildren {
+		return internal_err.NewDefaultError(http.StatusConflict, internal_err.ErrCategoryHasChildren)
+	}
 
-	err := a.catRepo.DeleteCategory(ctx, id)
+	err = a.catRepo.DeleteCategory(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
-
 func (a *catService) GetCategory(ctx context.Context, id string) (response.CategoryResponse, error) {
 	var res response.CategoryResponse
 	data, err := a.catRepo.GetCategory(ctx, id)
@@ -110,3 +174,95 @@ func (a *catService) GetCategory(ctx context.Context, id string) (response.Categ
 	res = response.ToCategoryResponse(data)
 	return res, nil
 }
+
+// ListPublicCategories returns the categories holding published articles, in tree order
+func (a *catService) ListPublicCategories(ctx context.Context) ([]response.PublicCategory, error) {
+	counts, err := a.catRepo.ListCategoryCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]response.PublicCategory, 0, len(counts))
+	for _, c := range counts {
+		if c.ArticleCount > 0 {
+			res = append(res, response.NewPublicCategory(c))
+		}
+	}
+	return res, nil
+}
+
+func (a *catService) GetPublicCategory(ctx context.Context, slug string) (response.PublicCategoryDetail, error) {
+	var res response.PublicCategoryDetail
+	counts, err := a.catRepo.ListCategoryCounts(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	byPath := make(map[string]dto.CategoryCount, len(counts))
+	var category *dto.CategoryCount
+	for i, c := range counts {
+		byPath[c.Path] = c
+		if c.Slug == slug {
+			category = &counts[i]
+		}
+	}
+	// empty categories are hidden from the public listing, so they get no landing page either
+	if category == nil || category.ArticleCount == 0 {
+		return res, internal_err.NewDefaultError(http.StatusNotFound, internal_err.DataNotFound)
+	}
+
+	var ancestors, children []dto.CategoryCount
+	paths := categoryAncestorPaths(category.Path)
+	for _, p := range paths[:len(paths)-1] {
+		if ancestor, ok := byPath[p]; ok {
+			ancestors = append(ancestors, ancestor)
+		}
+	}
+	for _, c := range counts {
+		if c.ParentID != nil && *c.ParentID == category.ID && c.ArticleCount > 0 {
+			children = append(children, c)
+		}
+	}
+
+	return response.NewPublicCategoryDetail(*category, ancestors, children), nil
+}
+
+func (a *catService) getParent(ctx context.Context, id string) (domain.Category, error) {
+	parent, err := a.catRepo.GetCategory(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return parent, internal_err.NewDefaultError(http.StatusBadRequest, "parent category not found")
+		}
+		return parent, err
+	}
+	return parent, nil
+}
+
+const categoryPathSeparator = "/"
+
+// childPath is the path of a category with the given slug placed under parentPath, empty for roots
+func childPath(parentPath, slug string) string {
+	if parentPath == "" {
+		return slug
+	}
+	return parentPath + categoryPathSeparator + slug
+}
+
+// parentPathOf strips the last slug from a category path
+func parentPathOf(path string) string {
+	if i := strings.LastIndex(path, categoryPathSeparator); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// categoryAncestorPaths lists the path of every category from the root down to the given one
+func categoryAncestorPaths(path string) []string {
+	var res []string
+	for i := range path {
+		if path[i] == categoryPathSeparator[0] {
+			res = append(res, path[:i])
+		}
+	}
+	return append(res, path)
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/dto/response"
+	"sora_landing_be/cmd/repository"
+	"sora_landing_be/pkg/database"
+	internal_err "sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/feed"
+	"sora_landing_be/pkg/http/client"
+	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/utils"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+	"go.uber.org/zap"
+)
+
+type FeedService interface {
+	CreateSubscription(ctx context.Context, userID string, payload requests.FeedSubscription) (response.FeedSubscription, error)
+	UpdateSubscription(ctx context.Context, id, userID string, payload requests.FeedSubscription) error
+	DeleteSubscription(ctx context.Context, id string) error
+	GetSubscription(ctx context.Context, id string) (response.FeedSubscription, error)
+	ListSubscriptions(ctx context.Context, payload requests.ListFeedSubscription) (dto.PaginationResponse[response.FeedSubscription], error)
+
+	// PollSubscription checks a single subscription right away
+	PollSubscription(ctx context.Context, id string) (response.FeedPoll, error)
+	// PollDueSubscriptions checks every active subscription not polled within constants.FeedPollInterval
+	PollDueSubscriptions(ctx context.Context) error
+}
+
+type feedService struct {
+	feedRepo    repository.FeedRepository
+	catRepo     repository.CategoryRepository
+	tagRepo     repository.TagRepository
+	blogService BlogService
+
+	// polling holds the IDs of subscriptions currently being polled
+	polling sync.Map
+}
+
+func NewFeedService(feedRepo repository.FeedRepository, catRepo repository.CategoryRepository, tagRepo repository.TagRepository, blogService BlogService) FeedService {
+	return &feedService{
+		feedRepo:    feedRepo,
+		catRepo:     catRepo,
+		tagRepo:     tagRepo,
+		blogService: blogService,
+	}
+}
+
+func (s *feedService) CreateSubscription(ctx context.Context, userID string, payload requests.FeedSubscription) (response.FeedSubscription, error) {
+	var res response.FeedSubscription
+
+	data := payload.ToDomain()
+	data.CreatedByID = userID
+	if err := s.prepare(ctx, &data); err != nil {
+		return res, err
+	}
+
+	// make sure the url really is a feed before subscribing to it
+	_, parsed, err := fetchFeed(ctx, data.URL, "", "")
+	if err != nil {
+		return res, err
+	}
+	if parsed != nil {
+		data.Title = parsed.Title
+	}
+
+	if err := s.feedRepo.CreateSubscription(ctx, &data); err != nil {
+		return res, err
+	}
+	return response.NewFeedSubscription(data), nil
+}
+
+func (s *feedService) UpdateSubscription(ctx context.Context, id, userID string, payload requests.FeedSubscription) error {
+	current, err := s.feedRepo.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	data := payload.ToDomain()
+	data.ID = id
+	data.Title = current.Title
+	data.EditedByID = &userID
+	if err := s.prepare(ctx, &data); err != nil {
+		return err
+	}
+
+	// a new url is checked like a new subscription, and the title follows the new feed
+	if data.URL != current.URL {
+		_, parsed, err := fetchFeed(ctx, data.URL, "", "")
+		if err != nil {
+			return err
+		}
+		if parsed != nil {
+			data.Title = parsed.Title
+		}
+	}
+
+	// the poll state reset and the update go together
+	return database.RunInTx(ctx, database.GetDB(), &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		return s.feedRepo.UpdateSubscription(ctx, &data)
+	})
+}
+
+func (s *feedService) DeleteSubscription(ctx context.Context, id string) error {
+	return s.feedRepo.DeleteSubscription(ctx, id)
+}
+
+func (s *feedService) GetSubscription(ctx context.Context, id string) (response.FeedSubscription, error) {
+	data, err := s.feedRepo.GetSubscription(ctx, id)
+	if err != nil {
+		return response.FeedSubscription{}, err
+	}
+	return response.NewFeedSubscription(data), nil
+}
+
+func (s *feedService) ListSubscriptions(ctx context.Context, payload requests.ListFeedSubscription) (dto.PaginationResponse[response.FeedSubscription], error) {
+	var paginateRes dto.PaginationResponse[response.FeedSubscription]
+	res, count, err := s.feedRepo.ListSubscriptions(ctx, payload)
+	if err != nil {
+		return paginateRes, err
+	}
+
+	paginateRes = dto.NewPaginationResponse(payload.PaginationRequest, count, response.NewListFeedSubscription(res))
+	return paginateRes, nil
+}
+
+func (s *feedService) PollSubscription(ctx context.Context, id string) (response.FeedPoll, error) {
+	sub, err := s.feedRepo.GetSubscription(ctx, id)
+	if err != nil {
+		return response.FeedPoll{}, err
+	}
+	return s.poll(ctx, sub)
+}
+
+func (s *feedService) PollDueSubscriptions(ctx context.Context) error {
+	subs, err := s.feedRepo.ListDueSubscriptions(ctx, time.Now().Add(-constants.FeedPollInterval))
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		res, err := s.poll(ctx, sub)
+		if err != nil {
+			logger.Log.Warn("failed to poll feed", zap.String("subscription_id", sub.ID), zap.String("url", sub.URL), zap.Error(err))
+			continue
+		}
+		logger.Log.Info("feed polled",
+			zap.String("subscription_id", sub.ID),
+			zap.Bool("not_modified", res.NotModified),
+			zap.Int("imported", res.Imported),
+			zap.Int("duplicates", res.Duplicates),
+			zap.Int("failed", res.Failed),
+		)
+	}
+	return nil
+}
+
+// poll fetches the feed with a conditional GET and imports entries that were not seen before
+func (s *feedService) poll(ctx context.Context, sub domain.FeedSubscription) (response.FeedPoll, error) {
+	res := response.FeedPoll{SubscriptionID: sub.ID}
+
+	if _, busy := s.polling.LoadOrStore(sub.ID, true); busy {
+		return res, internal_err.NewDefaultError(http.StatusConflict, "subscription is already being polled")
+	}
+	defer s.polling.Delete(sub.ID)
+
+	fetched, parsed, err := fetchFeed(ctx, sub.URL, sub.ETag, sub.LastModified)
+	sub.LastPolledAt = time.Now()
+	if err != nil {
+		sub.LastError = err.Error()
+		s.savePollState(ctx, &sub)
+		return res, err
+	}
+	sub.LastError = ""
+
+	if fetched.StatusCode == http.StatusNotModified {
+		res.NotModified = true
+		s.savePollState(ctx, &sub)
+		return res, nil
+	}
+	if parsed.Title != "" {
+		sub.Title = parsed.Title
+	}
+
+	pending, skipped, err := s.pendingItems(ctx, sub.ID, parsed.Items)
+	if err != nil {
+		return res, err
+	}
+	res.Skipped = skipped
+
+	// keep the validators only when everything in this version of the feed was handled,
+	// otherwise the next poll would get a 304 and never see the remaining entries
+	truncated := len(pending) > constants.FeedMaxEntriesPerPoll
+	if truncated {
+		res.Skipped += len(pending) - constants.FeedMaxEntriesPerPoll
+		pending = pending[len(pending)-constants.FeedMaxEntriesPerPoll:]
+	} else {
+		sub.ETag = fetched.Header.Get("ETag")
+		sub.LastModified = fetched.Header.Get("Last-Modified")
+	}
+
+	feedURL, _ := url.Parse(fetched.URL)
+	opts := ExternalImportOptions{
+		AsDraft:    !sub.AutoPublish,
+		CategoryID: utils.SafelyDereference(sub.CategoryID),
+		TagIDs:     sub.TagIDs,
+	}
+	for _, p := range pending {
+		entry := p.entry
+		entry.Attempts++
+
+		link := utils.ResolveURL(feedURL, p.item.Link)
+		entry.URL = link
+
+		var imported response.ExternalImport
+		err := errors.New("entry has no link")
+		if link != "" {
+			itemCtx, cancel := context.WithTimeout(ctx, externalImportTimeout)
+			imported, err = s.blogService.ImportExternal(itemCtx, sub.CreatedByID, link, opts)
+			cancel()
+		} else {
+			entry.Attempts = constants.FeedMaxAttempts
+		}
+
+		if err != nil {
+			entry.Status = constants.ImportStatusFailed
+			entry.Error = err.Error()
+			res.Failed++
+		} else {
+			entry.Status = imported.Status
+			entry.ArticleID = &imported.ArticleID
+			entry.Error = ""
+			if imported.Status == constants.ImportStatusDuplicate {
+				res.Duplicates++
+			} else {
+				res.Imported++
+			}
+		}
+
+		if err := s.feedRepo.SaveEntry(ctx, &entry); err != nil {
+			logger.Log.Error("failed to save feed entry", zap.String("subscription_id", sub.ID), zap.String("guid", entry.GUID), zap.Error(err))
+		}
+	}
+
+	s.savePollState(ctx, &sub)
+	return res, nil
+}
+
+type pendingFeedItem struct {
+	item  feed.Item
+	entry domain.FeedEntry
+}
+
+// pendingItems returns the feed items that still need importing, oldest first,
+// together with the number of items skipped because they were handled before
+func (s *feedService) pendingItems(ctx context.Context, subscriptionID string, items []feed.Item) ([]pendingFeedItem, int, error) {
+	guids := make([]string, 0, len(items))
+	unique := make([]feed.Item, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if seen[item.GUID] {
+			continue
+		}
+		seen[item.GUID] = true
+		guids = append(guids, item.GUID)
+		unique = append(unique, item)
+	}
+
+	entries, err := s.feedRepo.GetEntries(ctx, subscriptionID, guids)
+	if err != nil {
+		return nil, 0, err
+	}
+	known := make(map[string]domain.FeedEntry, len(entries))
+	for _, entry := range entries {
+		known[entry.GUID] = entry
+	}
+
+	var (
+		pending []pendingFeedItem
+		skipped int
+	)
+	for _, item := range unique {
+		entry, ok := known[item.GUID]
+		if ok && (entry.Status != constants.ImportStatusFailed || entry.Attempts >= constants.FeedMaxAttempts) {
+			skipped++
+			continue
+		}
+		if !ok {
+			entry = domain.FeedEntry{
+				SubscriptionID: subscriptionID,
+				GUID:           item.GUID,
+			}
+		}
+		pending = append(pending, pendingFeedItem{item: item, entry: entry})
+	}
+
+	// feeds list the newest entries first, import in publication order instead
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].item.Published.Before(pending[j].item.Published)
+	})
+	return pending, skipped, nil
+}
+
+func (s *feedService) savePollState(ctx context.Context, sub *domain.FeedSubscription) {
+	if err := s.feedRepo.UpdatePollState(ctx, sub); err != nil {
+		logger.Log.Error("failed to save feed poll state", zap.String("subscription_id", sub.ID), zap.Error(err))
+	}
+}
+
+// prepare normalizes the feed url and checks that the target category and tags exist
+func (s *feedService) prepare(ctx context.Context, data *domain.FeedSubscription) error {
+	canonical, err := utils.CanonicalURL(data.URL)
+	if err != nil {
+		return internal_err.NewDefaultError(http.StatusBadRequest, "invalid url: "+err.Error())
+	}
+	data.URL = canonical
+
+	if data.CategoryID != nil {
+		if _, err := s.catRepo.GetCategory(ctx, *data.CategoryID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return internal_err.NewDefaultError(http.StatusBadRequest, "category not found")
+			}
+			return err
+		}
+	}
+
+	tagIDs := make([]string, 0, len(data.TagIDs))
+	for _, id := range data.TagIDs {
+		if !utils.Contains(tagIDs, id) {
+			tagIDs = append(tagIDs, id)
+		}
+	}
+	count, err := s.tagRepo.CountTagsByIDs(ctx, tagIDs)
+	if err != nil {
+		return err
+	}
+	if count != len(tagIDs) {
+		return internal_err.NewDefaultError(http.StatusBadRequest, "one or more tags not found")
+	}
+	data.TagIDs = tagIDs
+	return nil
+}
+
+// fetchFeed downloads and parses a feed, sending the validators from the previous poll
+func fetchFeed(ctx context.Context, feedURL, etag, lastModified string) (*client.FetchResult, *feed.Feed, error) {
+	headers := map[string]string{
+		"Accept": "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.8, */*;q=0.5",
+	}
+	if etag != "" {
+		headers["If-None-Match"] = etag
+	}
+	if lastModified != "" {
+		headers["If-Modified-Since"] = lastModified
+	}
+
+	fetched, err := client.GetSafeClient().Fetch(ctx, feedURL, client.FetchOptions{
+		ContentTypes: feed.ContentTypes,
+		Headers:      headers,
+	})
+	if err != nil {
+		return nil, nil, internal_err.NewDefaultError(http.StatusBadRequest, "failed to fetch feed: "+err.Error())
+	}
+	if fetched.StatusCode == http.StatusNotModified {
+		return fetched, nil, nil
+	}
+
+	parsed, err := feed.Parse(fetched.Body)
+	if err != nil {
+		return nil, nil, internal_err.NewDefaultError(http.StatusBadRequest, "failed to parse feed: "+err.Error())
+	}
+	return fetched, parsed, nil
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"path/filepath"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/dto/response"
+	"sora_landing_be/cmd/repository"
+	"sora_landing_be/pkg/config"
+	internal_err "sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/storage"
+	"sora_landing_be/pkg/utils"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type FileService interface {
+	Upload(ctx context.Context, userID string, header *multipart.FileHeader, payload requests.UploadFile) (response.FileUpload, error)
+	Delete(ctx context.Context, key string) error
+
+	// Direct uploads to the bucket
+	PresignUpload(ctx context.Context, userID string, payload requests.PresignUpload) (response.PresignedUpload, error)
+	CompleteUpload(ctx context.Context, userID string, payload requests.CompleteUpload) (response.FileUpload, error)
+
+	// Resumable uploads following tus 1.0
+	CreateResumableUpload(ctx context.Context, userID string, payload requests.CreateResumableUpload) (response.ResumableUpload, error)
+	GetResumableUpload(ctx context.Context, userID, id string) (response.ResumableUpload, error)
+	AppendResumableUpload(ctx context.Context, userID, id string, payload requests.AppendResumableUpload, body io.Reader) (response.ResumableUpload, error)
+	TerminateResumableUpload(ctx context.Context, userID, id string) error
+
+	// Media library
+	ListMedia(ctx context.Context, params requests.ListMedia) (dto.PaginationResponse[response.MediaFile], error)
+	GetMedia(ctx context.Context, id string) (response.MediaDetail, error)
+	UpdateMedia(ctx context.Context, id string, payload requests.UpdateMedia) (response.MediaDetail, error)
+	DeleteMedia(ctx context.Context, id string) error
+
+	BackfillVariants(ctx context.Context, opts VariantBackfillOptions) (response.VariantBackfill, error)
+	SweepTemporaryUploads(ctx context.Context) error
+}
+
+type fileService struct {
+	fileRepo repository.FileRepository
+}
+
+func NewFileService(fileRepo repository.FileRepository) FileService {
+	return &fileService{
+		fileRepo: fileRepo,
+	}
+}
+
+func (s *fileService) Upload(ctx context.Context, userID string, header *multipart.FileHeader, payload requests.UploadFile) (response.FileUpload, error) {
+	if maxSize := config.LoadConfig().ObjectStorage.MaxFileSizeBytes(); header.Size > maxSize {
+		return response.FileUpload{}, internal_err.StorageErrorToAppError(fmt.Sprintf("File is larger than %d MB", maxSize>>20))
+	}
+	src, err := header.Open()
+	if err != nil {
+		return response.FileUpload{}, internal_err.StorageErrorToAppError("Failed to read uploaded file")
+	}
+	defer src.Close()
+
+	key := utils.GenerateKeyFile(header.Filename)
+	file := &domain.FileUpload{
+		FileName:   header.Filename,
+		Module:     payload.Module,
+		UploadedBy: userID,
+		IsPublic:   payload.IsPublic,
+	}
+	if payload.ReferenceID != "" {
+		file.ReferenceID = &payload.ReferenceID
+	}
+	if err := storeUpload(ctx, s.fileRepo, file, key, src); err != nil {
+		return response.FileUpload{}, err
+	}
+	return s.newFileUpload(ctx, *file, key), nil
+}
+
+func (s *fileService) Delete(ctx context.Context, key string) error {
+	filePath := uploadPath(key)
+	files, err := s.fileRepo.ListFilesByPaths(ctx, []string{filePath})
+	if err != nil {
+		return err
+	}
+	if len(files) > 0 {
+		return s.deleteFile(ctx, files[0])
+	}
+
+	// uploads from before files were tracked have no record
+	if err := storage.GetDriver().Delete(ctx, key); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return internal_err.StorageErrorToAppError("File not found")
+		}
+		return internal_err.StorageErrorToAppError("Failed to delete file")
+	}
+	return nil
+}
+
+// storeUpload checks and cleans r, see cleanUpload, stores it under key with the configured
+// driver and records it with its size, content type and hash. The file is removed again when
+// it cannot be recorded. It stays temporary until saving an article that references it promotes it.
+func storeUpload(ctx context.Context, fileRepo repository.FileRepository, file *domain.FileUpload, key string, r io.Reader) error {
+	filePath := uploadPath(key)
+	file.FilePath = filePath
+	file.IsTemporary = true
+
+	// uploads are small, the content is kept in memory to check it before storing it
+	content, err := cleanUpload(key, r)
+	if err != nil {
+		return err
+	}
+	if err := digestFile(file, bytes.NewReader(content), io.Discard); err != nil {
+		return internal_err.StorageErrorToAppError("Failed to read uploaded file")
+	}
+	driver := storage.GetDriver()
+	if err := driver.Put(ctx, key, bytes.NewReader(content), file.FileSize, file.ContentType); err != nil {
+		logger.Log.Error("failed to store upload", zap.String("key", key), zap.Error(err))
+		return internal_err.StorageErrorToAppError("Failed to save file")
+	}
+
+	if err := fileRepo.CreateFile(ctx, file); err != nil {
+		driver.Delete(ctx, key)
+		return err
+	}
+	if err := driver.MarkTemporary(ctx, key, true); err != nil {
+		logger.Log.Warn("failed to mark upload temporary", zap.String("key", key), zap.Error(err))
+	}
+
+	// the upload stands without its variants, pages fall back to the original
+	if err := generateVariants(ctx, fileRepo, file); err != nil {
+		logger.Log.Warn("failed to generate image variants", zap.String("path", filePath), zap.Error(err))
+	}
+	return nil
+}
+
+// cleanUpload reads an upload of at most object_storage.max_file_size and checks its content
+// is what the extension of key claims, returning it without metadata and active content
+func cleanUpload(key string, r io.Reader) ([]byte, error) {
+	maxSize := config.LoadConfig().ObjectStorage.MaxFileSizeBytes()
+	content, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, internal_err.StorageErrorToAppError("Failed to read uploaded file")
+	}
+	if int64(len(content)) > maxSize {
+		return nil, internal_err.StorageErrorToAppError(fmt.Sprintf("File is larger than %d MB", maxSize>>20))
+	}
+	content, err = utils.CleanUpload(filepath.Ext(key), content)
+	if err != nil {
+		return nil, internal_err.StorageErrorToAppError("Invalid file: " + err.Error())
+	}
+	return content, nil
+}
+
+// digestFile copies the content of an upload to w, filling in its size, content type and hash
+func digestFile(file *domain.FileUpload, r io.Reader, w io.Writer) error {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(512)
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(w, hash), br)
+	if err != nil {
+		return err
+	}
+	file.FileSize = size
+	file.ContentType = detectContentType(head, file.FilePath)
+	file.ContentHash = hex.EncodeToString(hash.Sum(nil))
+	return nil
+}
+
+// detectContentType sniffs the content, falling back to the extension for formats the
+// sniffer only knows as text, such as SVG
+func detectContentType(head []byte, name string) string {
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	switch sniffed {
+	case "application/octet-stream", "text/plain", "text/xml":
+		if byExt, _, err := mime.ParseMediaType(mime.TypeByExtension(strings.ToLower(filepath.Ext(name)))); err == nil {
+			return byExt
+		}
+	}
+	return sniffed
+}
+
+// uploadPath is the path of an upload as stored in file_uploads, the storage key prefixed
+// with the upload route whichever driver keeps the file
+func uploadPath(key string) string {
+	return path.Join(storage.LocalUploadDir, key)
+}
+
+// fileURL is the address of a stored file, temporary for files that are not public. Public files
+// get their upload route, which redirects to the bucket, so content saved with it is recognised
+// as referencing the upload whichever driver stores it.
+func fileURL(ctx context.Context, file domain.FileUpload) string {
+	driver := storage.GetDriver()
+	key := path.Base(file.FilePath)
+	if file.IsPublic {
+		return uploadURL(key)
+	}
+	expiry := config.LoadConfig().ObjectStorage.PresignExpiration
+	presigned, err := driver.PresignURL(ctx, key, utils.Fallback(expiry, constants.PresignDefaultExpiry, expiry > 0))
+	if err != nil {
+		logger.Log.Warn("failed to presign upload", zap.String("key", key), zap.Error(err))
+		return ""
+	}
+	return presigned
+}
+
+// syncArticleFiles records which tracked uploads an article shows as cover or inline image
+// and promotes the temporary ones, together with its Open Graph image.
+// Files uploaded before uploads were tracked have no record and are skipped.
+func syncArticleFiles(ctx context.Context, fileRepo repository.FileRepository, article domain.BlogArtikel) error {
+	usages := make(map[string][]constants.FileUsage)
+	var paths []string
+	add := func(key string, usage constants.FileUsage) {
+		p := uploadPath(path.Base(key))
+		if !utils.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+		if !utils.Contains(usages[p], usage) {
+			usages[p] = append(usages[p], usage)
+		}
+	}
+	if article.ImageURL != "" && !strings.Contains(article.ImageURL, "://") {
+		add(article.ImageURL, constants.FileUsageCover)
+	}
+	for _, match := range uploadRefPattern.FindAllStringSubmatch(article.Content, -1) {
+		add(match[1], constants.FileUsageInline)
+	}
+	if article.OgImage != "" && !strings.Contains(article.OgImage, "://") {
+		// kept, but not listed among the usages
+		paths = append(paths, uploadPath(path.Base(article.OgImage)))
+	}
+
+	files, err := fileRepo.ListFilesByPaths(ctx, paths)
+	if err != nil {
+		return err
+	}
+	var refs []domain.ArticleFile
+	var temporary []domain.FileUpload
+	for _, file := range files {
+		for _, usage := range usages[file.FilePath] {
+			refs = append(refs, domain.ArticleFile{FileID: file.ID, Usage: usage})
+		}
+		if file.IsTemporary {
+			temporary = append(temporary, file)
+		}
+	}
+	if err := fileRepo.ReplaceArticleFiles(ctx, article.ID, refs); err != nil {
+		return err
+	}
+	return promoteFiles(ctx, fileRepo, temporary)
+}
+
+// promoteFiles keeps temporary uploads now that saved content references them
+func promoteFiles(ctx context.Context, fileRepo repository.FileRepository, files []domain.FileUpload) error {
+	if len(files) == 0 {
+		return nil
+	}
+	ids := make([]string, len(files))
+	for i, file := range files {
+		ids[i] = file.ID
+	}
+	if err := fileRepo.PromoteFiles(ctx, ids); err != nil {
+		return err
+	}
+	for _, file := range files {
+		unmarkTemporary(ctx, file.FilePath)
+	}
+	return nil
+}
+
+// unmarkTemporary clears the storage flag of a promoted upload, the database record decides
+// what is swept so a failure only leaves the flag behind
+func unmarkTemporary(ctx context.Context, filePath string) {
+	if err := storage.GetDriver().MarkTemporary(ctx, path.Base(filePath), false); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		logger.Log.Warn("failed to unmark temporary upload", zap.String("path", filePath), zap.Error(err))
+	}
+}
+
+// releaseUpload marks a file an article stopped using as temporary again, unless other content
+// still references it, so the upload sweep reclaims it like an abandoned upload
+func releaseUpload(ctx context.Context, fileRepo repository.FileRepository, key string) error {
+	if key == "" || strings.Contains(key, "://") {
+		return nil
+	}
+	return fileRepo.ReleaseUnreferencedFile(ctx, uploadPath(path.Base(key)))
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/dto/response"
+	"sora_landing_be/pkg/config"
+	internal_err "sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/storage"
+	"sora_landing_be/pkg/utils"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+func directUploader() (storage.DirectUploader, error) {
+	uploader, ok := storage.GetDriver().(storage.DirectUploader)
+	if !ok {
+		return nil, internal_err.NewDefaultError(http.StatusBadRequest, internal_err.ErrDirectUploadOff)
+	}
+	return uploader, nil
+}
+
+// PresignUpload hands out a form for sending a file straight to the bucket, limited to the
+// declared content type and the configured size. What the upload is for is stored with
+// the object so the completion only needs the key. The upload is recorded as a session
+// the sweep expires, removing the object if it is never completed.
+func (s *fileService) PresignUpload(ctx context.Context, userID string, payload requests.PresignUpload) (response.PresignedUpload, error) {
+	uploader, err := directUploader()
+	if err != nil {
+		return response.PresignedUpload{}, err
+	}
+
+	if ok, expectedFormat, actualFormat := utils.IsDocumentFile(payload.FileName); !ok {
+		return response.PresignedUpload{}, internal_err.StorageErrorToAppError(
+			fmt.Sprintf("Cannot upload file with format: %s, expected: %s", actualFormat, expectedFormat),
+		)
+	}
+	contentType := strings.ToLower(strings.TrimSpace(payload.ContentType))
+	expected, _, _ := mime.ParseMediaType(mime.TypeByExtension(filepath.Ext(payload.FileName)))
+	if expected != "" && contentType != expected {
+		return response.PresignedUpload{}, internal_err.StorageErrorToAppError(
+			fmt.Sprintf("Content type %s does not match the file, expected: %s", payload.ContentType, expected),
+		)
+	}
+	maxSize := config.LoadConfig().ObjectStorage.MaxFileSizeBytes()
+	if payload.Size > maxSize {
+		return response.PresignedUpload{}, internal_err.StorageErrorToAppError(
+			fmt.Sprintf("File is larger than %d MB", maxSize>>20),
+		)
+	}
+
+	metadata := map[string]string{
+		constants.DirectUploadMetaUploadedBy: userID,
+		// metadata is sent as headers, names outside ASCII are escaped
+		constants.DirectUploadMetaFileName: url.QueryEscape(payload.FileName),
+		constants.DirectUploadMetaPublic:   strconv.FormatBool(payload.IsPublic),
+	}
+	if payload.Module != "" {
+		metadata[constants.DirectUploadMetaModule] = payload.Module
+	}
+	if payload.ReferenceID != "" {
+		metadata[constants.DirectUploadMetaReferenceID] = payload.ReferenceID
+	}
+
+	key := utils.GenerateKeyFile(payload.FileName)
+	form, err := uploader.PresignUpload(ctx, key, storage.UploadPolicy{
+		ContentType: contentType,
+		MaxSize:     maxSize,
+		Expiry:      constants.DirectUploadExpiry,
+		Metadata:    metadata,
+		Temporary:   true,
+	})
+	if err != nil {
+		return response.PresignedUpload{}, err
+	}
+
+	session := domain.UploadSession{
+		FileKey:      key,
+		FileName:     payload.FileName,
+		UploadLength: payload.Size,
+		Parts:        []string{},
+		Module:       payload.Module,
+		IsPublic:     payload.IsPublic,
+		UploadedBy:   userID,
+		Direct:       true,
+	}
+	if payload.ReferenceID != "" {
+		session.ReferenceID = &payload.ReferenceID
+	}
+	if err := s.fileRepo.CreateUploadSession(ctx, &session); err != nil {
+		return response.PresignedUpload{}, err
+	}
+	return response.PresignedUpload{
+		Key:       key,
+		URL:       form.URL,
+		Method:    http.MethodPost,
+		Fields:    form.Fields,
+		MaxSize:   maxSize,
+		ExpiresAt: form.ExpiresAt,
+	}, nil
+}
+
+// CompleteUpload checks a file the client sent to the bucket and records it like an upload
+// through the API, temporary until an article uses it. Completing twice returns the record.
+func (s *fileService) CompleteUpload(ctx context.Context, userID string, payload requests.CompleteUpload) (response.FileUpload, error) {
+	uploader, err := directUploader()
+	if err != nil {
+		return response.FileUpload{}, err
+	}
+	key := path.Base(payload.Key)
+
+	info, err := uploader.Stat(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return response.FileUpload{}, internal_err.NewDefaultError(http.StatusNotFound, internal_err.ErrUploadNotFound)
+		}
+		return response.FileUpload{}, err
+	}
+	if info.Metadata[constants.DirectUploadMetaUploadedBy] != userID {
+		return response.FileUpload{}, internal_err.NewDefaultError(http.StatusForbidden, internal_err.ErrUploadNotOwned)
+	}
+
+	existing, err := s.fileRepo.ListFilesByPaths(ctx, []string{uploadPath(key)})
+	if err != nil {
+		return response.FileUpload{}, err
+	}
+	if len(existing) > 0 {
+		s.finishDirectUpload(ctx, key, existing[0].ID)
+		return s.newFileUpload(ctx, existing[0], key), nil
+	}
+
+	fileName, err := url.QueryUnescape(info.Metadata[constants.DirectUploadMetaFileName])
+	if err != nil || fileName == "" {
+		fileName = key
+	}
+	file := domain.FileUpload{
+		FileName:   fileName,
+		FilePath:   uploadPath(key),
+		Module:     info.Metadata[constants.DirectUploadMetaModule],
+		UploadedBy: userID,
+		IsPublic:   info.Metadata[constants.DirectUploadMetaPublic] == "true",
+	}
+	if referenceID := info.Metadata[constants.DirectUploadMetaReferenceID]; referenceID != "" {
+		file.ReferenceID = &referenceID
+	}
+
+	// the policy only bounded the size and declared type
+	if err := s.recordStoredUpload(ctx, &file, info.Size); err != nil {
+		return response.FileUpload{}, err
+	}
+	s.finishDirectUpload(ctx, key, file.ID)
+	return s.newFileUpload(ctx, file, key), nil
+}
+
+// finishDirectUpload closes the session of a completed upload. The sweep checks for the file
+// record before removing an object, so failing here only leaves the session until it expires.
+func (s *fileService) finishDirectUpload(ctx context.Context, key, fileID string) {
+	if err := s.fileRepo.FinishDirectUpload(ctx, key, fileID); err != nil {
+		logger.Log.Warn("failed to finish direct upload", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// recordStoredUpload checks a file already in storage like an upload through the API, replaces
+// it with its cleaned version and records it, temporary until an article uses it. Formats too
+// large to clean in memory only have their start checked. A file failing the checks is removed.
+func (s *fileService) recordStoredUpload(ctx context.Context, file *domain.FileUpload, size int64) error {
+	driver := storage.GetDriver()
+	key := path.Base(file.FilePath)
+	file.IsTemporary = true
+
+	err := s.checkStoredUpload(ctx, file, size)
+	if err != nil {
+		var appErr internal_err.AppError
+		if errors.As(err, &appErr) {
+			if err := driver.Delete(ctx, key); err != nil {
+				logger.Log.Warn("failed to remove rejected upload", zap.String("key", key), zap.Error(err))
+			}
+		}
+		return err
+	}
+
+	if err := s.fileRepo.CreateFile(ctx, file); err != nil {
+		return internal_err.CheckUniqueViolation(err)
+	}
+	if err := generateVariants(ctx, s.fileRepo, file); err != nil {
+		logger.Log.Warn("failed to generate image variants", zap.String("path", file.FilePath), zap.Error(err))
+	}
+	return nil
+}
+
+// checkStoredUpload validates a stored file and fills in its size, content type and hash
+func (s *fileService) checkStoredUpload(ctx context.Context, file *domain.FileUpload, size int64) error {
+	driver := storage.GetDriver()
+	key := path.Base(file.FilePath)
+	src, err := driver.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if !utils.IsCleanedFormat(filepath.Ext(key)) {
+		br := bufio.NewReaderSize(src, utils.UploadHeadLen)
+		head, _ := br.Peek(utils.UploadHeadLen)
+		if err := utils.CheckUploadHead(filepath.Ext(key), head); err != nil {
+			return internal_err.StorageErrorToAppError("Invalid file: " + err.Error())
+		}
+		if err := digestFile(file, br, io.Discard); err != nil {
+			return internal_err.StorageErrorToAppError("Failed to read uploaded file")
+		}
+		return nil
+	}
+
+	original, err := io.ReadAll(io.LimitReader(src, size+1))
+	if err != nil {
+		return internal_err.StorageErrorToAppError("Failed to read uploaded file")
+	}
+	content, err := cleanUpload(key, bytes.NewReader(original))
+	if err != nil {
+		return err
+	}
+	if err := digestFile(file, bytes.NewReader(content), io.Discard); err != nil {
+		return internal_err.StorageErrorToAppError("Failed to read uploaded file")
+	}
+	if bytes.Equal(content, original) {
+		return nil
+	}
+	if err := driver.Put(ctx, key, bytes.NewReader(content), file.FileSize, file.ContentType); err != nil {
+		return err
+	}
+	if err := driver.MarkTemporary(ctx, key, true); err != nil {
+		logger.Log.Warn("failed to mark upload temporary", zap.String("key", key), zap.Error(err))
+	}
+	return nil
+}
+
+func (s *fileService) newFileUpload(ctx context.Context, file domain.FileUpload, key string) response.FileUpload {
+	res := response.NewFileUpload(file, key)
+	res.URL = fileURL(ctx, file)
+	res.Image = responsiveImage(file)
+	return res
+}
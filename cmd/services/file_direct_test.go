@@ -0,0 +1,423 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/dto/response"
+	"sora_landing_be/cmd/repository"
+	"sora_landing_be/pkg/config"
+	internal_err "sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/storage"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/segmentio/ksuid"
+)
+
+// directUploadRepo keeps the file records of direct uploads and the articles using them in memory
+type directUploadRepo struct {
+	repository.FileRepository
+	files    []domain.FileUpload
+	refs     []domain.ArticleFile
+	sessions []domain.UploadSession
+}
+
+func (r *directUploadRepo) CreateFile(_ context.Context, file *domain.FileUpload) error {
+	file.ID = ksuid.New().String()
+	r.files = append(r.files, *file)
+	return nil
+}
+
+func (r *directUploadRepo) ListFilesByPaths(_ context.Context, paths []string) ([]domain.FileUpload, error) {
+	var res []domain.FileUpload
+	for _, file := range r.files {
+		if slices.Contains(paths, file.FilePath) {
+			res = append(res, file)
+		}
+	}
+	return res, nil
+}
+
+func (r *directUploadRepo) SaveFileVariants(context.Context, domain.FileUpload) error {
+	return nil
+}
+
+func (r *directUploadRepo) ReplaceArticleFiles(_ context.Context, articleID string, files []domain.ArticleFile) error {
+	r.refs = slices.DeleteFunc(r.refs, func(ref domain.ArticleFile) bool { return ref.ArticleID == articleID })
+	for _, file := range files {
+		file.ArticleID = articleID
+		r.refs = append(r.refs, file)
+	}
+	return nil
+}
+
+func (r *directUploadRepo) PromoteFiles(_ context.Context, ids []string) error {
+	for i := range r.files {
+		if slices.Contains(ids, r.files[i].ID) {
+			r.files[i].IsTemporary = false
+		}
+	}
+	return nil
+}
+
+func (r *directUploadRepo) referenced(file domain.FileUpload) bool {
+	return slices.ContainsFunc(r.refs, func(ref domain.ArticleFile) bool { return ref.FileID == file.ID })
+}
+
+func (r *directUploadRepo) PromoteReferencedFiles(_ context.Context, before time.Time) ([]string, error) {
+	var res []string
+	for i, file := range r.files {
+		if file.IsTemporary && file.CreatedAt.Before(before) && r.referenced(file) {
+			r.files[i].IsTemporary = false
+			res = append(res, file.FilePath)
+		}
+	}
+	return res, nil
+}
+
+func (r *directUploadRepo) ListAbandonedFiles(_ context.Context, before time.Time, limit int) ([]domain.FileUpload, error) {
+	var res []domain.FileUpload
+	for _, file := range r.files {
+		if file.IsTemporary && file.CreatedAt.Before(before) && !r.referenced(file) && len(res) < limit {
+			res = append(res, file)
+		}
+	}
+	return res, nil
+}
+
+func (r *directUploadRepo) CreateUploadSession(_ context.Context, session *domain.UploadSession) error {
+	session.ID = ksuid.New().String()
+	session.UpdatedAt = time.Now()
+	r.sessions = append(r.sessions, *session)
+	return nil
+}
+
+func (r *directUploadRepo) FinishDirectUpload(_ context.Context, fileKey, fileID string) error {
+	for i, session := range r.sessions {
+		if session.Direct && session.FileKey == fileKey && session.FileID == nil {
+			r.sessions[i].FileID = &fileID
+		}
+	}
+	return nil
+}
+
+func (r *directUploadRepo) ListStaleUploadSessions(_ context.Context, before time.Time, limit int) ([]domain.UploadSession, error) {
+	var res []domain.UploadSession
+	for _, session := range r.sessions {
+		if session.UpdatedAt.Before(before) && len(res) < limit {
+			res = append(res, session)
+		}
+	}
+	return res, nil
+}
+
+func (r *directUploadRepo) DeleteUploadSession(_ context.Context, id string) error {
+	r.sessions = slices.DeleteFunc(r.sessions, func(session domain.UploadSession) bool { return session.ID == id })
+	return nil
+}
+
+func (r *directUploadRepo) DeleteFileByPath(_ context.Context, filePath string) error {
+	r.files = slices.DeleteFunc(r.files, func(file domain.FileUpload) bool { return file.FilePath == filePath })
+	return nil
+}
+
+// expire backdates every record and session past the temporary upload TTL
+func (r *directUploadRepo) expire() {
+	for i := range r.files {
+		r.files[i].CreatedAt = time.Now().Add(-2 * temporaryUploadTTL())
+	}
+	for i := range r.sessions {
+		r.sessions[i].UpdatedAt = time.Now().Add(-2 * temporaryUploadTTL())
+	}
+}
+
+// setupDirectUploads configures the s3 driver on a fresh bucket of the MinIO stand-in named by
+// S3_TEST_ENDPOINT, with uploads limited to 1 MB. Configuration is loaded once per test binary.
+func setupDirectUploads(t *testing.T) (*fileService, *directUploadRepo, *minio.Client, string) {
+	t.Helper()
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT is not set")
+	}
+	accessKey := envOr("S3_TEST_ACCESS_KEY", "minioadmin")
+	secretKey := envOr("S3_TEST_SECRET_KEY", "minioadmin")
+
+	client, err := minio.New(endpoint, &minio.Options{Creds: credentials.NewStaticV4(accessKey, secretKey, "")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	bucket := "uploads-test-" + strings.ToLower(ksuid.New().String())
+	if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		for object := range client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Recursive: true}) {
+			if object.Err == nil {
+				client.RemoveObject(ctx, bucket, object.Key, minio.RemoveObjectOptions{})
+			}
+		}
+		client.RemoveBucket(ctx, bucket)
+	})
+
+	dir := t.TempDir()
+	env := fmt.Sprintf(`logger:
+  log_level: error
+  encoding: console
+storage:
+  driver: s3
+object_storage:
+  bucket: %s
+  endpoint: %s
+  access_key: %s
+  secret_key: %s
+  max_file_size: 1
+  use_ssl: false
+  presign_expiration: 1h
+`, bucket, endpoint, accessKey, secretKey)
+	if err := os.WriteFile(filepath.Join(dir, "env.yaml"), []byte(env), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_PATH", dir)
+	cfg := config.LoadConfig()
+	logger.NewZapLogger(cfg.Logger)
+	storage.InitDriver(cfg)
+
+	repo := &directUploadRepo{}
+	return &fileService{fileRepo: repo}, repo, client, bucket
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// sendDirectUpload posts content to a presigned form the way a browser would
+func sendDirectUpload(t *testing.T, upload response.PresignedUpload, content []byte) {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range upload.Fields {
+		form.WriteField(name, value)
+	}
+	part, err := form.CreateFormFile("file", "upload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	form.Close()
+
+	res, err := http.Post(upload.URL, form.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		msg, _ := io.ReadAll(res.Body)
+		t.Fatalf("bucket refused the upload with %d: %s", res.StatusCode, msg)
+	}
+}
+
+func appErrorCode(err error) int {
+	var appErr internal_err.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return 0
+}
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDirectUpload(t *testing.T) {
+	s, repo, client, bucket := setupDirectUploads(t)
+	ctx := context.Background()
+
+	presign := func(t *testing.T, fileName, contentType string, content []byte) response.PresignedUpload {
+		t.Helper()
+		upload, err := s.PresignUpload(ctx, "user1", requests.PresignUpload{
+			FileName:    fileName,
+			ContentType: contentType,
+			Size:        int64(len(content)),
+			Module:      "blog",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sendDirectUpload(t, upload, content)
+		return upload
+	}
+
+	t.Run("content type must match the file", func(t *testing.T) {
+		_, err := s.PresignUpload(ctx, "user1", requests.PresignUpload{FileName: "a.png", ContentType: "image/jpeg", Size: 10})
+		if appErrorCode(err) != http.StatusBadRequest {
+			t.Errorf("presigning a png as jpeg = %v, want a bad request", err)
+		}
+	})
+
+	t.Run("size must be within the limit", func(t *testing.T) {
+		_, err := s.PresignUpload(ctx, "user1", requests.PresignUpload{FileName: "a.png", ContentType: "image/png", Size: 2 << 20})
+		if appErrorCode(err) != http.StatusBadRequest {
+			t.Errorf("presigning 2 MB with a 1 MB limit = %v, want a bad request", err)
+		}
+	})
+
+	t.Run("completing records the file once", func(t *testing.T) {
+		content := testPNG(t)
+		upload := presign(t, "photo.png", "image/png", content)
+		if upload.MaxSize != 1<<20 {
+			t.Errorf("max size %d, want the configured 1 MB", upload.MaxSize)
+		}
+
+		file, err := s.CompleteUpload(ctx, "user1", requests.CompleteUpload{Key: upload.Key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if file.OriginalName != "photo.png" || file.ContentType != "image/png" || !file.IsTemporary {
+			t.Errorf("recorded %+v", file)
+		}
+		if len(repo.files) != 1 || repo.files[0].UploadedBy != "user1" || repo.files[0].Module != "blog" {
+			t.Fatalf("records %+v", repo.files)
+		}
+
+		again, err := s.CompleteUpload(ctx, "user1", requests.CompleteUpload{Key: upload.Key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again.ID != file.ID || len(repo.files) != 1 {
+			t.Errorf("completing twice recorded %+v", repo.files)
+		}
+	})
+
+	t.Run("only the uploader completes", func(t *testing.T) {
+		upload := presign(t, "other.png", "image/png", testPNG(t))
+		_, err := s.CompleteUpload(ctx, "user2", requests.CompleteUpload{Key: upload.Key})
+		if appErrorCode(err) != http.StatusForbidden {
+			t.Errorf("completing another user's upload = %v, want forbidden", err)
+		}
+	})
+
+	t.Run("unknown keys are not found", func(t *testing.T) {
+		_, err := s.CompleteUpload(ctx, "user1", requests.CompleteUpload{Key: "missing.png"})
+		if appErrorCode(err) != http.StatusNotFound {
+			t.Errorf("completing a missing upload = %v, want not found", err)
+		}
+	})
+
+	t.Run("content not matching the extension is removed", func(t *testing.T) {
+		upload := presign(t, "fake.png", "image/png", []byte("<html><script>alert(1)</script></html>"))
+		_, err := s.CompleteUpload(ctx, "user1", requests.CompleteUpload{Key: upload.Key})
+		if appErrorCode(err) != http.StatusBadRequest {
+			t.Errorf("completing html named png = %v, want a bad request", err)
+		}
+		if _, err := client.StatObject(ctx, bucket, upload.Key, minio.StatObjectOptions{}); err == nil {
+			t.Error("rejected upload is still in the bucket")
+		}
+	})
+
+	t.Run("svg is sanitized in the bucket", func(t *testing.T) {
+		content := []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script><rect width="1" height="1"/></svg>`)
+		upload := presign(t, "icon.svg", "image/svg+xml", content)
+		if _, err := s.CompleteUpload(ctx, "user1", requests.CompleteUpload{Key: upload.Key}); err != nil {
+			t.Fatal(err)
+		}
+		object, err := client.GetObject(ctx, bucket, upload.Key, minio.GetObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored, err := io.ReadAll(object)
+		object.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(stored, []byte("<script")) || !bytes.Contains(stored, []byte("<rect")) {
+			t.Errorf("stored svg %s", stored)
+		}
+	})
+
+	t.Run("inline images saved into content survive the sweep", func(t *testing.T) {
+		inline := presign(t, "inline.png", "image/png", testPNG(t))
+		file, err := s.CompleteUpload(ctx, "user1", requests.CompleteUpload{Key: inline.Key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		abandoned := presign(t, "abandoned.png", "image/png", testPNG(t))
+		if _, err := s.CompleteUpload(ctx, "user1", requests.CompleteUpload{Key: abandoned.Key}); err != nil {
+			t.Fatal(err)
+		}
+
+		// the editor inserts the url the upload returned
+		article := domain.BlogArtikel{Content: fmt.Sprintf(`<p>text</p><img src="%s" alt="">`, file.URL)}
+		article.ID = "article1"
+		if err := syncArticleFiles(ctx, repo, article); err != nil {
+			t.Fatal(err)
+		}
+		repo.expire()
+		if err := s.SweepTemporaryUploads(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := client.StatObject(ctx, bucket, inline.Key, minio.StatObjectOptions{}); err != nil {
+			t.Errorf("inline image %s was swept: %v", file.URL, err)
+		}
+		records, _ := repo.ListFilesByPaths(ctx, []string{uploadPath(inline.Key)})
+		if len(records) != 1 || records[0].IsTemporary {
+			t.Errorf("inline image records %+v, want it promoted", records)
+		}
+		if _, err := client.StatObject(ctx, bucket, abandoned.Key, minio.StatObjectOptions{}); err == nil {
+			t.Error("abandoned upload was not swept")
+		}
+	})
+
+	t.Run("uploads never completed are swept", func(t *testing.T) {
+		pending := presign(t, "pending.png", "image/png", testPNG(t))
+		completed := presign(t, "completed.png", "image/png", testPNG(t))
+		file, err := s.CompleteUpload(ctx, "user1", requests.CompleteUpload{Key: completed.Key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		article := domain.BlogArtikel{ImageURL: file.URL}
+		article.ID = "article2"
+		if err := syncArticleFiles(ctx, repo, article); err != nil {
+			t.Fatal(err)
+		}
+		repo.expire()
+		if err := s.SweepTemporaryUploads(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := client.StatObject(ctx, bucket, pending.Key, minio.StatObjectOptions{}); err == nil {
+			t.Error("upload never completed was not swept")
+		}
+		if _, err := client.StatObject(ctx, bucket, completed.Key, minio.StatObjectOptions{}); err != nil {
+			t.Errorf("completed upload was swept: %v", err)
+		}
+		if len(repo.sessions) != 0 {
+			t.Errorf("sessions %+v left after the sweep", repo.sessions)
+		}
+	})
+}
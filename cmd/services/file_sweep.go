@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/pkg/config"
+	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/storage"
+	"sora_landing_be/pkg/utils"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func temporaryUploadTTL() time.Duration {
+	ttl := config.LoadConfig().Storage.TemporaryTTL
+	return utils.Fallback(ttl, constants.TemporaryUploadDefaultTTL, ttl > 0)
+}
+
+// SweepTemporaryUploads deletes a batch of uploads nothing was saved with for longer than
+// storage.temporary_ttl, from the database and from whichever driver stores them. Expired
+// uploads content still points at without having promoted them are kept instead. Resumable
+// uploads that received nothing for as long are dropped along with their chunks, presigned
+// uploads never completed along with the object the client sent.
+func (s *fileService) SweepTemporaryUploads(ctx context.Context) error {
+	before := time.Now().Add(-temporaryUploadTTL())
+
+	kept, err := s.fileRepo.PromoteReferencedFiles(ctx, before)
+	if err != nil {
+		return err
+	}
+	for _, filePath := range kept {
+		unmarkTemporary(ctx, filePath)
+	}
+
+	files, err := s.fileRepo.ListAbandonedFiles(ctx, before, constants.TemporaryUploadSweepBatch)
+	if err != nil {
+		return err
+	}
+	removed := 0
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.removeFile(ctx, file); err != nil {
+			logger.Log.Warn("failed to remove abandoned upload", zap.String("path", file.FilePath), zap.Error(err))
+			continue
+		}
+		removed++
+	}
+
+	sessions, err := s.fileRepo.ListStaleUploadSessions(ctx, before, constants.TemporaryUploadSweepBatch)
+	if err != nil {
+		return err
+	}
+	expired := 0
+	for _, session := range sessions {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.expireUploadSession(ctx, session); err != nil {
+			logger.Log.Warn("failed to remove expired upload session", zap.String("id", session.ID), zap.Error(err))
+			continue
+		}
+		expired++
+	}
+
+	if removed > 0 || len(kept) > 0 || expired > 0 {
+		logger.Log.Info("swept temporary uploads",
+			zap.Int("removed", removed), zap.Int("kept", len(kept)), zap.Int("sessions_expired", expired))
+	}
+	return nil
+}
+
+// expireUploadSession drops a stale upload session. A presigned upload never completed also
+// loses its object, unless the file was recorded after all.
+func (s *fileService) expireUploadSession(ctx context.Context, session domain.UploadSession) error {
+	if session.Direct && session.FileID == nil {
+		recorded, err := s.fileRepo.ListFilesByPaths(ctx, []string{uploadPath(session.FileKey)})
+		if err != nil {
+			return err
+		}
+		if len(recorded) == 0 {
+			if err := storage.GetDriver().Delete(ctx, session.FileKey); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return err
+			}
+		}
+	}
+	return s.removeUploadSession(ctx, session)
+}
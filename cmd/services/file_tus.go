@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/dto/response"
+	"sora_landing_be/pkg/config"
+	internal_err "sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/storage"
+	"sora_landing_be/pkg/utils"
+	"strings"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"go.uber.org/zap"
+)
+
+// tusPartPrefix starts the keys of stored chunks, hidden files drivers leave out of listings
+const tusPartPrefix = ".tus-"
+
+// CreateResumableUpload starts a tus upload of a file of the declared length. Upload-Metadata
+// names the file and may say what it is for, like the form fields of a regular upload.
+func (s *fileService) CreateResumableUpload(ctx context.Context, userID string, payload requests.CreateResumableUpload) (response.ResumableUpload, error) {
+	if payload.Length == nil || *payload.Length <= 0 {
+		return response.ResumableUpload{}, internal_err.StorageErrorToAppError("Upload-Length is required")
+	}
+	length := *payload.Length
+	if length > config.LoadConfig().Storage.ResumableMaxSizeBytes() {
+		return response.ResumableUpload{}, internal_err.NewDefaultError(http.StatusRequestEntityTooLarge, internal_err.ErrUploadTooLarge)
+	}
+
+	metadata, err := parseUploadMetadata(payload.Metadata)
+	if err != nil {
+		return response.ResumableUpload{}, internal_err.StorageErrorToAppError(err.Error())
+	}
+	fileName := filepath.Base(metadata["filename"])
+	if metadata["filename"] == "" {
+		return response.ResumableUpload{}, internal_err.StorageErrorToAppError("Upload-Metadata must include filename")
+	}
+	ext := filepath.Ext(fileName)
+	if utils.UploadFormat(ext) == "" {
+		return response.ResumableUpload{}, internal_err.StorageErrorToAppError(fmt.Sprintf("Cannot upload file with format: %s", ext))
+	}
+	// images are cleaned in memory once complete, they stay within the regular limit
+	if maxSize := config.LoadConfig().ObjectStorage.MaxFileSizeBytes(); utils.IsCleanedFormat(ext) && length > maxSize {
+		return response.ResumableUpload{}, internal_err.StorageErrorToAppError(fmt.Sprintf("File is larger than %d MB", maxSize>>20))
+	}
+	if len(metadata["module"]) > 50 {
+		return response.ResumableUpload{}, internal_err.StorageErrorToAppError("Module must be at most 50 characters")
+	}
+
+	session := domain.UploadSession{
+		FileKey:      utils.GenerateKeyFile(fileName),
+		FileName:     fileName,
+		UploadLength: length,
+		Parts:        []string{},
+		Metadata:     payload.Metadata,
+		Module:       metadata["module"],
+		IsPublic:     metadata["is_public"] == "true",
+		UploadedBy:   userID,
+	}
+	if referenceID := metadata["reference_id"]; referenceID != "" {
+		session.ReferenceID = &referenceID
+	}
+	if err := s.fileRepo.CreateUploadSession(ctx, &session); err != nil {
+		return response.ResumableUpload{}, err
+	}
+	return newResumableUpload(session), nil
+}
+
+// GetResumableUpload returns how much of an upload was received
+func (s *fileService) GetResumableUpload(ctx context.Context, userID, id string) (response.ResumableUpload, error) {
+	session, err := s.ownUploadSession(ctx, userID, id)
+	if err != nil {
+		return response.ResumableUpload{}, err
+	}
+	return newResumableUpload(session), nil
+}
+
+// AppendResumableUpload stores the next chunk of an upload, which must start where the last
+// one ended. What arrived before a dropped connection is kept so the client can resume from
+// there. The chunk completing the file joins the parts and records the file like CompleteUpload.
+func (s *fileService) AppendResumableUpload(ctx context.Context, userID, id string, payload requests.AppendResumableUpload, body io.Reader) (response.ResumableUpload, error) {
+	if payload.Offset == nil {
+		return response.ResumableUpload{}, internal_err.StorageErrorToAppError("Upload-Offset is required")
+	}
+	session, err := s.ownUploadSession(ctx, userID, id)
+	if err != nil {
+		return response.ResumableUpload{}, err
+	}
+	if session.FileID != nil || *payload.Offset != session.UploadOffset {
+		return response.ResumableUpload{}, internal_err.NewDefaultError(http.StatusConflict, internal_err.ErrUploadOffset)
+	}
+
+	tmp, err := os.CreateTemp("", "tus-*")
+	if err != nil {
+		return response.ResumableUpload{}, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	remaining := session.UploadLength - session.UploadOffset
+	size, readErr := io.Copy(tmp, io.LimitReader(body, remaining+1))
+	if size > remaining {
+		return response.ResumableUpload{}, internal_err.StorageErrorToAppError(internal_err.ErrUploadPastLength)
+	}
+	if size == 0 {
+		if readErr != nil {
+			return response.ResumableUpload{}, internal_err.StorageErrorToAppError("Failed to read uploaded file")
+		}
+		return newResumableUpload(session), nil
+	}
+	if readErr != nil {
+		logger.Log.Info("keeping partial chunk", zap.String("upload", id), zap.Int64("size", size), zap.Error(readErr))
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return response.ResumableUpload{}, err
+	}
+
+	// the request context ends with a dropped connection, what was read is stored anyway
+	ctx = context.WithoutCancel(ctx)
+	driver := storage.GetDriver()
+	part := fmt.Sprintf("%s%s-%d-%s", tusPartPrefix, session.ID, session.UploadOffset, ksuid.New().String())
+	if err := driver.Put(ctx, part, tmp, size, "application/octet-stream"); err != nil {
+		return response.ResumableUpload{}, err
+	}
+	ok, err := s.fileRepo.AppendUploadPart(ctx, session.ID, session.UploadOffset, session.UploadOffset+size, part)
+	if err != nil || !ok {
+		removeUploadParts(ctx, []string{part})
+		if err != nil {
+			return response.ResumableUpload{}, err
+		}
+		return response.ResumableUpload{}, internal_err.NewDefaultError(http.StatusConflict, internal_err.ErrUploadOffset)
+	}
+	session.UploadOffset += size
+	session.Parts = append(session.Parts, part)
+	session.UpdatedAt = time.Now()
+
+	if session.UploadOffset == session.UploadLength {
+		if err := s.finishResumableUpload(ctx, &session); err != nil {
+			if err := s.removeUploadSession(ctx, session); err != nil {
+				logger.Log.Warn("failed to remove resumable upload", zap.String("id", session.ID), zap.Error(err))
+			}
+			return response.ResumableUpload{}, err
+		}
+	}
+	return newResumableUpload(session), nil
+}
+
+// TerminateResumableUpload drops an upload and the chunks received so far. A file it was
+// completed into stays, temporary until an article uses it.
+func (s *fileService) TerminateResumableUpload(ctx context.Context, userID, id string) error {
+	session, err := s.ownUploadSession(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	return s.removeUploadSession(ctx, session)
+}
+
+func (s *fileService) ownUploadSession(ctx context.Context, userID, id string) (domain.UploadSession, error) {
+	session, err := s.fileRepo.GetUploadSession(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.UploadSession{}, internal_err.NewDefaultError(http.StatusNotFound, internal_err.ErrUploadNotFound)
+		}
+		return domain.UploadSession{}, err
+	}
+	// presigned uploads are completed through CompleteUpload, not resumed
+	if session.Direct {
+		return domain.UploadSession{}, internal_err.NewDefaultError(http.StatusNotFound, internal_err.ErrUploadNotFound)
+	}
+	if session.UploadedBy != userID {
+		return domain.UploadSession{}, internal_err.NewDefaultError(http.StatusForbidden, internal_err.ErrUploadNotOwned)
+	}
+	// expired uploads wait for the sweep, they can no longer be resumed
+	if session.FileID == nil && time.Now().After(uploadSessionExpiry(session)) {
+		return domain.UploadSession{}, internal_err.NewDefaultError(http.StatusNotFound, internal_err.ErrUploadNotFound)
+	}
+	return session, nil
+}
+
+// finishResumableUpload joins the parts of a complete upload into its file and records it
+func (s *fileService) finishResumableUpload(ctx context.Context, session *domain.UploadSession) error {
+	driver := storage.GetDriver()
+	contentType := mime.TypeByExtension(filepath.Ext(session.FileKey))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	parts := &partsReader{ctx: ctx, keys: session.Parts}
+	err := driver.Put(ctx, session.FileKey, parts, session.UploadLength, contentType)
+	parts.Close()
+	if err != nil {
+		return err
+	}
+	removeUploadParts(ctx, session.Parts)
+	session.Parts = []string{}
+	if err := driver.MarkTemporary(ctx, session.FileKey, true); err != nil {
+		logger.Log.Warn("failed to mark upload temporary", zap.String("key", session.FileKey), zap.Error(err))
+	}
+
+	file := domain.FileUpload{
+		FileName:    session.FileName,
+		FilePath:    uploadPath(session.FileKey),
+		Module:      session.Module,
+		ReferenceID: session.ReferenceID,
+		UploadedBy:  session.UploadedBy,
+		IsPublic:    session.IsPublic,
+	}
+	if err := s.recordStoredUpload(ctx, &file, session.UploadLength); err != nil {
+		// rejected files are removed already, this covers failing to record them
+		if err := driver.Delete(ctx, session.FileKey); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			logger.Log.Warn("failed to remove unrecorded upload", zap.String("key", session.FileKey), zap.Error(err))
+		}
+		return err
+	}
+	if err := s.fileRepo.FinishUploadSession(ctx, session.ID, file.ID); err != nil {
+		return err
+	}
+	session.FileID = &file.ID
+	return nil
+}
+
+func (s *fileService) removeUploadSession(ctx context.Context, session domain.UploadSession) error {
+	removeUploadParts(ctx, session.Parts)
+	return s.fileRepo.DeleteUploadSession(ctx, session.ID)
+}
+
+func removeUploadParts(ctx context.Context, parts []string) {
+	for _, part := range parts {
+		if err := storage.GetDriver().Delete(ctx, part); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			logger.Log.Warn("failed to remove upload part", zap.String("key", part), zap.Error(err))
+		}
+	}
+}
+
+func uploadSessionExpiry(session domain.UploadSession) time.Time {
+	return session.UpdatedAt.Add(temporaryUploadTTL())
+}
+
+func newResumableUpload(session domain.UploadSession) response.ResumableUpload {
+	return response.NewResumableUpload(session, uploadSessionExpiry(session))
+}
+
+// parseUploadMetadata decodes an Upload-Metadata header, comma separated pairs of a key
+// and a base64 value, the value may be left out
+func parseUploadMetadata(header string) (map[string]string, error) {
+	res := map[string]string{}
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, encoded, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("Upload-Metadata value of %s is not base64", key)
+		}
+		res[key] = string(value)
+	}
+	return res, nil
+}
+
+// partsReader reads the stored chunks of an upload one after another, opening each when reached
+type partsReader struct {
+	ctx     context.Context
+	keys    []string
+	current io.ReadCloser
+}
+
+func (r *partsReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.keys) == 0 {
+				return 0, io.EOF
+			}
+			src, err := storage.GetDriver().Open(r.ctx, r.keys[0])
+			if err != nil {
+				return 0, err
+			}
+			r.current, r.keys = src, r.keys[1:]
+		}
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (r *partsReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	return r.current.Close()
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"mime"
+	"path"
+	"path/filepath"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto/response"
+	"sora_landing_be/cmd/repository"
+	"sora_landing_be/pkg/config"
+	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/storage"
+	"sora_landing_be/pkg/utils"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// defaultImageVariants is used when image.variants is not configured
+var defaultImageVariants = []config.ImageVariant{
+	{Name: "thumb", Width: 320},
+	{Name: "card", Width: 640},
+	{Name: "hero", Width: 1280},
+}
+
+func imageVariants() []config.ImageVariant {
+	variants := config.LoadConfig().Image.Variants
+	return utils.Fallback(variants, defaultImageVariants, len(variants) > 0)
+}
+
+func webpQuality() int {
+	quality := config.LoadConfig().Image.WebPQuality
+	return utils.Fallback(quality, constants.WebPDefaultQuality, quality > 0 && quality <= 100)
+}
+
+// imageFormat returns the format of an image upload, empty for files variants cannot be made of
+func imageFormat(contentType string) string {
+	format := strings.TrimPrefix(contentType, "image/")
+	if format == contentType || !utils.IsResizableFormat(format) {
+		return ""
+	}
+	return format
+}
+
+// generateVariants stores the configured sizes of an uploaded image, in its own format and
+// in WebP, next to it and records them with the file. Images are never scaled up, sizes
+// wider than the original are skipped. Other files are left alone.
+func generateVariants(ctx context.Context, fileRepo repository.FileRepository, file *domain.FileUpload) error {
+	format := imageFormat(file.ContentType)
+	if format == "" {
+		return nil
+	}
+
+	driver := storage.GetDriver()
+	src, err := driver.Open(ctx, path.Base(file.FilePath))
+	if err != nil {
+		return err
+	}
+	img, _, err := image.Decode(src)
+	src.Close()
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	previous := file.Variants
+	file.Width = img.Bounds().Dx()
+	file.Height = img.Bounds().Dy()
+	file.Variants = nil
+
+	base := strings.TrimSuffix(file.FilePath, filepath.Ext(file.FilePath))
+	write := func(name string, img image.Image, format string) error {
+		ext := utils.ImageFormatExt(format)
+		variant := &domain.FileVariant{
+			Name:        name,
+			Format:      format,
+			FilePath:    base + "_" + name + ext,
+			ContentType: mime.TypeByExtension(ext),
+			Width:       img.Bounds().Dx(),
+			Height:      img.Bounds().Dy(),
+		}
+		quality := utils.Fallback(webpQuality(), utils.Quality, format == constants.ImageFormatWebP)
+		var buf bytes.Buffer
+		if err := utils.EncodeImage(&buf, img, format, quality); err != nil {
+			return err
+		}
+		variant.FileSize = int64(buf.Len())
+		if err := driver.Put(ctx, path.Base(variant.FilePath), &buf, variant.FileSize, variant.ContentType); err != nil {
+			return err
+		}
+		file.Variants = append(file.Variants, variant)
+		return nil
+	}
+
+	err = func() error {
+		for _, size := range imageVariants() {
+			if size.Name == "" || size.Width <= 0 || size.Width >= file.Width {
+				continue
+			}
+			resized := utils.ResizeToWidth(img, size.Width)
+			if format != constants.ImageFormatWebP {
+				if err := write(size.Name, resized, format); err != nil {
+					return err
+				}
+			}
+			if err := write(size.Name, resized, constants.ImageFormatWebP); err != nil {
+				return err
+			}
+		}
+		// browsers taking WebP get it at full size as well
+		if format != constants.ImageFormatWebP {
+			return write(constants.ImageVariantOriginal, img, constants.ImageFormatWebP)
+		}
+		return nil
+	}()
+	if err == nil {
+		err = fileRepo.SaveFileVariants(ctx, *file)
+	}
+	if err != nil {
+		removeVariantFiles(ctx, file.Variants, previous)
+		file.Variants = previous
+		return err
+	}
+
+	// variants of sizes no longer configured
+	removeVariantFiles(ctx, previous, file.Variants)
+	return nil
+}
+
+// removeVariantFiles deletes the files of variants, except the ones also listed in keep
+func removeVariantFiles(ctx context.Context, variants []*domain.FileVariant, keep []*domain.FileVariant) {
+	for _, variant := range variants {
+		kept := false
+		for _, other := range keep {
+			if other.FilePath == variant.FilePath {
+				kept = true
+				break
+			}
+		}
+		if kept {
+			continue
+		}
+		if err := storage.GetDriver().Delete(ctx, path.Base(variant.FilePath)); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			logger.Log.Warn("failed to remove image variant", zap.String("path", variant.FilePath), zap.Error(err))
+		}
+	}
+}
+
+// responsiveImage describes the stored sizes of an image upload, nil for other files
+func responsiveImage(file domain.FileUpload) *response.ResponsiveImage {
+	format := imageFormat(file.ContentType)
+	if format == "" || file.Width == 0 {
+		return nil
+	}
+
+	res := &response.ResponsiveImage{
+		// the upload route like fileURL, editors insert it into content
+		Src:    uploadURL(path.Base(file.FilePath)),
+		Width:  file.Width,
+		Height: file.Height,
+	}
+	byFormat := map[string][]response.ImageVariant{
+		format: {{
+			Name:   constants.ImageVariantOriginal,
+			URL:    res.Src,
+			Width:  file.Width,
+			Height: file.Height,
+			Size:   file.FileSize,
+		}},
+	}
+	for _, variant := range file.Variants {
+		byFormat[variant.Format] = append(byFormat[variant.Format], response.ImageVariant{
+			Name:   variant.Name,
+			URL:    storage.GetDriver().URL(path.Base(variant.FilePath)),
+			Width:  variant.Width,
+			Height: variant.Height,
+			Size:   variant.FileSize,
+		})
+	}
+
+	formats := []string{constants.ImageFormatWebP}
+	if format != constants.ImageFormatWebP {
+		formats = append(formats, format)
+	}
+	for _, f := range formats {
+		variants := byFormat[f]
+		if len(variants) == 0 {
+			continue
+		}
+		sort.Slice(variants, func(i, j int) bool { return variants[i].Width < variants[j].Width })
+		srcset := make([]string, len(variants))
+		for i, variant := range variants {
+			srcset[i] = fmt.Sprintf("%s %dw", variant.URL, variant.Width)
+		}
+		res.Sources = append(res.Sources, response.ImageSource{
+			Type:     mime.TypeByExtension(utils.ImageFormatExt(f)),
+			Srcset:   strings.Join(srcset, ", "),
+			Variants: variants,
+		})
+	}
+	return res
+}
+
+// coverImages looks up the stored sizes of the cover images of articles, keyed by image URL
+func coverImages(ctx context.Context, fileRepo repository.FileRepository, articles ...domain.BlogArtikel) (map[string]*response.ResponsiveImage, error) {
+	var paths []string
+	for _, article := range articles {
+		if article.ImageURL != "" && !strings.Contains(article.ImageURL, "://") {
+			paths = append(paths, uploadPath(path.Base(article.ImageURL)))
+		}
+	}
+	files, err := fileRepo.ListFilesByPaths(ctx, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	byPath := make(map[string]*response.ResponsiveImage, len(files))
+	for _, file := range files {
+		byPath[file.FilePath] = responsiveImage(file)
+	}
+	res := make(map[string]*response.ResponsiveImage)
+	for _, article := range articles {
+		if img, ok := byPath[uploadPath(path.Base(article.ImageURL))]; ok && img != nil {
+			res[article.ImageURL] = img
+		}
+	}
+	return res, nil
+}
+
+// VariantBackfillOptions controls BackfillVariants
+type VariantBackfillOptions struct {
+	// Force regenerates the variants of images that already have them, after the sizes changed
+	Force bool
+	// UploadedBy records files found without a record as uploaded by this user, they are skipped when empty
+	UploadedBy string
+}
+
+// BackfillVariants generates the variants of the images already stored
+func (s *fileService) BackfillVariants(ctx context.Context, opts VariantBackfillOptions) (response.VariantBackfill, error) {
+	var res response.VariantBackfill
+
+	stored, err := storage.GetDriver().List(ctx)
+	if err != nil {
+		return res, err
+	}
+	variantPaths, err := s.fileRepo.ListVariantPaths(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	for _, object := range stored {
+		filePath := uploadPath(object.Key)
+		if utils.Contains(variantPaths, filePath) {
+			continue
+		}
+		res.Scanned++
+
+		files, err := s.fileRepo.ListFilesByPaths(ctx, []string{filePath})
+		if err != nil {
+			return res, err
+		}
+		var file domain.FileUpload
+		switch {
+		case len(files) > 0:
+			file = files[0]
+		case opts.UploadedBy == "":
+			res.Skipped++
+			continue
+		default:
+			file, err = s.registerUpload(ctx, filePath, opts.UploadedBy)
+			if err != nil {
+				logger.Log.Warn("failed to record upload", zap.String("path", filePath), zap.Error(err))
+				res.Failed++
+				continue
+			}
+			res.Registered++
+		}
+
+		if imageFormat(file.ContentType) == "" || (len(file.Variants) > 0 && !opts.Force) {
+			res.Skipped++
+			continue
+		}
+		if err := generateVariants(ctx, s.fileRepo, &file); err != nil {
+			logger.Log.Warn("failed to generate image variants", zap.String("path", filePath), zap.Error(err))
+			res.Failed++
+			continue
+		}
+		res.Generated++
+	}
+	return res, nil
+}
+
+// registerUpload records a file stored before uploads were tracked
+func (s *fileService) registerUpload(ctx context.Context, filePath, uploadedBy string) (domain.FileUpload, error) {
+	file := domain.FileUpload{
+		FileName:   path.Base(filePath),
+		FilePath:   filePath,
+		UploadedBy: uploadedBy,
+		IsPublic:   true,
+	}
+	src, err := storage.GetDriver().Open(ctx, path.Base(filePath))
+	if err != nil {
+		return file, err
+	}
+	defer src.Close()
+
+	if err := digestFile(&file, src, io.Discard); err != nil {
+		return file, err
+	}
+	return file, s.fileRepo.CreateFile(ctx, &file)
+}
@@ -15,11 +15,16 @@ type PoolService struct {
 	CategoryService CategoryService
 	BlogService     BlogService
 	DemoService     DemoService
+	ArchiveService  ArchiveService
+	FeedService     FeedService
+	LinkService     LinkAuditService
+	FileService     FileService
 }
 
 func Init() {
 	once.Do(func() {
 		repo := repository.RepoPool
+		blogService := NewBlogService(repo.BlogRepository, repo.TagRepository, repo.CategoryRepository, repo.ImportJobRepository, repo.FileRepository)
 		ServicePool = &PoolService{
 			AuthService: NewAuthSrv(repo.AuthenticationRepository),
 			UserService: NewUserSrv(
@@ -28,8 +33,12 @@ func Init() {
 			),
 			TagService:      NewTagService(repo.TagRepository),
 			CategoryService: NewCatService(repo.CategoryRepository),
-			BlogService:     NewBlogService(repo.BlogRepository, repo.TagRepository, repo.CategoryRepository),
+			BlogService:     blogService,
 			DemoService:     NewDemoService(repo.DemoRepository),
+			ArchiveService:  NewArchiveService(repo.ArchiveRepository),
+			FeedService:     NewFeedService(repo.FeedRepository, repo.CategoryRepository, repo.TagRepository, blogService),
+			LinkService:     NewLinkAuditService(repo.LinkRepository, repo.BlogRepository, repo.TagRepository),
+			FileService:     NewFileService(repo.FileRepository),
 		}
 	})
 }
@@ -0,0 +1,441 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/dto/response"
+	"sora_landing_be/cmd/repository"
+	"sora_landing_be/pkg/config"
+	internal_err "sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/http/client"
+	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/storage"
+	"sora_landing_be/pkg/utils"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+)
+
+type LinkAuditService interface {
+	CheckDueArticles(ctx context.Context) error
+	CheckArticle(ctx context.Context, id string) (response.ArticleLinks, error)
+	GetArticleLinks(ctx context.Context, id string) (response.ArticleLinks, error)
+	ListLinkReport(ctx context.Context, params requests.ListLinkReport) (dto.PaginationResponse[response.ArticleLinks], error)
+}
+
+type linkAuditService struct {
+	linkRepo repository.LinkRepository
+	blogRepo repository.BlogRepository
+	tagRepo  repository.TagRepository
+	// checking holds the IDs of articles whose links are being checked
+	checking sync.Map
+}
+
+func NewLinkAuditService(linkRepo repository.LinkRepository, blogRepo repository.BlogRepository, tagRepo repository.TagRepository) LinkAuditService {
+	return &linkAuditService{
+		linkRepo: linkRepo,
+		blogRepo: blogRepo,
+		tagRepo:  tagRepo,
+	}
+}
+
+// CheckDueArticles checks a batch of articles that were never checked, changed since their
+// last check or were checked more than constants.LinkCheckInterval ago
+func (s *linkAuditService) CheckDueArticles(ctx context.Context) error {
+	articles, err := s.linkRepo.ListArticlesDueForLinkCheck(ctx, time.Now().Add(-constants.LinkCheckInterval), constants.LinkAuditBatchSize)
+	if err != nil {
+		return err
+	}
+
+	checker := newLinkChecker()
+	for _, article := range articles {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		links, err := s.check(ctx, checker, &article)
+		if err != nil {
+			logger.Log.Warn("failed to check article links", zap.String("article_id", article.ID), zap.Error(err))
+			continue
+		}
+		logger.Log.Info("article links checked",
+			zap.String("article_id", article.ID),
+			zap.Int("links", len(links)),
+			zap.Int("issues", countLinkIssues(links)),
+		)
+	}
+	return nil
+}
+
+func (s *linkAuditService) CheckArticle(ctx context.Context, id string) (response.ArticleLinks, error) {
+	article, err := s.blogRepo.GetArticle(ctx, id)
+	if err != nil {
+		return response.ArticleLinks{}, err
+	}
+
+	links, err := s.check(ctx, newLinkChecker(), &article)
+	if err != nil {
+		return response.ArticleLinks{}, err
+	}
+	return response.NewArticleLinks(article, links), nil
+}
+
+func (s *linkAuditService) GetArticleLinks(ctx context.Context, id string) (response.ArticleLinks, error) {
+	article, err := s.blogRepo.GetArticle(ctx, id)
+	if err != nil {
+		return response.ArticleLinks{}, err
+	}
+
+	links, err := s.linkRepo.ListArticleLinks(ctx, id)
+	if err != nil {
+		return response.ArticleLinks{}, err
+	}
+	res := make([]*domain.ArticleLink, len(links))
+	for i := range links {
+		res[i] = &links[i]
+	}
+	return response.NewArticleLinks(article, res), nil
+}
+
+func (s *linkAuditService) ListLinkReport(ctx context.Context, params requests.ListLinkReport) (dto.PaginationResponse[response.ArticleLinks], error) {
+	articles, count, err := s.linkRepo.ListArticlesWithLinkIssues(ctx, params)
+	if err != nil {
+		return dto.PaginationResponse[response.ArticleLinks]{}, err
+	}
+	return dto.NewPaginationResponse(params.PaginationRequest, count, response.NewListArticleLinks(articles)), nil
+}
+
+// check finds, checks and stores the links of one article
+func (s *linkAuditService) check(ctx context.Context, checker *linkChecker, article *domain.BlogArtikel) ([]*domain.ArticleLink, error) {
+	if _, busy := s.checking.LoadOrStore(article.ID, true); busy {
+		return nil, internal_err.NewDefaultError(http.StatusConflict, "article links are already being checked")
+	}
+	defer s.checking.Delete(article.ID)
+
+	links := articleLinks(*article)
+	if err := s.checkInternal(ctx, links); err != nil {
+		return nil, err
+	}
+	checker.checkExternal(ctx, links)
+
+	checkedAt := time.Now()
+	if err := s.linkRepo.ReplaceArticleLinks(ctx, article.ID, links, checkedAt); err != nil {
+		return nil, err
+	}
+	article.LinksCheckedAt = checkedAt
+	return links, nil
+}
+
+// checkInternal resolves links to our own pages and uploads against the database and the
+// upload directory. Links to other pages of the website are left for the HTTP check.
+func (s *linkAuditService) checkInternal(ctx context.Context, links []*domain.ArticleLink) error {
+	targets := make(map[*domain.ArticleLink]internalTarget)
+	slugs := make(map[internalTargetKind][]string)
+	for _, link := range links {
+		target, ok := parseInternalLink(link.URL, link.Kind)
+		if !ok {
+			continue
+		}
+		link.Internal = true
+		if target.kind == internalPage {
+			continue
+		}
+		targets[link] = target
+		if target.kind != internalUpload && !utils.Contains(slugs[target.kind], target.key) {
+			slugs[target.kind] = append(slugs[target.kind], target.key)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	articles, err := s.linkRepo.ExistingArticleSlugs(ctx, slugs[internalArticle])
+	if err != nil {
+		return err
+	}
+	categories, err := s.linkRepo.ExistingCategorySlugs(ctx, slugs[internalCategory])
+	if err != nil {
+		return err
+	}
+	tags, err := s.linkRepo.ExistingTagSlugs(ctx, slugs[internalTag])
+	if err != nil {
+		return err
+	}
+	var missingTags []string
+	for _, slug := range slugs[internalTag] {
+		if !utils.Contains(tags, slug) {
+			missingTags = append(missingTags, slug)
+		}
+	}
+	// links to merged or renamed tags still work through the redirect, but should be updated
+	redirects, err := s.tagRepo.GetRedirectedSlugs(ctx, missingTags)
+	if err != nil {
+		return err
+	}
+
+	for link, target := range targets {
+		found := false
+		switch target.kind {
+		case internalUpload:
+			found, _ = storage.GetDriver().Exists(ctx, target.key)
+		case internalArticle:
+			found = utils.Contains(articles, target.key)
+		case internalCategory:
+			found = utils.Contains(categories, target.key)
+		case internalTag:
+			found = utils.Contains(tags, target.key)
+			if slug, ok := redirects[target.key]; !found && ok {
+				link.Status = constants.LinkStatusRedirected
+				link.FinalURL = frontendURL(fmt.Sprintf(constants.TagPathFormat, url.PathEscape(slug)))
+				continue
+			}
+		}
+		link.Status = utils.Fallback(constants.LinkStatusOK, constants.LinkStatusBroken, found)
+	}
+	return nil
+}
+
+// linkChecker requests external links with bounded concurrency. Results are kept for the
+// lifetime of the checker so a link shared by many articles is requested once per run.
+type linkChecker struct {
+	sem     chan struct{}
+	mu      sync.Mutex
+	results map[string]linkResult
+}
+
+type linkResult struct {
+	status     constants.LinkStatus
+	statusCode int
+	finalURL   string
+	err        string
+}
+
+func newLinkChecker() *linkChecker {
+	return &linkChecker{
+		sem:     make(chan struct{}, constants.LinkCheckConcurrency),
+		results: make(map[string]linkResult),
+	}
+}
+
+// checkExternal sets the status of every link not resolved by checkInternal
+func (c *linkChecker) checkExternal(ctx context.Context, links []*domain.ArticleLink) {
+	var wg sync.WaitGroup
+	for _, link := range links {
+		if link.Status != "" {
+			continue
+		}
+		wg.Add(1)
+		go func(link *domain.ArticleLink) {
+			defer wg.Done()
+			res := c.result(ctx, absoluteLinkURL(link.URL))
+			link.Status = res.status
+			link.StatusCode = res.statusCode
+			link.FinalURL = res.finalURL
+			link.Error = res.err
+		}(link)
+	}
+	wg.Wait()
+}
+
+func (c *linkChecker) result(ctx context.Context, rawURL string) linkResult {
+	c.mu.Lock()
+	res, ok := c.results[rawURL]
+	c.mu.Unlock()
+	if ok {
+		return res
+	}
+
+	select {
+	case c.sem <- struct{}{}:
+	case <-ctx.Done():
+		return linkResult{status: constants.LinkStatusUnreachable, err: ctx.Err().Error()}
+	}
+	res = probeLink(ctx, rawURL)
+	<-c.sem
+
+	c.mu.Lock()
+	c.results[rawURL] = res
+	c.mu.Unlock()
+	return res
+}
+
+func probeLink(ctx context.Context, rawURL string) linkResult {
+	ctx, cancel := context.WithTimeout(ctx, constants.LinkCheckTimeout)
+	defer cancel()
+
+	checked, err := client.GetSafeClient().Check(ctx, rawURL)
+	if err != nil {
+		return linkResult{status: constants.LinkStatusUnreachable, err: err.Error()}
+	}
+
+	res := linkResult{status: constants.LinkStatusOK, statusCode: checked.StatusCode}
+	switch {
+	case checked.StatusCode == http.StatusTooManyRequests:
+		// rate limited, the link itself may be fine
+		res.status = constants.LinkStatusUnreachable
+	case checked.StatusCode >= http.StatusBadRequest:
+		res.status = constants.LinkStatusBroken
+	case !sameLink(rawURL, checked.URL):
+		res.status = constants.LinkStatusRedirected
+		res.finalURL = checked.URL
+	}
+	return res
+}
+
+// sameLink ignores the differences that do not make a redirect worth fixing: an upgrade to
+// https, the host case and a trailing slash
+func sameLink(a, b string) bool {
+	normalize := func(raw string) string {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return raw
+		}
+		return strings.ToLower(u.Host) + strings.TrimSuffix(u.EscapedPath(), "/") + "?" + u.RawQuery
+	}
+	return normalize(a) == normalize(b)
+}
+
+func countLinkIssues(links []*domain.ArticleLink) int {
+	n := 0
+	for _, link := range links {
+		if link.Status != constants.LinkStatusOK {
+			n++
+		}
+	}
+	return n
+}
+
+// articleLinks collects the links and images of an article, cover image included
+func articleLinks(article domain.BlogArtikel) []*domain.ArticleLink {
+	var res []*domain.ArticleLink
+	seen := make(map[string]bool)
+	add := func(ref string, kind constants.LinkKind) {
+		ref = strings.TrimSpace(ref)
+		if !isCheckableLink(ref) || seen[string(kind)+" "+ref] {
+			return
+		}
+		seen[string(kind)+" "+ref] = true
+		res = append(res, &domain.ArticleLink{URL: ref, Kind: kind})
+	}
+
+	add(article.ImageURL, constants.LinkKindImage)
+	nodes, err := utils.ParseHTMLFragment(article.Content)
+	if err != nil {
+		return res
+	}
+	for _, node := range nodes {
+		utils.WalkHTML(node, func(n *html.Node) {
+			if n.Type != html.ElementNode {
+				return
+			}
+			switch n.Data {
+			case "a":
+				add(utils.GetAttr(n, "href"), constants.LinkKindLink)
+			case "img":
+				add(utils.GetAttr(n, "src"), constants.LinkKindImage)
+			}
+		})
+	}
+	return res
+}
+
+// isCheckableLink skips anchors on the same page and links that are not web addresses
+func isCheckableLink(ref string) bool {
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return false
+	}
+	lower := strings.ToLower(ref)
+	for _, scheme := range []string{"mailto:", "tel:", "javascript:", "data:"} {
+		if strings.HasPrefix(lower, scheme) {
+			return false
+		}
+	}
+	return true
+}
+
+type internalTargetKind int
+
+const (
+	internalPage internalTargetKind = iota
+	internalUpload
+	internalArticle
+	internalCategory
+	internalTag
+)
+
+type internalTarget struct {
+	kind internalTargetKind
+	key  string
+}
+
+// parseInternalLink recognises links to the website and to uploaded files. Image keys
+// stored without a path, such as cover images, count as uploads.
+func parseInternalLink(ref string, kind constants.LinkKind) (internalTarget, bool) {
+	if kind == constants.LinkKindImage && !strings.Contains(ref, "/") {
+		return internalTarget{kind: internalUpload, key: ref}, true
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return internalTarget{}, false
+	}
+	if u.Host != "" && !isOwnHost(u.Host) {
+		return internalTarget{}, false
+	}
+	if u.Host == "" && !strings.HasPrefix(u.Path, "/") {
+		return internalTarget{}, false
+	}
+
+	p := u.Path
+	prefixes := []struct {
+		prefix string
+		kind   internalTargetKind
+	}{
+		{"/" + storage.LocalUploadDir + "/", internalUpload},
+		{strings.TrimSuffix(constants.CategoryPathFormat, "%s"), internalCategory},
+		{strings.TrimSuffix(constants.TagPathFormat, "%s"), internalTag},
+		{strings.TrimSuffix(constants.ArticlePathFormat, "%s"), internalArticle},
+	}
+	for _, candidate := range prefixes {
+		if !strings.HasPrefix(p, candidate.prefix) {
+			continue
+		}
+		key := strings.Trim(strings.TrimPrefix(p, candidate.prefix), "/")
+		if candidate.kind == internalUpload {
+			key = path.Base(key)
+		}
+		if key != "" && !strings.Contains(key, "/") {
+			return internalTarget{kind: candidate.kind, key: key}, true
+		}
+	}
+	return internalTarget{kind: internalPage}, true
+}
+
+// isOwnHost reports whether host serves the website or this API
+func isOwnHost(host string) bool {
+	app := config.LoadConfig().Application
+	for _, base := range []string{app.FrontendURL, app.BaseURL} {
+		if u, err := url.Parse(base); err == nil && u.Host != "" && strings.EqualFold(u.Host, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// absoluteLinkURL resolves a link relative to the website, as a browser reading the article would
+func absoluteLinkURL(ref string) string {
+	base, err := url.Parse(config.LoadConfig().Application.FrontendURL)
+	if err != nil {
+		return ref
+	}
+	return utils.ResolveURL(base, ref)
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"path"
+	"sora_landing_be/cmd/domain"
+	"sora_landing_be/cmd/dto"
+	"sora_landing_be/cmd/dto/requests"
+	"sora_landing_be/cmd/dto/response"
+	internal_err "sora_landing_be/pkg/errors"
+	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/storage"
+
+	"go.uber.org/zap"
+)
+
+func (s *fileService) ListMedia(ctx context.Context, params requests.ListMedia) (dto.PaginationResponse[response.MediaFile], error) {
+	files, total, err := s.fileRepo.ListMedia(ctx, params)
+	if err != nil {
+		return dto.PaginationResponse[response.MediaFile]{}, err
+	}
+	list := response.NewListMediaFile(files)
+	for i, file := range files {
+		list[i].URL = fileURL(ctx, file.FileUpload)
+		list[i].Image = responsiveImage(file.FileUpload)
+	}
+	return dto.NewPaginationResponse(params.PaginationRequest, total, list), nil
+}
+
+func (s *fileService) GetMedia(ctx context.Context, id string) (response.MediaDetail, error) {
+	file, err := s.getFile(ctx, id)
+	if err != nil {
+		return response.MediaDetail{}, err
+	}
+	return s.newMediaDetail(ctx, file)
+}
+
+func (s *fileService) UpdateMedia(ctx context.Context, id string, payload requests.UpdateMedia) (response.MediaDetail, error) {
+	file, err := s.getFile(ctx, id)
+	if err != nil {
+		return response.MediaDetail{}, err
+	}
+
+	if payload.Title != nil {
+		file.Title = *payload.Title
+	}
+	if payload.AltText != nil {
+		file.AltText = *payload.AltText
+	}
+	if payload.Caption != nil {
+		file.Caption = *payload.Caption
+	}
+	if err := s.fileRepo.UpdateFileDetails(ctx, file); err != nil {
+		return response.MediaDetail{}, err
+	}
+	return s.newMediaDetail(ctx, file)
+}
+
+func (s *fileService) newMediaDetail(ctx context.Context, file domain.FileUpload) (response.MediaDetail, error) {
+	usages, err := s.fileRepo.ListFileUsages(ctx, file.ID)
+	if err != nil {
+		return response.MediaDetail{}, err
+	}
+	res := response.NewMediaDetail(file, usages)
+	res.URL = fileURL(ctx, file)
+	res.Image = responsiveImage(file)
+	return res, nil
+}
+
+func (s *fileService) DeleteMedia(ctx context.Context, id string) error {
+	file, err := s.getFile(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.deleteFile(ctx, file)
+}
+
+func (s *fileService) getFile(ctx context.Context, id string) (domain.FileUpload, error) {
+	file, err := s.fileRepo.GetFile(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return file, internal_err.NewDefaultError(http.StatusNotFound, internal_err.DataNotFound)
+		}
+		return file, err
+	}
+	return file, nil
+}
+
+// deleteFile removes a tracked file unless an article, category or tag still shows it
+func (s *fileService) deleteFile(ctx context.Context, file domain.FileUpload) error {
+	usages, err := s.fileRepo.ListFileUsages(ctx, file.ID)
+	if err != nil {
+		return err
+	}
+	if len(usages) > 0 {
+		return internal_err.NewDefaultError(http.StatusConflict, internal_err.ErrFileInUse)
+	}
+	return s.removeFile(ctx, file)
+}
+
+// removeFile deletes the record of a file and then its stored content and variants
+func (s *fileService) removeFile(ctx context.Context, file domain.FileUpload) error {
+	if err := s.fileRepo.DeleteFileByPath(ctx, file.FilePath); err != nil {
+		return err
+	}
+	if err := storage.GetDriver().Delete(ctx, path.Base(file.FilePath)); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		logger.Log.Warn("failed to remove upload", zap.String("path", file.FilePath), zap.Error(err))
+	}
+	removeVariantFiles(ctx, file.Variants, nil)
+	return nil
+}
@@ -3,13 +3,22 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/domain"
 	"sora_landing_be/cmd/dto"
 	"sora_landing_be/cmd/dto/requests"
 	"sora_landing_be/cmd/dto/response"
 	"sora_landing_be/cmd/repository"
 	"sora_landing_be/pkg/authentication"
 	"sora_landing_be/pkg/database"
+	internal_err "sora_landing_be/pkg/errors"
 	"sora_landing_be/pkg/utils"
+	"sort"
+	"strings"
 
 	"github.com/uptrace/bun"
 )
@@ -20,6 +29,14 @@ type TagService interface {
 	UpdateTag(ctx context.Context, id string, payload requests.TagRequest) error
 	DeleteTag(ctx context.Context, id string) error
 	GetTag(ctx context.Context, id string) (response.Tag, error)
+	MergeTags(ctx context.Context, targetID string, payload requests.MergeTags) (response.TagMerge, error)
+
+	ListSynonyms(ctx context.Context, tagID string) ([]response.TagSynonym, error)
+	CreateSynonym(ctx context.Context, tagID string, payload requests.TagSynonym) error
+	DeleteSynonym(ctx context.Context, tagID, id string) error
+
+	// Public endpoints
+	ListPublicTags(ctx context.Context, params requests.ListPublicTag) ([]response.PublicTag, error)
 }
 
 type tagService struct {
@@ -32,8 +49,18 @@ func NewTagService(tagRepo repository.TagRepository) TagService {
 	}
 }
 
+// CreateTag adds a tag unless its name already resolves to one, by name, slug or synonym. A
+// redirect left on the new slug by a renamed or merged tag is dropped so the slug filters the new tag.
 func (t *tagService) CreateTag(ctx context.Context, payload requests.TagRequest) error {
 	err := database.RunInTx(ctx, database.GetDB(), &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		resolved, err := t.tagRepo.GetTagByName(ctx, payload.Name)
+		if err != nil {
+			return err
+		}
+		if resolved != nil {
+			return internal_err.NewDefaultError(http.StatusBadRequest, fmt.Sprintf("%q already resolves to tag %q", payload.Name, resolved.Name))
+		}
+
 		uniqueSlug, err := utils.GenerateUniqueSlug(ctx, t.tagRepo, payload.Name)
 		if err != nil {
 			return err
@@ -44,7 +71,7 @@ func (t *tagService) CreateTag(ctx context.Context, payload requests.TagRequest)
 		if err != nil {
 			return err
 		}
-		return nil
+		return t.tagRepo.DeleteRedirect(ctx, uniqueSlug)
 	})
 
 	if err != nil {
@@ -68,21 +95,41 @@ func (a *tagService) ListTag(ctx context.Context, payload requests.ListTag) (dto
 func (a *tagService) UpdateTag(ctx context.Context, id string, payload requests.TagRequest) error {
 	var edited string
 	err := database.RunInTx(ctx, database.GetDB(), &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		uniqueSlug, err := utils.GenerateUniqueSlug(ctx, a.tagRepo, payload.Name)
+		existing, err := a.tagRepo.GetTag(ctx, id)
 		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return internal_err.NewDefaultError(http.StatusNotFound, internal_err.DataNotFound)
+			}
 			return err
 		}
-		data := payload.ToDomain(uniqueSlug)
+
+		// only a rename gets a new slug, the old one keeps working through a redirect
+		slug := existing.Slug
+		if payload.Name != existing.Name && utils.Slugify(payload.Name) != utils.Slugify(existing.Name) {
+			slug, err = utils.GenerateUniqueSlug(ctx, a.tagRepo, payload.Name)
+			if err != nil {
+				return err
+			}
+		}
+		data := payload.ToDomain(slug)
 		data.ID = id
 
 		edited = authentication.GetUserDataFromToken(ctx).UserID
 		data.EditedByID = &edited
 
+		// keep the current SEO metadata unless the request replaces it
+		if payload.Seo == nil {
+			data.SeoMeta = existing.SeoMeta
+		}
+
 		err = a.tagRepo.UpdateTag(ctx, &data)
 		if err != nil {
 			return err
 		}
 
+		if payload.Name != existing.Name {
+			return a.keepOldNames(ctx, data, []domain.Tag{existing}, edited)
+		}
 		return nil
 	})
 	if err != nil {
@@ -112,3 +159,175 @@ func (a *tagService) GetTag(ctx context.Context, id string) (response.Tag, error
 	res = response.NewTag(data)
 	return res, nil
 }
+
+func (a *tagService) MergeTags(ctx context.Context, targetID string, payload requests.MergeTags) (response.TagMerge, error) {
+	var res response.TagMerge
+
+	var sourceIDs []string
+	for _, id := range payload.SourceIDs {
+		if id == targetID {
+			return res, internal_err.NewDefaultError(http.StatusBadRequest, "a tag cannot be merged into itself")
+		}
+		if id != "" && !utils.Contains(sourceIDs, id) {
+			sourceIDs = append(sourceIDs, id)
+		}
+	}
+	if len(sourceIDs) == 0 {
+		return res, internal_err.NewDefaultError(http.StatusBadRequest, "source_ids is required")
+	}
+
+	err := database.RunInTx(ctx, database.GetDB(), &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		target, err := a.tagRepo.GetTag(ctx, targetID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return internal_err.NewDefaultError(http.StatusNotFound, internal_err.DataNotFound)
+			}
+			return err
+		}
+
+		sources, err := a.tagRepo.GetTagsByIDs(ctx, sourceIDs)
+		if err != nil {
+			return err
+		}
+		if len(sources) != len(sourceIDs) {
+			return internal_err.NewDefaultError(http.StatusNotFound, "one or more source tags were not found")
+		}
+
+		res.Retagged, err = a.tagRepo.MergeTags(ctx, target.ID, sourceIDs)
+		if err != nil {
+			return err
+		}
+		if err = a.keepOldNames(ctx, target, sources, authentication.GetUserDataFromToken(ctx).UserID); err != nil {
+			return err
+		}
+
+		res.Target = response.NewTag(target)
+		for _, source := range sources {
+			res.Merged = append(res.Merged, source.Name)
+		}
+		return nil
+	})
+	return res, err
+}
+
+// keepOldNames lets the names and slugs of tags that were renamed or merged away resolve to tag
+func (a *tagService) keepOldNames(ctx context.Context, tag domain.Tag, previous []domain.Tag, userID string) error {
+	var (
+		redirects []domain.TagRedirect
+		synonyms  []domain.TagSynonym
+	)
+	for _, old := range previous {
+		if old.Slug != tag.Slug {
+			redirects = append(redirects, domain.TagRedirect{OldSlug: old.Slug, TagID: tag.ID})
+		}
+		if normalized := utils.Slugify(old.Name); normalized != "" && normalized != tag.Slug {
+			synonyms = append(synonyms, domain.TagSynonym{TagID: tag.ID, Name: old.Name, Normalized: normalized, CreatedByID: userID})
+		}
+	}
+
+	// a tag renamed back to an earlier slug must not redirect away from itself
+	if err := a.tagRepo.DeleteRedirect(ctx, tag.Slug); err != nil {
+		return err
+	}
+	if err := a.tagRepo.SaveRedirects(ctx, redirects); err != nil {
+		return err
+	}
+	return a.tagRepo.SaveSynonyms(ctx, synonyms)
+}
+
+func (a *tagService) ListSynonyms(ctx context.Context, tagID string) ([]response.TagSynonym, error) {
+	if _, err := a.getTag(ctx, tagID); err != nil {
+		return nil, err
+	}
+	synonyms, err := a.tagRepo.ListSynonyms(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	return response.NewTagSynonyms(synonyms), nil
+}
+
+func (a *tagService) CreateSynonym(ctx context.Context, tagID string, payload requests.TagSynonym) error {
+	tag, err := a.getTag(ctx, tagID)
+	if err != nil {
+		return err
+	}
+
+	normalized := utils.Slugify(payload.Name)
+	if normalized == "" {
+		return internal_err.NewDefaultError(http.StatusBadRequest, "synonym must contain letters or digits")
+	}
+	resolved, err := a.tagRepo.GetTagByName(ctx, payload.Name)
+	if err != nil {
+		return err
+	}
+	if resolved != nil {
+		if resolved.ID != tag.ID {
+			return internal_err.NewDefaultError(http.StatusBadRequest, fmt.Sprintf("%q already resolves to tag %q, merge the tags instead", payload.Name, resolved.Name))
+		}
+		return internal_err.NewDefaultError(http.StatusBadRequest, internal_err.DataAlreadyExist)
+	}
+
+	return a.tagRepo.CreateSynonym(ctx, &domain.TagSynonym{
+		TagID:       tag.ID,
+		Name:        payload.Name,
+		Normalized:  normalized,
+		CreatedByID: authentication.GetUserDataFromToken(ctx).UserID,
+	})
+}
+
+func (a *tagService) DeleteSynonym(ctx context.Context, tagID, id string) error {
+	err := a.tagRepo.DeleteSynonym(ctx, tagID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return internal_err.NewDefaultError(http.StatusNotFound, internal_err.DataNotFound)
+	}
+	return err
+}
+
+// ListPublicTags returns tags used by published articles, most used first, or in cloud mode the most
+// used ones sorted by name with a weight from 1 to TagCloudLevels
+func (a *tagService) ListPublicTags(ctx context.Context, params requests.ListPublicTag) ([]response.PublicTag, error) {
+	cloud := params.Mode == "cloud"
+	limit := params.Limit
+	if cloud && limit <= 0 {
+		limit = constants.TagCloudDefaultLimit
+	}
+
+	counts, err := a.tagRepo.ListTagCounts(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]response.PublicTag, len(counts))
+	for i, t := range counts {
+		res[i] = response.NewPublicTag(t)
+	}
+	if !cloud || len(res) == 0 {
+		return res, nil
+	}
+
+	// counts are sorted descending, weights grow logarithmically so a few popular tags don't flatten the rest
+	most, least := math.Log(float64(res[0].ArticleCount)), math.Log(float64(res[len(res)-1].ArticleCount))
+	for i := range res {
+		if most == least {
+			res[i].Weight = (constants.TagCloudLevels + 1) / 2
+			continue
+		}
+		ratio := (math.Log(float64(res[i].ArticleCount)) - least) / (most - least)
+		res[i].Weight = 1 + int(math.Round(ratio*float64(constants.TagCloudLevels-1)))
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return strings.ToLower(res[i].Name) < strings.ToLower(res[j].Name)
+	})
+	return res, nil
+}
+
+func (a *tagService) getTag(ctx context.Context, id string) (domain.Tag, error) {
+	tag, err := a.tagRepo.GetTag(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return tag, internal_err.NewDefaultError(http.StatusNotFound, internal_err.DataNotFound)
+		}
+		return tag, err
+	}
+	return tag, nil
+}
@@ -40,7 +40,13 @@ func (u *userService) Register(ctx context.Context, payload requests.CreateUser)
 	err := database.RunInTx(ctx, database.GetDB(), &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
 		user := payload.ToDomain()
 
-		err := u.userRepo.CreateUser(ctx, &user)
+		slug, err := utils.GenerateUniqueSlug(ctx, u.userRepo, user.Name)
+		if err != nil {
+			return err
+		}
+		user.Slug = slug
+
+		err = u.userRepo.CreateUser(ctx, &user)
 		if err != nil {
 			return err
 		}
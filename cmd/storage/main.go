@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"sora_landing_be/pkg/config"
+	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/storage"
+)
+
+func main() {
+	// Parse command line flags
+	overwrite := flag.Bool("overwrite", false, "Replace files the bucket already has")
+	flag.Parse()
+
+	// Copies the local upload directory into the bucket, database records keep
+	// their /uploads paths so nothing else has to change before switching to s3
+	cfg := config.LoadConfig()
+	logger.NewZapLogger(cfg.Logger)
+	if cfg.ObjectStorage.Bucket == "" {
+		log.Fatal("object_storage is not configured")
+	}
+	local := storage.NewLocalDriver(cfg.Storage, cfg.Application.BaseURL)
+	bucket := storage.NewS3Driver(cfg.Storage, cfg.ObjectStorage)
+
+	res, err := storage.Copy(context.Background(), local, bucket, *overwrite)
+	if err != nil {
+		log.Fatalf("Error copying files: %v", err)
+	}
+	log.Printf("Copied %d files from %s to bucket %s, %d already there", res.Copied, local.Dir(), cfg.ObjectStorage.Bucket, res.Skipped)
+	if cfg.Storage.Driver != storage.DriverS3 {
+		log.Print("Set storage.driver to s3 to serve them from the bucket")
+	}
+}
@@ -0,0 +1,12 @@
+package templates
+
+import (
+	"embed"
+	"html/template"
+)
+
+//go:embed *.html
+var files embed.FS
+
+// ShareArticle renders the Open Graph page served to link preview crawlers
+var ShareArticle = template.Must(template.ParseFS(files, "share_article.html"))
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"sora_landing_be/cmd/repository"
+	"sora_landing_be/cmd/services"
+	"sora_landing_be/pkg/config"
+	"sora_landing_be/pkg/database"
+	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/storage"
+)
+
+func main() {
+	// Parse command line flags
+	force := flag.Bool("force", false, "Regenerate variants of images that already have them")
+	uploadedBy := flag.String("uploaded-by", "", "Record files found without a record as uploaded by this user ID, they are skipped otherwise")
+	flag.Parse()
+
+	// Initialize configuration and database
+	cfg := config.LoadConfig()
+	logger.NewZapLogger(cfg.Logger)
+	database.InitDB(cfg.Database)
+	storage.InitDriver(cfg)
+	repository.Init(database.GetDB())
+	services.Init()
+	ctx := context.Background()
+
+	res, err := services.ServicePool.FileService.BackfillVariants(ctx, services.VariantBackfillOptions{
+		Force:      *force,
+		UploadedBy: *uploadedBy,
+	})
+	if err != nil {
+		log.Fatalf("Error generating image variants: %v", err)
+	}
+	log.Printf("Scanned %d files: %d recorded, %d generated, %d skipped, %d failed",
+		res.Scanned, res.Registered, res.Generated, res.Skipped, res.Failed)
+}
@@ -0,0 +1,61 @@
+package workers
+
+import (
+	"context"
+	"sora_landing_be/cmd/constants"
+	"sora_landing_be/cmd/services"
+	"sora_landing_be/pkg/logger"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Start launches the background jobs, which stop once ctx is cancelled. It must be called after
+// the service pool is initialized, the returned group is done when every job has stopped.
+func Start(ctx context.Context) *sync.WaitGroup {
+	pool := services.ServicePool
+	jobs := &sync.WaitGroup{}
+	start := func(name string, interval time.Duration, fn func(ctx context.Context) error) {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			runEvery(ctx, name, interval, fn)
+		}()
+	}
+
+	start("feed_poller", constants.FeedPollTick, pool.FeedService.PollDueSubscriptions)
+	start("article_expiry", constants.ArticleExpiryTick, pool.BlogService.ArchiveExpiredArticles)
+	start("link_audit", constants.LinkAuditTick, pool.LinkService.CheckDueArticles)
+	start("upload_sweep", constants.TemporaryUploadSweepTick, pool.FileService.SweepTemporaryUploads)
+	return jobs
+}
+
+// runEvery calls fn once right away and then on every tick until ctx is cancelled.
+// A panic or error in one run is logged and does not stop the job.
+func runEvery(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		runOnce(ctx, name, fn)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runOnce(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Log.Error("background job panicked", zap.String("job", name), zap.Any("panic", r))
+		}
+	}()
+
+	if err := fn(ctx); err != nil {
+		logger.Log.Error("background job failed", zap.String("job", name), zap.Error(err))
+	}
+}
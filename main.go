@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
+	"os/signal"
 	"sora_landing_be/cmd/routes"
+	"sora_landing_be/cmd/workers"
 	"sora_landing_be/pkg/authentication"
 	"sora_landing_be/pkg/config"
 	"sora_landing_be/pkg/database"
 	"sora_landing_be/pkg/http/server"
 	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/storage"
+	"syscall"
 
 	"go.uber.org/zap"
 )
@@ -18,6 +23,8 @@ func main() {
 
 	database.InitDB(cfg.Database)
 
+	storage.InitDriver(cfg)
+
 	authentication.NewJWTManager(authentication.JWTOptions{
 		AccessSecret:       cfg.Authentication.AccessSecretKey,
 		RefreshSecret:      cfg.Authentication.RefreshSecretKey,
@@ -31,14 +38,17 @@ func main() {
 	// Initialize the server
 	srv := server.Init(cfg.Application, routes.RegisterV1)
 
-	// Start listening for shutdown signals in a separate goroutine
-	go func() {
-		srv.GracefulShutdown()
-	}()
+	// Cancelled by a shutdown signal, the background jobs and the server stop with it
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Start background jobs, the service pool is ready once the routes are registered
+	jobs := workers.Start(ctx)
 
 	// Log that we're starting
 	logger.Log.Info("Server is running", zap.Int("port", cfg.Application.Port))
 
-	// Keep the main goroutine running
-	select {}
+	// Serve until a shutdown signal, then let the running jobs finish
+	srv.GracefulShutdown(ctx)
+	jobs.Wait()
 }
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS import_job_items;
+DROP TABLE IF EXISTS import_jobs;
+
+DROP INDEX IF EXISTS idx_blog_artikels_source;
+
+ALTER TABLE blog_artikels
+    DROP COLUMN IF EXISTS synced_at,
+    DROP COLUMN IF EXISTS source_hash;
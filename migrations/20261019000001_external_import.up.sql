@@ -0,0 +1,32 @@
+ALTER TABLE blog_artikels
+    ADD COLUMN source_hash VARCHAR(64),
+    ADD COLUMN synced_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_blog_artikels_source ON blog_artikels(source) WHERE source <> '-';
+
+CREATE TABLE import_jobs (
+    id VARCHAR(27) PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    created_by_id VARCHAR(27) NOT NULL,
+    status VARCHAR NOT NULL DEFAULT 'pending',
+    as_draft BOOLEAN NOT NULL DEFAULT false,
+    total INT NOT NULL DEFAULT 0,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (created_by_id) REFERENCES users(id)
+);
+
+CREATE TABLE import_job_items (
+    id VARCHAR(27) PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    job_id VARCHAR(27) NOT NULL REFERENCES import_jobs(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    status VARCHAR NOT NULL DEFAULT 'pending',
+    article_id VARCHAR(27) REFERENCES blog_artikels(id) ON DELETE SET NULL,
+    error TEXT
+);
+
+CREATE INDEX idx_import_job_items_job ON import_job_items(job_id);
@@ -0,0 +1,35 @@
+CREATE TABLE feed_subscriptions (
+    id VARCHAR(27) PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    url TEXT NOT NULL,
+    title VARCHAR,
+    category_id VARCHAR(27) REFERENCES categories(id) ON DELETE SET NULL,
+    tag_ids VARCHAR(27)[] NOT NULL DEFAULT '{}',
+    auto_publish BOOLEAN NOT NULL DEFAULT false,
+    active BOOLEAN NOT NULL DEFAULT true,
+    etag VARCHAR,
+    last_modified VARCHAR,
+    last_polled_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    created_by_id VARCHAR(27) NOT NULL REFERENCES users(id),
+    edited_by_id VARCHAR(27) REFERENCES users(id)
+);
+
+CREATE UNIQUE INDEX idx_feed_subscriptions_url ON feed_subscriptions(url) WHERE deleted_at IS NULL;
+
+CREATE TABLE feed_entries (
+    id VARCHAR(27) PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    subscription_id VARCHAR(27) NOT NULL REFERENCES feed_subscriptions(id) ON DELETE CASCADE,
+    guid TEXT NOT NULL,
+    url TEXT NOT NULL,
+    status VARCHAR NOT NULL,
+    article_id VARCHAR(27) REFERENCES blog_artikels(id) ON DELETE SET NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    error TEXT,
+    UNIQUE (subscription_id, guid)
+);
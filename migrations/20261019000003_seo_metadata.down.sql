@@ -0,0 +1,20 @@
+ALTER TABLE tags
+    DROP COLUMN IF EXISTS noindex,
+    DROP COLUMN IF EXISTS og_image,
+    DROP COLUMN IF EXISTS canonical_url,
+    DROP COLUMN IF EXISTS meta_description,
+    DROP COLUMN IF EXISTS meta_title;
+
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS noindex,
+    DROP COLUMN IF EXISTS og_image,
+    DROP COLUMN IF EXISTS canonical_url,
+    DROP COLUMN IF EXISTS meta_description,
+    DROP COLUMN IF EXISTS meta_title;
+
+ALTER TABLE blog_artikels
+    DROP COLUMN IF EXISTS noindex,
+    DROP COLUMN IF EXISTS og_image,
+    DROP COLUMN IF EXISTS canonical_url,
+    DROP COLUMN IF EXISTS meta_description,
+    DROP COLUMN IF EXISTS meta_title;
@@ -0,0 +1,32 @@
+ALTER TABLE blog_artikels
+    ADD COLUMN meta_title VARCHAR,
+    ADD COLUMN meta_description TEXT,
+    ADD COLUMN canonical_url TEXT,
+    ADD COLUMN og_image TEXT,
+    ADD COLUMN noindex BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE categories
+    ADD COLUMN meta_title VARCHAR,
+    ADD COLUMN meta_description TEXT,
+    ADD COLUMN canonical_url TEXT,
+    ADD COLUMN og_image TEXT,
+    ADD COLUMN noindex BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE tags
+    ADD COLUMN meta_title VARCHAR,
+    ADD COLUMN meta_description TEXT,
+    ADD COLUMN canonical_url TEXT,
+    ADD COLUMN og_image TEXT,
+    ADD COLUMN noindex BOOLEAN NOT NULL DEFAULT false;
+
+-- imported posts always point search engines at the original
+UPDATE blog_artikels
+SET canonical_url = source
+WHERE source <> '-' AND source <> '';
+
+-- give already published articles the same defaults new ones get at publish time
+UPDATE blog_artikels
+SET meta_title = LEFT(title, 60),
+    meta_description = NULLIF(LEFT(excerpt, 160), ''),
+    og_image = image_url
+WHERE status = 'published';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_article_tags_tag_id;
+DROP INDEX IF EXISTS users_slug_uindex;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,22 @@
+ALTER TABLE users
+    ADD COLUMN slug VARCHAR;
+
+-- derive slugs from names, suffixing duplicates in creation order
+WITH base AS (
+    SELECT id,
+           COALESCE(NULLIF(TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(name), '[^a-z0-9]+', '-', 'g')), ''), 'author') AS slug,
+           created_at
+    FROM users
+), numbered AS (
+    SELECT id, slug, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY created_at, id) AS n
+    FROM base
+)
+UPDATE users u
+SET slug = CASE WHEN numbered.n = 1 THEN numbered.slug ELSE numbered.slug || '-' || (numbered.n - 1) END
+FROM numbered
+WHERE numbered.id = u.id;
+
+CREATE UNIQUE INDEX users_slug_uindex ON users (slug);
+
+-- public listing filters look tags and categories up by slug
+CREATE INDEX IF NOT EXISTS idx_article_tags_tag_id ON article_tags (tag_id);
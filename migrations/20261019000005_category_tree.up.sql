@@ -0,0 +1,13 @@
+ALTER TABLE categories
+    ADD COLUMN parent_id VARCHAR(27) REFERENCES categories(id),
+    ADD COLUMN path VARCHAR;
+
+-- path is the slash separated chain of slugs from the root, existing categories become roots
+UPDATE categories
+SET path = slug;
+
+ALTER TABLE categories
+    ALTER COLUMN path SET NOT NULL;
+
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
+CREATE INDEX idx_categories_path ON categories (path varchar_pattern_ops);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tag_redirects;
+DROP TABLE IF EXISTS tag_synonyms;
@@ -0,0 +1,26 @@
+-- alternative names that resolve to a canonical tag, e.g. "kompas.com" for "Kompas"
+CREATE TABLE tag_synonyms (
+    id VARCHAR(27) PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    tag_id VARCHAR(27) NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    normalized VARCHAR NOT NULL,
+    created_by_id VARCHAR(27) REFERENCES users(id)
+);
+
+CREATE UNIQUE INDEX idx_tag_synonyms_normalized ON tag_synonyms(normalized) WHERE deleted_at IS NULL;
+CREATE INDEX idx_tag_synonyms_tag_id ON tag_synonyms(tag_id);
+
+-- slugs of merged or renamed tags, so old links can be redirected
+CREATE TABLE tag_redirects (
+    id VARCHAR(27) PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    old_slug VARCHAR NOT NULL,
+    tag_id VARCHAR(27) NOT NULL REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_tag_redirects_old_slug ON tag_redirects(old_slug) WHERE deleted_at IS NULL;
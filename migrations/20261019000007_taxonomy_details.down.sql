@@ -0,0 +1,7 @@
+ALTER TABLE tags
+    DROP COLUMN IF EXISTS image_url,
+    DROP COLUMN IF EXISTS description;
+
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS image_url,
+    DROP COLUMN IF EXISTS description;
@@ -0,0 +1,7 @@
+ALTER TABLE categories
+    ADD COLUMN description TEXT,
+    ADD COLUMN image_url VARCHAR;
+
+ALTER TABLE tags
+    ADD COLUMN description TEXT,
+    ADD COLUMN image_url VARCHAR;
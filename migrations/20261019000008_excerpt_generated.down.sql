@@ -0,0 +1,2 @@
+ALTER TABLE blog_artikels
+    DROP COLUMN IF EXISTS excerpt_generated;
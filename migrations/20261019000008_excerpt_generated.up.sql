@@ -0,0 +1,2 @@
+ALTER TABLE blog_artikels
+    ADD COLUMN excerpt_generated BOOLEAN NOT NULL DEFAULT FALSE;
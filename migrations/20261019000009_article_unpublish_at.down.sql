@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_blog_artikels_unpublish_at;
+
+ALTER TABLE blog_artikels
+    DROP COLUMN IF EXISTS unpublish_at;
@@ -0,0 +1,6 @@
+ALTER TABLE blog_artikels
+    ADD COLUMN unpublish_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_blog_artikels_unpublish_at
+    ON blog_artikels (unpublish_at)
+    WHERE unpublish_at IS NOT NULL AND deleted_at IS NULL;
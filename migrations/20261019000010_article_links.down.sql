@@ -0,0 +1,4 @@
+ALTER TABLE blog_artikels
+    DROP COLUMN IF EXISTS links_checked_at;
+
+DROP TABLE IF EXISTS article_links;
@@ -0,0 +1,22 @@
+-- links and images found in articles with the result of their last check
+CREATE TABLE article_links (
+    id VARCHAR(27) PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    article_id VARCHAR(27) NOT NULL REFERENCES blog_artikels(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    kind VARCHAR NOT NULL,
+    internal BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR NOT NULL,
+    status_code INT,
+    final_url TEXT,
+    error TEXT,
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_article_links_article_id ON article_links(article_id);
+CREATE INDEX idx_article_links_status ON article_links(status) WHERE status <> 'ok' AND deleted_at IS NULL;
+
+ALTER TABLE blog_artikels
+    ADD COLUMN links_checked_at TIMESTAMP WITH TIME ZONE;
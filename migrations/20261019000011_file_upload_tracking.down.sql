@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS article_files;
+
+DROP INDEX IF EXISTS idx_file_uploads_content_hash;
+DROP INDEX IF EXISTS idx_file_uploads_file_path;
+
+ALTER TABLE file_uploads
+    DROP COLUMN IF EXISTS content_hash;
@@ -0,0 +1,15 @@
+ALTER TABLE file_uploads
+    ADD COLUMN content_hash VARCHAR(64);
+
+CREATE UNIQUE INDEX idx_file_uploads_file_path ON file_uploads(file_path) WHERE deleted_at IS NULL;
+CREATE INDEX idx_file_uploads_content_hash ON file_uploads(content_hash) WHERE content_hash IS NOT NULL;
+
+-- uploads shown by an article, as cover or inside the content
+CREATE TABLE article_files (
+    article_id VARCHAR(27) NOT NULL REFERENCES blog_artikels(id) ON DELETE CASCADE,
+    file_id VARCHAR(27) NOT NULL REFERENCES file_uploads(id) ON DELETE CASCADE,
+    usage VARCHAR(20) NOT NULL,
+    PRIMARY KEY (article_id, file_id, usage)
+);
+
+CREATE INDEX idx_article_files_file_id ON article_files(file_id);
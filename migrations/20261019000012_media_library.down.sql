@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_file_uploads_created_at;
+
+ALTER TABLE file_uploads
+    DROP COLUMN IF EXISTS caption,
+    DROP COLUMN IF EXISTS alt_text,
+    DROP COLUMN IF EXISTS title;
@@ -0,0 +1,6 @@
+ALTER TABLE file_uploads
+    ADD COLUMN title VARCHAR(255),
+    ADD COLUMN alt_text VARCHAR(500),
+    ADD COLUMN caption TEXT;
+
+CREATE INDEX idx_file_uploads_created_at ON file_uploads(created_at DESC) WHERE deleted_at IS NULL;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS file_variants;
+
+ALTER TABLE file_uploads
+    DROP COLUMN IF EXISTS height,
+    DROP COLUMN IF EXISTS width;
@@ -0,0 +1,17 @@
+ALTER TABLE file_uploads
+    ADD COLUMN width INTEGER,
+    ADD COLUMN height INTEGER;
+
+-- resized copies of uploaded images, one row per size and format
+CREATE TABLE file_variants (
+    file_id VARCHAR(27) NOT NULL REFERENCES file_uploads(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    file_path TEXT NOT NULL,
+    file_size BIGINT NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (file_id, name, format)
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_file_uploads_temporary;
+
+ALTER TABLE file_uploads
+    DROP COLUMN IF EXISTS is_temporary;
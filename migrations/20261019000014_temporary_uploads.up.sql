@@ -0,0 +1,6 @@
+-- uploads stay temporary until saved content references them, the sweeper removes
+-- the ones abandoned for longer than storage.temporary_ttl
+ALTER TABLE file_uploads
+    ADD COLUMN is_temporary BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_file_uploads_temporary ON file_uploads(created_at) WHERE is_temporary;
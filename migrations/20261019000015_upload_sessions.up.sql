@@ -0,0 +1,21 @@
+-- resumable (tus) uploads in progress, their chunks are stored as hidden parts
+-- until the last one arrives and they are joined into the file
+CREATE TABLE upload_sessions (
+    id VARCHAR(27) PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    file_key TEXT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    upload_length BIGINT NOT NULL,
+    upload_offset BIGINT NOT NULL DEFAULT 0,
+    parts TEXT[] NOT NULL DEFAULT '{}',
+    metadata TEXT,
+    module VARCHAR(50),
+    reference_id VARCHAR(27),
+    is_public BOOLEAN NOT NULL DEFAULT false,
+    uploaded_by VARCHAR(27) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    file_id VARCHAR(27) REFERENCES file_uploads(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_upload_sessions_updated_at ON upload_sessions(updated_at);
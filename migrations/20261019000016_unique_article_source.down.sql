@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_blog_artikels_source;
+
+CREATE INDEX idx_blog_artikels_source ON blog_artikels(source) WHERE source <> '-';
@@ -0,0 +1,17 @@
+-- two imports of the same URL can both pass the duplicate lookup, the index lets only one insert through
+DROP INDEX IF EXISTS idx_blog_artikels_source;
+
+-- imports used to store the URL without any duplicate check, so live articles may already share
+-- a source. The oldest one stays the import of the URL, the later copies keep their content but
+-- get the "-" default back and stop being re-synced, otherwise the index below cannot be built.
+UPDATE blog_artikels ba
+SET source = '-', source_hash = NULL, synced_at = NULL, updated_at = NOW()
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY source ORDER BY created_at ASC, id ASC) AS n
+    FROM blog_artikels
+    WHERE source <> '' AND source <> '-' AND deleted_at IS NULL
+) dup
+WHERE ba.id = dup.id AND dup.n > 1;
+
+CREATE UNIQUE INDEX idx_blog_artikels_source ON blog_artikels(source)
+    WHERE source <> '' AND source <> '-' AND deleted_at IS NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_upload_sessions_direct_file_key;
+
+ALTER TABLE upload_sessions
+    DROP COLUMN IF EXISTS direct;
@@ -0,0 +1,6 @@
+-- presigned uploads go straight to the bucket, a session records each one so the sweep
+-- removes what a client sent without ever completing the upload
+ALTER TABLE upload_sessions
+    ADD COLUMN direct BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX idx_upload_sessions_direct_file_key ON upload_sessions(file_key) WHERE direct;
@@ -0,0 +1,4 @@
+ALTER TABLE blog_artikels
+    DROP COLUMN IF EXISTS meta_title_generated,
+    DROP COLUMN IF EXISTS meta_description_generated,
+    DROP COLUMN IF EXISTS og_image_generated;
@@ -0,0 +1,18 @@
+ALTER TABLE blog_artikels
+    ADD COLUMN meta_title_generated BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN meta_description_generated BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN og_image_generated BOOLEAN NOT NULL DEFAULT false;
+
+-- values the seo backfill or publishing filled in from the article are marked generated so they
+-- follow it from now on. A value counts as generated when it is the start of the title or the
+-- excerpt, cut with an ellipsis or not, and an Open Graph image when it is the cover.
+UPDATE blog_artikels
+SET meta_title_generated = meta_title IS NOT NULL AND (
+        starts_with(title, meta_title)
+        OR (right(meta_title, 1) = '…' AND starts_with(title, left(meta_title, -1)))
+    ),
+    meta_description_generated = meta_description IS NOT NULL AND COALESCE(excerpt, '') <> '' AND (
+        starts_with(excerpt, meta_description)
+        OR (right(meta_description, 1) = '…' AND starts_with(excerpt, left(meta_description, -1)))
+    ),
+    og_image_generated = og_image IS NOT NULL AND og_image = image_url;
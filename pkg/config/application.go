@@ -3,4 +3,11 @@ package config
 type Application struct {
 	Port        int                    `yaml:"port"`
 	Environment ApplicationEnvironment `yaml:"environment"`
+	// BaseURL is the public address of this API, used to build absolute links to uploaded files
+	BaseURL string `yaml:"base_url" mapstructure:"base_url"`
+	// FrontendURL is the public address of the website, used for canonical links and structured data
+	FrontendURL string `yaml:"frontend_url" mapstructure:"frontend_url"`
+	SiteName    string `yaml:"site_name" mapstructure:"site_name"`
+	// ExcerptLength is the maximum length, in characters, of excerpts generated from article content
+	ExcerptLength int `yaml:"excerpt_length" mapstructure:"excerpt_length"`
 }
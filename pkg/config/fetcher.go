@@ -0,0 +1,14 @@
+package config
+
+import "time"
+
+// Fetcher restricts outbound requests made on behalf of users, such as URL imports
+type Fetcher struct {
+	Timeout         time.Duration `mapstructure:"timeout"`
+	MaxResponseSize int64         `mapstructure:"max_response_size"` // in bytes
+	MaxRedirects    int           `mapstructure:"max_redirects"`
+	AllowedSchemes  []string      `mapstructure:"allowed_schemes"`
+	AllowedPorts    []int         `mapstructure:"allowed_ports"`
+	AllowDomains    []string      `mapstructure:"allow_domains"` // when set, only these domains (and subdomains) may be fetched
+	DenyDomains     []string      `mapstructure:"deny_domains"`
+}
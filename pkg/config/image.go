@@ -0,0 +1,13 @@
+package config
+
+// Image configures the resized copies generated for uploaded images
+type Image struct {
+	Variants    []ImageVariant `mapstructure:"variants"`
+	WebPQuality int            `mapstructure:"webp_quality"` // 1-100
+}
+
+// ImageVariant is a named width images are scaled down to, keeping their aspect ratio
+type ImageVariant struct {
+	Name  string `mapstructure:"name"`
+	Width int    `mapstructure:"width"`
+}
@@ -18,6 +18,9 @@ type Config struct {
 	Database       Database       `yaml:"database"`
 	Logger         Logger         `yaml:"logger"`
 	ObjectStorage  ObjectStorage  `yaml:"object_storage"`
+	Fetcher        Fetcher        `yaml:"fetcher"`
+	Image          Image          `yaml:"image"`
+	Storage        Storage        `yaml:"storage"`
 }
 
 var once sync.Once
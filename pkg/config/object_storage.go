@@ -9,5 +9,16 @@ type ObjectStorage struct {
 	SecretKey         string        `mapstructure:"secret_key"`
 	UseSSL            bool          `mapstructure:"use_ssl"`
 	PresignExpiration time.Duration `mapstructure:"presign_expiration"`
-	MaxFileSize       int64         `mapstructure:"max_file_size"`
+	MaxFileSize       int64         `mapstructure:"max_file_size"` // MB
+}
+
+// defaultMaxFileSize is the upload limit in MB when max_file_size is not configured
+const defaultMaxFileSize = 10
+
+// MaxFileSizeBytes is the largest file accepted, whether uploaded through the API or to the bucket
+func (o ObjectStorage) MaxFileSizeBytes() int64 {
+	if o.MaxFileSize <= 0 {
+		return defaultMaxFileSize << 20
+	}
+	return o.MaxFileSize << 20
 }
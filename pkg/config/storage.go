@@ -0,0 +1,27 @@
+package config
+
+import "time"
+
+// Storage selects where uploaded files are kept
+type Storage struct {
+	Driver   string `mapstructure:"driver"`    // local (default) or s3, s3 uses the object_storage settings
+	LocalDir string `mapstructure:"local_dir"` // directory of the local driver, uploads by default
+	// PublicURL is the address files are served from, such as a CDN in front of the bucket.
+	// Local files default to the /uploads route of this API, s3 to the bucket on its endpoint.
+	PublicURL string `mapstructure:"public_url"`
+	// TemporaryTTL is how long an upload no saved content references is kept, 24h by default
+	TemporaryTTL time.Duration `mapstructure:"temporary_ttl"`
+	// ResumableMaxSize limits resumable uploads, which also take videos and PDFs, in MB
+	ResumableMaxSize int64 `mapstructure:"resumable_max_size"`
+}
+
+// defaultResumableMaxSize is the resumable upload limit in MB when resumable_max_size is not configured
+const defaultResumableMaxSize = 1024
+
+// ResumableMaxSizeBytes is the largest file accepted through a resumable upload
+func (s Storage) ResumableMaxSizeBytes() int64 {
+	if s.ResumableMaxSize <= 0 {
+		return defaultResumableMaxSize << 20
+	}
+	return s.ResumableMaxSize << 20
+}
@@ -8,11 +8,21 @@ import (
 )
 
 const (
-	DataNotFound          = "Data not found"
-	DataAlreadyExist      = "Data already exist"
-	ErrFeaturedSlotFull   = "featured slot is already occupied"
-	ErrMaxFeaturedReached = "maximum 3 featured articles reached"
-	ErrInvalidPosition    = "invalid featured position, must be 1, 2, or 3"
+	DataNotFound           = "Data not found"
+	DataAlreadyExist       = "Data already exist"
+	ErrFeaturedSlotFull    = "featured slot is already occupied"
+	ErrMaxFeaturedReached  = "maximum 3 featured articles reached"
+	ErrInvalidPosition     = "invalid featured position, must be 1, 2, or 3"
+	ErrCategoryCycle       = "category cannot be moved under itself or one of its subcategories"
+	ErrCategoryHasChildren = "category still has subcategories, move or delete them first"
+	ErrUnpublishBeforeLive = "unpublish_at must be later than the time the article goes live"
+	ErrFileInUse           = "file is still used by an article, category or tag, remove it from there first"
+	ErrDirectUploadOff     = "direct uploads need the s3 storage driver"
+	ErrUploadNotFound      = "nothing was uploaded under this key, or the upload expired"
+	ErrUploadNotOwned      = "the upload was started by another user"
+	ErrUploadOffset        = "Upload-Offset does not match the bytes received so far"
+	ErrUploadPastLength    = "the chunk goes past Upload-Length"
+	ErrUploadTooLarge      = "the file is larger than resumable uploads allow"
 )
 
 func CheckUniqueViolation(err error) error {
@@ -24,3 +34,9 @@ func CheckUniqueViolation(err error) error {
 
 	return err
 }
+
+// IsUniqueViolation reports whether err is a unique violation of the named constraint or index
+func IsUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
@@ -0,0 +1,219 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+var ErrUnknownFormat = errors.New("document is not an RSS or Atom feed")
+
+// ContentTypes lists the media types feeds are commonly served with
+var ContentTypes = []string{
+	"application/rss+xml",
+	"application/atom+xml",
+	"application/rdf+xml",
+	"application/xml",
+	"text/xml",
+	"text/plain",
+}
+
+type Feed struct {
+	Title string
+	Link  string
+	Items []Item
+}
+
+type Item struct {
+	// GUID identifies the entry within its feed, falls back to the link
+	GUID      string
+	Title     string
+	Link      string
+	Published time.Time
+}
+
+type rssDocument struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Links []string  `xml:"link"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	// RSS 1.0 (RDF) keeps items next to the channel instead of inside it
+	Items []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	GUID    string   `xml:"guid"`
+	Title   string   `xml:"title"`
+	Links   []string `xml:"link"`
+	PubDate string   `xml:"pubDate"`
+	Date    string   `xml:"date"`
+}
+
+type atomDocument struct {
+	Title   string      `xml:"title"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Links     []atomLink `xml:"link"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+// Parse reads an RSS 2.0, RSS 1.0 or Atom document
+func Parse(data []byte) (*Feed, error) {
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(root) {
+	case "rss", "rdf":
+		var doc rssDocument
+		if err := decode(data, &doc); err != nil {
+			return nil, err
+		}
+		return doc.toFeed(), nil
+	case "feed":
+		var doc atomDocument
+		if err := decode(data, &doc); err != nil {
+			return nil, err
+		}
+		return doc.toFeed(), nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+func (d rssDocument) toFeed() *Feed {
+	res := &Feed{
+		Title: strings.TrimSpace(d.Channel.Title),
+		Link:  firstNonEmpty(d.Channel.Links...),
+	}
+
+	items := d.Channel.Items
+	if len(items) == 0 {
+		items = d.Items
+	}
+	for _, item := range items {
+		link := firstNonEmpty(item.Links...)
+		guid := strings.TrimSpace(item.GUID)
+		if guid == "" {
+			guid = link
+		}
+		if guid == "" {
+			continue
+		}
+		res.Items = append(res.Items, Item{
+			GUID:      guid,
+			Title:     strings.TrimSpace(item.Title),
+			Link:      link,
+			Published: parseDate(firstNonEmpty(item.PubDate, item.Date)),
+		})
+	}
+	return res
+}
+
+func (d atomDocument) toFeed() *Feed {
+	res := &Feed{
+		Title: strings.TrimSpace(d.Title),
+		Link:  alternateLink(d.Links),
+	}
+
+	for _, entry := range d.Entries {
+		link := alternateLink(entry.Links)
+		guid := strings.TrimSpace(entry.ID)
+		if guid == "" {
+			guid = link
+		}
+		if guid == "" {
+			continue
+		}
+		res.Items = append(res.Items, Item{
+			GUID:      guid,
+			Title:     strings.TrimSpace(entry.Title),
+			Link:      link,
+			Published: parseDate(firstNonEmpty(entry.Published, entry.Updated)),
+		})
+	}
+	return res
+}
+
+func rootElement(data []byte) (string, error) {
+	decoder := newDecoder(data)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", ErrUnknownFormat
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func decode(data []byte, v any) error {
+	return newDecoder(data).Decode(v)
+}
+
+func newDecoder(data []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = charset.NewReaderLabel
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	return decoder
+}
+
+// alternateLink picks the link pointing at the HTML version of a feed or entry
+func alternateLink(links []atomLink) string {
+	for _, link := range links {
+		if link.Href != "" && (link.Rel == "" || link.Rel == "alternate") {
+			return strings.TrimSpace(link.Href)
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	time.RFC822Z,
+	time.RFC822,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+func parseDate(value string) time.Time {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
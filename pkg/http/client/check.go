@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// CheckResult is the outcome of probing a url without downloading it
+type CheckResult struct {
+	// URL is the final url after redirects
+	URL        string
+	StatusCode int
+}
+
+// Check probes a url with a HEAD request, retrying with GET when the server refuses HEAD.
+// The body is never read.
+// Returns:
+//   - result : final url after redirects and status, non 2xx statuses are not errors
+//   - err : ErrBlockedDestination when the guard rejects the url, or a transport error
+func (c *HTTPClient) Check(ctx context.Context, rawURL string) (*CheckResult, error) {
+	res, err := c.probe(ctx, http.MethodHead, rawURL)
+	if errors.Is(err, ErrBlockedDestination) || ctx.Err() != nil {
+		return nil, err
+	}
+	if err == nil {
+		switch res.StatusCode {
+		case http.StatusMethodNotAllowed, http.StatusNotImplemented, http.StatusForbidden:
+		default:
+			return res, nil
+		}
+	}
+	return c.probe(ctx, http.MethodGet, rawURL)
+}
+
+func (c *HTTPClient) probe(ctx context.Context, method, rawURL string) (*CheckResult, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	if c.guard != nil {
+		if err := c.guard.CheckURL(req.URL); err != nil {
+			return nil, err
+		}
+	}
+	req.Header.Set("User-Agent", DefaultUserAgent)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return &CheckResult{
+		URL:        resp.Request.URL.String(),
+		StatusCode: resp.StatusCode,
+	}, nil
+}
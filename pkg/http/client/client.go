@@ -16,6 +16,10 @@ import (
 
 type HTTPClient struct {
 	Client *http.Client
+
+	// guard and maxBytes are only set on clients built by NewSafeClient
+	guard    *Guard
+	maxBytes int64
 }
 
 var (
@@ -78,7 +82,7 @@ func (c *HTTPClient) Call(urls string, response interface{}, attributes RequestA
 	if attributes.Body != nil {
 		reqBody, err = json.Marshal(attributes.Body)
 		if err != nil {
-			return NewHTTPClientError(0, fmt.Sprintf("error marshaling body: %w", err))
+			return NewHTTPClientError(0, fmt.Sprintf("error marshaling body: %v", err))
 		}
 	}
 
@@ -119,7 +123,7 @@ func (c *HTTPClient) Call(urls string, response interface{}, attributes RequestA
 
 	if response != nil {
 		if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
-			return NewHTTPClientError(resp.StatusCode, fmt.Sprintf("error decoding response: %w", err))
+			return NewHTTPClientError(resp.StatusCode, fmt.Sprintf("error decoding response: %v", err))
 		}
 	}
 
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const (
+	DefaultFetchMaxBytes = 10 * 1024 * 1024
+	DefaultUserAgent     = "Mozilla/5.0 (compatible; SoraBot/1.0)"
+)
+
+// FetchOptions controls how a remote resource is downloaded
+type FetchOptions struct {
+	// ContentTypes lists accepted media type prefixes, e.g. "text/html" or "image/".
+	// Empty means any content type is accepted.
+	ContentTypes []string
+	// MaxBytes caps the response body size, defaults to the client limit or DefaultFetchMaxBytes.
+	// It can never raise the limit configured on a safe client.
+	MaxBytes int64
+	Headers  map[string]string
+}
+
+type FetchResult struct {
+	URL         string
+	StatusCode  int
+	ContentType string
+	Header      http.Header
+	Body        []byte
+}
+
+// Fetch downloads a remote resource with a GET request.
+// Parameters:
+//   - rawURL: absolute http(s) url to download
+//   - opts: accepted content types, size cap and extra headers
+//
+// Returns:
+//   - result : final url after redirects, status, content type and body
+//   - err : ErrBlockedDestination when the guard rejects the url, HTTPClientError for
+//     non 2xx responses, rejected content types or oversized bodies
+func (c *HTTPClient) Fetch(ctx context.Context, rawURL string, opts FetchOptions) (*FetchResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	if c.guard != nil {
+		if err := c.guard.CheckURL(req.URL); err != nil {
+			return nil, err
+		}
+	}
+	req.Header.Set("User-Agent", DefaultUserAgent)
+	for key, value := range opts.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	res := &FetchResult{
+		URL:        resp.Request.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		return res, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return res, NewHTTPClientError(resp.StatusCode, fmt.Sprintf("unexpected status code: %d", resp.StatusCode))
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	res.ContentType = mediaType
+	if !acceptsContentType(opts.ContentTypes, mediaType) {
+		return res, NewHTTPClientError(resp.StatusCode, fmt.Sprintf("unexpected content type: %q", mediaType))
+	}
+
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 || (c.maxBytes > 0 && maxBytes > c.maxBytes) {
+		maxBytes = c.maxBytes
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultFetchMaxBytes
+	}
+	if resp.ContentLength > maxBytes {
+		return res, NewHTTPClientError(resp.StatusCode, fmt.Sprintf("response too large: %d bytes", resp.ContentLength))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return res, fmt.Errorf("error reading response body: %w", err)
+	}
+	if int64(len(body)) > maxBytes {
+		return res, NewHTTPClientError(resp.StatusCode, fmt.Sprintf("response exceeds %d bytes", maxBytes))
+	}
+	res.Body = body
+
+	return res, nil
+}
+
+func acceptsContentType(allowed []string, mediaType string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, prefix := range allowed {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,252 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sora_landing_be/pkg/config"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrBlockedDestination = errors.New("destination is not allowed")
+	ErrTooManyRedirects   = errors.New("too many redirects")
+
+	safeInstance *HTTPClient
+	safeOnce     = &sync.Once{}
+)
+
+// blockedPrefixes lists ranges that are not covered by the netip helpers used in isPublicIP
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, includes broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local-use NAT64
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("100::/64"),        // discard-only
+	netip.MustParsePrefix("2002::/16"),       // 6to4 can embed private IPv4 addresses
+	netip.MustParsePrefix("fec0::/10"),       // deprecated site-local
+	netip.MustParsePrefix("169.254.0.0/16"),  // link-local, incl. cloud metadata
+	netip.MustParsePrefix("fd00:ec2::/32"),   // AWS IPv6 metadata
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay anycast
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+}
+
+// Guard validates outbound destinations to protect against SSRF. URLs are
+// checked before each request and redirect, and the resolved IP address is
+// checked again when dialing so DNS tricks cannot reach internal hosts.
+type Guard struct {
+	schemes      []string
+	ports        []int
+	allowDomains []string
+	denyDomains  []string
+}
+
+func NewGuard(cfg config.Fetcher) *Guard {
+	g := &Guard{
+		schemes:      lowerAll(cfg.AllowedSchemes),
+		ports:        cfg.AllowedPorts,
+		allowDomains: normalizeDomains(cfg.AllowDomains),
+		denyDomains:  normalizeDomains(cfg.DenyDomains),
+	}
+	if len(g.schemes) == 0 {
+		g.schemes = []string{"http", "https"}
+	}
+	if len(g.ports) == 0 {
+		g.ports = []int{80, 443}
+	}
+	return g
+}
+
+// GetSafeClient returns the shared client used for fetching user supplied URLs
+func GetSafeClient() *HTTPClient {
+	safeOnce.Do(func() {
+		safeInstance = NewSafeClient(config.LoadConfig().Fetcher)
+	})
+	return safeInstance
+}
+
+// NewSafeClient builds a client that only talks to public addresses allowed by cfg
+func NewSafeClient(cfg config.Fetcher) *HTTPClient {
+	guard := NewGuard(cfg)
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	maxRedirects := cfg.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = 5
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   guard.control,
+	}
+
+	transport := &http.Transport{
+		Proxy:                 nil, // a proxy would hide the real destination from the dial check
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          20,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: timeout,
+	}
+
+	return &HTTPClient{
+		Client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) > maxRedirects {
+					return ErrTooManyRedirects
+				}
+				return guard.CheckURL(req.URL)
+			},
+		},
+		guard:    guard,
+		maxBytes: cfg.MaxResponseSize,
+	}
+}
+
+// CheckURL validates scheme, port and domain lists, and rejects literal IPs
+// outside the public address space
+func (g *Guard) CheckURL(u *url.URL) error {
+	if u == nil {
+		return ErrBlockedDestination
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if !containsString(g.schemes, scheme) {
+		return fmt.Errorf("%w: scheme %q", ErrBlockedDestination, u.Scheme)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return fmt.Errorf("%w: missing host", ErrBlockedDestination)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[scheme]
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil || !containsInt(g.ports, portNum) {
+		return fmt.Errorf("%w: port %q", ErrBlockedDestination, port)
+	}
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return fmt.Errorf("%w: host %q", ErrBlockedDestination, host)
+	}
+	if matchesDomain(g.denyDomains, host) {
+		return fmt.Errorf("%w: domain %q is denied", ErrBlockedDestination, host)
+	}
+	if len(g.allowDomains) > 0 && !matchesDomain(g.allowDomains, host) {
+		return fmt.Errorf("%w: domain %q is not allowed", ErrBlockedDestination, host)
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil && !isPublicIP(addr) {
+		return fmt.Errorf("%w: address %s", ErrBlockedDestination, addr)
+	}
+
+	return nil
+}
+
+// control runs after DNS resolution, right before the socket connects
+func (g *Guard) control(network, address string, _ syscall.RawConn) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedDestination, address)
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !isPublicIP(addr) {
+		return fmt.Errorf("%w: address %s", ErrBlockedDestination, host)
+	}
+
+	portNum, err := strconv.Atoi(port)
+	if err != nil || !containsInt(g.ports, portNum) {
+		return fmt.Errorf("%w: port %s", ErrBlockedDestination, port)
+	}
+	return nil
+}
+
+func isPublicIP(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesDomain(domains []string, host string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeDomains(domains []string) []string {
+	res := make([]string, 0, len(domains))
+	for _, d := range domains {
+		d = strings.Trim(strings.ToLower(strings.TrimSpace(d)), ".")
+		if d != "" {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+func lowerAll(values []string) []string {
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		res = append(res, strings.ToLower(strings.TrimSpace(v)))
+	}
+	return res
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package http
+
+import "strings"
+
+// linkPreviewAgents are user agent fragments of crawlers that build link previews
+// or index pages without running JavaScript
+var linkPreviewAgents = []string{
+	"facebookexternalhit",
+	"facebookcatalog",
+	"facebot",
+	"twitterbot",
+	"linkedinbot",
+	"whatsapp",
+	"slackbot",
+	"slack-imgproxy",
+	"telegrambot",
+	"discordbot",
+	"pinterest",
+	"skypeuripreview",
+	"redditbot",
+	"applebot",
+	"googlebot",
+	"bingbot",
+	"yandex",
+	"duckduckbot",
+	"embedly",
+	"iframely",
+	"vkshare",
+	"tumblr",
+	"mastodon",
+	"bitlybot",
+	"quora link preview",
+	"line-poker",
+	"kakaotalk-scrap",
+	"snapchat",
+	"google-inspectiontool",
+}
+
+// IsLinkPreviewBot reports whether the user agent belongs to a crawler that reads meta tags
+func IsLinkPreviewBot(userAgent string) bool {
+	ua := strings.ToLower(userAgent)
+	if ua == "" {
+		return false
+	}
+	for _, agent := range linkPreviewAgents {
+		if strings.Contains(ua, agent) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,14 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"mime"
 	"net/http"
-	"os"
-	"os/signal"
+	"path"
 	"sora_landing_be/pkg/config"
 	"sora_landing_be/pkg/http/server/middlewares"
 	"sora_landing_be/pkg/logger"
+	"sora_landing_be/pkg/storage"
 	"sora_landing_be/pkg/validation"
-	"syscall"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/gzip"
@@ -31,7 +32,7 @@ func Init(config config.Application, routes ...RegisterRoute) *HTTPServer {
 	router.Use(middlewares.LoggerMiddleware())
 	router.Use(middlewares.ErrorMiddleware())
 	router.NoRoute(middlewares.NotFoundHandler)
-	router.Static("/uploads", "./uploads")
+	registerUploads(router)
 	//init router
 	for _, route := range routes {
 		route(router)
@@ -54,11 +55,41 @@ func Init(config config.Application, routes ...RegisterRoute) *HTTPServer {
 	}
 }
 
-func (h *HTTPServer) GracefulShutdown() {
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+// registerUploads serves uploaded files, from disk for the local driver and otherwise by
+// redirecting to the storage, so links to /uploads keep working whichever driver is used
+func registerUploads(router *gin.Engine) {
+	driver := storage.GetDriver()
+	if local, ok := driver.(*storage.LocalDriver); ok {
+		router.Group("/"+storage.LocalUploadDir, uploadHeaders).Static("/", local.Dir())
+		return
+	}
+	router.GET("/"+storage.LocalUploadDir+"/*key", func(c *gin.Context) {
+		c.Redirect(http.StatusFound, driver.URL(path.Base(c.Param("key"))))
+	})
+}
+
+// uploadHeaders keeps browsers from running uploaded files, such as an SVG opened directly,
+// as a page of this site. Only documents that can script are sandboxed, a sandboxed PDF or
+// video would not open in the browser's own viewer.
+func uploadHeaders(c *gin.Context) {
+	policy := "default-src 'none'; img-src 'self' data:; style-src 'unsafe-inline'"
+	if isScriptableType(mime.TypeByExtension(path.Ext(c.Request.URL.Path))) {
+		policy += "; sandbox"
+	}
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", policy)
+	c.Next()
+}
+
+// isScriptableType reports whether a browser renders contentType as a document able to run scripts
+func isScriptableType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.Contains(mediaType, "svg") || strings.Contains(mediaType, "html") || strings.HasSuffix(mediaType, "xml")
+}
 
-	<-stop
+// GracefulShutdown waits for ctx to be cancelled, by a shutdown signal, and then stops the server
+func (h *HTTPServer) GracefulShutdown(ctx context.Context) {
+	<-ctx.Done()
 
 	logger.Log.Info("Shutting down gracefully...")
 
@@ -0,0 +1,123 @@
+package keywords
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MinTokenLength drops tokens too short to carry meaning, two letters keeps acronyms such as "ai"
+const MinTokenLength = 2
+
+// Term is a unigram or a bigram ("machine learning") with its TF-IDF score in a document
+type Term struct {
+	Text  string
+	Score float64
+}
+
+// Tokenize lowercases text and splits it into words, stop words, numbers and punctuation
+// come back as empty strings so callers can tell phrases apart
+func Tokenize(text string) []string {
+	var (
+		res  []string
+		word strings.Builder
+	)
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		f := word.String()
+		word.Reset()
+		if len([]rune(f)) < MinTokenLength || isNumber(f) || IsStopWord(f) {
+			f = ""
+		}
+		res = append(res, f)
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(r)
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			// "ai. golang" is two phrases, not one
+			flush()
+			res = append(res, "")
+		}
+	}
+	flush()
+	return res
+}
+
+// Terms returns the unigrams and bigrams of a tokenized document with their frequency,
+// bigrams never span a stop word
+func Terms(tokens []string) map[string]int {
+	res := make(map[string]int)
+	for i, t := range tokens {
+		if t == "" {
+			continue
+		}
+		res[t]++
+		if i+1 < len(tokens) && tokens[i+1] != "" {
+			res[t+" "+tokens[i+1]]++
+		}
+	}
+	return res
+}
+
+// Corpus holds document frequencies, it is built once and shared between requests
+type Corpus struct {
+	docs int
+	df   map[string]int
+}
+
+func NewCorpus() *Corpus {
+	return &Corpus{df: make(map[string]int)}
+}
+
+// Add counts a document, terms being the output of Terms
+func (c *Corpus) Add(terms map[string]int) {
+	c.docs++
+	for t := range terms {
+		c.df[t]++
+	}
+}
+
+func (c *Corpus) Size() int {
+	return c.docs
+}
+
+// Score ranks the terms of a document by TF-IDF, highest first
+func (c *Corpus) Score(terms map[string]int) []Term {
+	total := 0
+	for _, n := range terms {
+		total += n
+	}
+	if total == 0 {
+		return nil
+	}
+
+	res := make([]Term, 0, len(terms))
+	for t, n := range terms {
+		// smoothed idf, a term unknown to the corpus scores as if it appeared once
+		idf := math.Log(float64(c.docs+1)/float64(c.df[t]+1)) + 1
+		res = append(res, Term{Text: t, Score: float64(n) / float64(total) * idf})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].Text < res[j].Text
+	})
+	return res
+}
+
+func isNumber(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
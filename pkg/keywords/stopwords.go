@@ -0,0 +1,48 @@
+package keywords
+
+// IsStopWord reports whether word, already lowercased, is an Indonesian or English stop word
+func IsStopWord(word string) bool {
+	_, ok := stopWords[word]
+	return ok
+}
+
+var stopWords = func() map[string]struct{} {
+	res := make(map[string]struct{}, len(indonesianStopWords)+len(englishStopWords))
+	for _, list := range [][]string{indonesianStopWords, englishStopWords} {
+		for _, w := range list {
+			res[w] = struct{}{}
+		}
+	}
+	return res
+}()
+
+var indonesianStopWords = []string{
+	"ada", "adalah", "adanya", "agak", "agar", "akan", "akhirnya", "aku", "amat", "anda", "antara", "apa",
+	"apabila", "apakah", "atas", "atau", "bagai", "bagaimana", "bagi", "bahkan", "bahwa", "baik", "banyak",
+	"baru", "beberapa", "begitu", "belum", "benar", "berapa", "berbagai", "berikut", "bersama", "besar",
+	"biasa", "bila", "bisa", "boleh", "bukan", "cara", "cukup", "dalam", "dan", "dapat", "dari", "daripada",
+	"demikian", "dengan", "di", "dia", "diri", "dua", "harus", "hal", "hanya", "hingga", "ia", "ialah",
+	"ini", "itu", "jadi", "jika", "juga", "jumlah", "kali", "kalian", "kami", "kamu", "kan", "karena",
+	"kata", "ke", "kembali", "kemudian", "kepada", "ketika", "kini", "kita", "lagi", "lain", "lalu",
+	"lebih", "maka", "masih", "mau", "melalui", "memang", "membuat", "menjadi", "menurut", "mereka",
+	"merupakan", "misalnya", "mungkin", "namun", "nya", "oleh", "pada", "paling", "para", "pasti", "per",
+	"perlu", "pula", "pun", "saat", "saja", "salah", "sama", "sampai", "sangat", "satu", "saya", "se",
+	"sebagai", "sebelum", "sebuah", "secara", "sedang", "sehingga", "sejak", "sekarang", "sekitar",
+	"selain", "selalu", "semua", "sendiri", "seperti", "serta", "setelah", "setiap", "suatu", "sudah",
+	"supaya", "tahun", "tak", "tanpa", "tapi", "telah", "tentang", "terhadap", "termasuk", "tersebut",
+	"tetapi", "tidak", "tiga", "untuk", "waktu", "yaitu", "yakni", "yang",
+}
+
+var englishStopWords = []string{
+	"a", "about", "above", "after", "again", "against", "all", "also", "am", "an", "and", "any", "are",
+	"as", "at", "be", "because", "been", "before", "being", "below", "between", "both", "but", "by", "can",
+	"could", "did", "do", "does", "doing", "down", "during", "each", "even", "few", "for", "from", "further",
+	"get", "gets", "got", "had", "has", "have", "having", "he", "her", "here", "hers", "him", "his", "how",
+	"however", "if", "in", "into", "is", "it", "its", "itself", "just", "let", "like", "make", "many", "may",
+	"me", "might", "more", "most", "much", "must", "my", "new", "no", "nor", "not", "now", "of", "off", "on",
+	"once", "one", "only", "or", "other", "our", "ours", "out", "over", "own", "same", "say", "says", "she",
+	"should", "so", "some", "such", "than", "that", "the", "their", "theirs", "them", "then", "there",
+	"these", "they", "this", "those", "through", "to", "too", "two", "under", "until", "up", "us", "use",
+	"used", "using", "very", "was", "way", "we", "well", "were", "what", "when", "where", "which", "while",
+	"who", "whom", "why", "will", "with", "would", "you", "your", "yours",
+}
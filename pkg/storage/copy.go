@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"mime"
+	"path/filepath"
+)
+
+// CopyResult counts the files handled by Copy
+type CopyResult struct {
+	Copied  int
+	Skipped int
+}
+
+// Copy puts every file of src into dst, files dst already has are skipped unless overwrite is set
+func Copy(ctx context.Context, src, dst Driver, overwrite bool) (CopyResult, error) {
+	var res CopyResult
+
+	files, err := src.List(ctx)
+	if err != nil {
+		return res, err
+	}
+	for _, file := range files {
+		if !overwrite {
+			exists, err := dst.Exists(ctx, file.Key)
+			if err != nil {
+				return res, err
+			}
+			if exists {
+				res.Skipped++
+				continue
+			}
+		}
+
+		r, err := src.Open(ctx, file.Key)
+		if err != nil {
+			return res, err
+		}
+		err = dst.Put(ctx, file.Key, r, file.Size, mime.TypeByExtension(filepath.Ext(file.Key)))
+		r.Close()
+		if err != nil {
+			return res, err
+		}
+		res.Copied++
+	}
+	return res, nil
+}
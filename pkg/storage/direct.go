@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// DirectUploader is implemented by drivers clients can upload to without going through the API
+type DirectUploader interface {
+	// PresignUpload returns a form the client posts a file to, accepted only within the policy
+	PresignUpload(ctx context.Context, key string, policy UploadPolicy) (DirectUpload, error)
+	// Stat describes a stored file without reading it, ErrNotFound when it is missing
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+}
+
+// UploadPolicy limits what a presigned upload accepts
+type UploadPolicy struct {
+	ContentType string
+	MaxSize     int64
+	Expiry      time.Duration
+	// Metadata is stored with the file and has to be sent unchanged, keys are lower case
+	Metadata map[string]string
+	// Temporary tags the file like MarkTemporary does
+	Temporary bool
+}
+
+// DirectUpload is a multipart form to POST to URL, Fields go before the file field
+type DirectUpload struct {
+	URL       string
+	Fields    map[string]string
+	ExpiresAt time.Time
+}
+
+type ObjectInfo struct {
+	FileInfo
+	ContentType string
+	Metadata    map[string]string // keys are lower case
+}
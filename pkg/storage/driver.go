@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sora_landing_be/pkg/config"
+	"sync"
+	"time"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// ErrNotFound is returned for keys that are not stored
+var ErrNotFound = errors.New("file not found")
+
+// TemporaryTag is the object tag of files not yet used by any content
+const TemporaryTag = "temporary"
+
+// Driver keeps uploaded files under flat keys, on local disk or in an S3 compatible bucket
+type Driver interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+	List(ctx context.Context) ([]FileInfo, error)
+	// MarkTemporary flags a file as not yet used by any content, or clears the flag. The
+	// database decides what is swept, the flag lets the storage expire files on its own too.
+	MarkTemporary(ctx context.Context, key string, temporary bool) error
+	// URL is the public address of a file
+	URL(key string) string
+	// PresignURL is a temporary address of a file that works without the file being public
+	PresignURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+type FileInfo struct {
+	Key  string
+	Size int64
+}
+
+var (
+	driverOnce = &sync.Once{}
+	driver     Driver
+)
+
+// InitDriver sets up the driver selected by storage.driver
+func InitDriver(cfg config.Config) {
+	driverOnce.Do(func() {
+		switch cfg.Storage.Driver {
+		case DriverS3:
+			driver = NewS3Driver(cfg.Storage, cfg.ObjectStorage)
+		default:
+			driver = NewLocalDriver(cfg.Storage, cfg.Application.BaseURL)
+		}
+	})
+}
+
+// GetDriver returns the configured driver, setting it up on first use
+func GetDriver() Driver {
+	InitDriver(config.LoadConfig())
+	return driver
+}
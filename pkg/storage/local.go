@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sora_landing_be/pkg/config"
+	"strings"
+	"time"
+)
+
+// LocalDriver stores files in a directory served by this API under /uploads
+type LocalDriver struct {
+	dir       string
+	publicURL string
+}
+
+func NewLocalDriver(cfg config.Storage, baseURL string) *LocalDriver {
+	publicURL := cfg.PublicURL
+	if publicURL == "" {
+		publicURL = strings.TrimSuffix(baseURL, "/") + "/" + LocalUploadDir
+	}
+	dir := cfg.LocalDir
+	if dir == "" {
+		dir = LocalUploadDir
+	}
+	return &LocalDriver{
+		dir:       dir,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// Dir is the directory the files are stored in
+func (d *LocalDriver) Dir() string {
+	return d.dir
+}
+
+// Put writes the file under a temporary name first, readers never see a partial file
+func (d *LocalDriver) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(d.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0o644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), d.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+func (d *LocalDriver) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(d.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (d *LocalDriver) Delete(_ context.Context, key string) error {
+	err := os.Remove(d.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (d *LocalDriver) Exists(_ context.Context, key string) (bool, error) {
+	_, err := os.Stat(d.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// MarkTemporary does nothing, local files are swept by their database record alone
+func (d *LocalDriver) MarkTemporary(_ context.Context, _ string, _ bool) error {
+	return nil
+}
+
+// List returns the stored files, hidden files such as partial writes are left out
+func (d *LocalDriver) List(_ context.Context) ([]FileInfo, error) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var res []FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, FileInfo{Key: entry.Name(), Size: info.Size()})
+	}
+	return res, nil
+}
+
+func (d *LocalDriver) URL(key string) string {
+	return d.publicURL + "/" + key
+}
+
+// PresignURL returns the public URL, every local file is served without authentication
+func (d *LocalDriver) PresignURL(_ context.Context, key string, _ time.Duration) (string, error) {
+	return d.URL(key), nil
+}
+
+// path keeps keys inside the directory, a key is a file name and never a path
+func (d *LocalDriver) path(key string) string {
+	return filepath.Join(d.dir, filepath.Base(key))
+}
@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sora_landing_be/pkg/config"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+// S3Driver stores files in an S3 compatible bucket, such as MinIO
+type S3Driver struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string
+}
+
+func NewS3Driver(cfg config.Storage, objCfg config.ObjectStorage) *S3Driver {
+	InitMinioStorage(objCfg)
+
+	publicURL := cfg.PublicURL
+	if publicURL == "" {
+		publicURL = MinioClient.client.EndpointURL().String() + "/" + objCfg.Bucket
+	}
+	return &S3Driver{
+		client:    MinioClient.client,
+		bucket:    objCfg.Bucket,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+func (d *S3Driver) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := d.client.PutObject(ctx, d.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	return err
+}
+
+func (d *S3Driver) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	// GetObject is lazy, stat first so a missing key is reported here
+	if _, err := d.client.StatObject(ctx, d.bucket, key, minio.StatObjectOptions{}); err != nil {
+		return nil, s3Error(err)
+	}
+	return d.client.GetObject(ctx, d.bucket, key, minio.GetObjectOptions{})
+}
+
+// Delete reports missing keys like the local driver, S3 itself accepts deleting them
+func (d *S3Driver) Delete(ctx context.Context, key string) error {
+	if _, err := d.client.StatObject(ctx, d.bucket, key, minio.StatObjectOptions{}); err != nil {
+		return s3Error(err)
+	}
+	return d.client.RemoveObject(ctx, d.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (d *S3Driver) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := d.client.StatObject(ctx, d.bucket, key, minio.StatObjectOptions{})
+	if err == nil {
+		return true, nil
+	}
+	if err = s3Error(err); err == ErrNotFound {
+		return false, nil
+	}
+	return false, err
+}
+
+func (d *S3Driver) List(ctx context.Context) ([]FileInfo, error) {
+	var res []FileInfo
+	for object := range d.client.ListObjects(ctx, d.bucket, minio.ListObjectsOptions{}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		// hidden keys are chunks of unfinished uploads, like partial writes of the local driver
+		if strings.HasPrefix(object.Key, ".") {
+			continue
+		}
+		res = append(res, FileInfo{Key: object.Key, Size: object.Size})
+	}
+	return res, nil
+}
+
+func (d *S3Driver) MarkTemporary(ctx context.Context, key string, temporary bool) error {
+	if !temporary {
+		return s3Error(d.client.RemoveObjectTagging(ctx, d.bucket, key, minio.RemoveObjectTaggingOptions{}))
+	}
+	objectTags, err := tags.NewTags(map[string]string{TemporaryTag: "true"}, true)
+	if err != nil {
+		return err
+	}
+	return s3Error(d.client.PutObjectTagging(ctx, d.bucket, key, objectTags, minio.PutObjectTaggingOptions{}))
+}
+
+func (d *S3Driver) PresignUpload(ctx context.Context, key string, policy UploadPolicy) (DirectUpload, error) {
+	expiresAt := time.Now().Add(policy.Expiry)
+	p := minio.NewPostPolicy()
+	err := errors.Join(
+		p.SetBucket(d.bucket),
+		p.SetKey(key),
+		p.SetExpires(expiresAt),
+		p.SetContentType(policy.ContentType),
+		p.SetContentLengthRange(1, policy.MaxSize),
+	)
+	for name, value := range policy.Metadata {
+		err = errors.Join(err, p.SetUserMetadata(name, value))
+	}
+	if policy.Temporary {
+		// the form takes the tags as the XML document of the tagging API
+		objectTags, tagErr := tags.NewTags(map[string]string{TemporaryTag: "true"}, true)
+		if tagErr == nil {
+			var tagging []byte
+			if tagging, tagErr = xml.Marshal(objectTags); tagErr == nil {
+				tagErr = p.SetTagging(string(tagging))
+			}
+		}
+		err = errors.Join(err, tagErr)
+	}
+	if err != nil {
+		return DirectUpload{}, fmt.Errorf("upload policy for %s: %w", key, err)
+	}
+
+	u, fields, err := d.client.PresignedPostPolicy(ctx, p)
+	if err != nil {
+		return DirectUpload{}, fmt.Errorf("presign upload of %s: %w", key, err)
+	}
+	return DirectUpload{URL: u.String(), Fields: fields, ExpiresAt: expiresAt}, nil
+}
+
+func (d *S3Driver) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	object, err := d.client.StatObject(ctx, d.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, s3Error(err)
+	}
+	metadata := make(map[string]string, len(object.UserMetadata))
+	for name, value := range object.UserMetadata {
+		metadata[strings.ToLower(name)] = value
+	}
+	return ObjectInfo{
+		FileInfo:    FileInfo{Key: object.Key, Size: object.Size},
+		ContentType: object.ContentType,
+		Metadata:    metadata,
+	}, nil
+}
+
+func (d *S3Driver) URL(key string) string {
+	return d.publicURL + "/" + url.PathEscape(key)
+}
+
+func (d *S3Driver) PresignURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	presigned, err := d.client.PresignedGetObject(ctx, d.bucket, key, expiry, nil)
+	if err != nil {
+		return "", fmt.Errorf("presign %s: %w", key, err)
+	}
+	return presigned.String(), nil
+}
+
+func s3Error(err error) error {
+	if code := minio.ToErrorResponse(err).Code; code == "NoSuchKey" || code == "NotFound" {
+		return ErrNotFound
+	}
+	return err
+}
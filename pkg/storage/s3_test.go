@@ -0,0 +1,286 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/segmentio/ksuid"
+)
+
+// The integration tests run against a MinIO stand-in for the bucket, such as the minio service
+// of docker-compose (make test-s3), and are skipped unless S3_TEST_ENDPOINT points at it
+
+func testS3Client(t *testing.T, endpoint string) *minio.Client {
+	t.Helper()
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds: credentials.NewStaticV4(
+			envOr("S3_TEST_ACCESS_KEY", "minioadmin"),
+			envOr("S3_TEST_SECRET_KEY", "minioadmin"),
+			"",
+		),
+		// a known region keeps presigning from asking the server for the bucket location
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// newTestS3Driver returns a driver on a fresh bucket of the stand-in, removed after the test
+func newTestS3Driver(t *testing.T) *S3Driver {
+	t.Helper()
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT is not set")
+	}
+	client := testS3Client(t, endpoint)
+	ctx := context.Background()
+	bucket := "storage-test-" + strings.ToLower(ksuid.New().String())
+	if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{Region: "us-east-1"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		for object := range client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Recursive: true}) {
+			if object.Err == nil {
+				client.RemoveObject(ctx, bucket, object.Key, minio.RemoveObjectOptions{})
+			}
+		}
+		if err := client.RemoveBucket(ctx, bucket); err != nil {
+			t.Logf("remove bucket %s: %v", bucket, err)
+		}
+	})
+	return &S3Driver{client: client, bucket: bucket, publicURL: client.EndpointURL().String() + "/" + bucket}
+}
+
+// postUpload sends a file through a presigned form the way a browser would
+func postUpload(t *testing.T, upload DirectUpload, contentType string, content []byte) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range upload.Fields {
+		if name != "Content-Type" {
+			form.WriteField(name, value)
+		}
+	}
+	// sent as given so the bucket gets to reject a type the policy does not allow
+	form.WriteField("Content-Type", contentType)
+	part, err := form.CreateFormFile("file", "upload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	form.Close()
+
+	res, err := http.Post(upload.URL, form.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { res.Body.Close() })
+	return res
+}
+
+func TestS3PresignUploadPolicy(t *testing.T) {
+	d := &S3Driver{client: testS3Client(t, "localhost:9000"), bucket: "uploads"}
+	policy := UploadPolicy{
+		ContentType: "image/png",
+		MaxSize:     10 << 20,
+		Expiry:      15 * time.Minute,
+		Metadata:    map[string]string{"uploaded-by": "user1", "module": "blog"},
+		Temporary:   true,
+	}
+	upload, err := d.PresignUpload(context.Background(), "photo_1.png", policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasSuffix(upload.URL, "/uploads/") {
+		t.Errorf("url %s does not post to the bucket", upload.URL)
+	}
+	if until := time.Until(upload.ExpiresAt); until <= 14*time.Minute || until > policy.Expiry {
+		t.Errorf("expires in %s, want %s", until, policy.Expiry)
+	}
+	for name, want := range map[string]string{
+		"key":                    "photo_1.png",
+		"Content-Type":           "image/png",
+		"x-amz-meta-uploaded-by": "user1",
+		"x-amz-meta-module":      "blog",
+	} {
+		if got := upload.Fields[name]; got != want {
+			t.Errorf("field %s = %q, want %q", name, got, want)
+		}
+	}
+	if !strings.Contains(upload.Fields["tagging"], "<Key>"+TemporaryTag+"</Key><Value>true</Value>") {
+		t.Errorf("tagging %q does not mark the upload temporary", upload.Fields["tagging"])
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(upload.Fields["policy"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Conditions []json.RawMessage `json:"conditions"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatal(err)
+	}
+	conditions := make([]string, len(doc.Conditions))
+	for i, condition := range doc.Conditions {
+		conditions[i] = string(condition)
+	}
+	signed := strings.Join(conditions, " ")
+	for _, want := range []string{
+		`["eq","$bucket","uploads"]`,
+		`["eq","$key","photo_1.png"]`,
+		`["eq","$Content-Type","image/png"]`,
+		`["content-length-range", 1, 10485760]`,
+		`["eq","$x-amz-meta-uploaded-by","user1"]`,
+		`["eq","$tagging",`,
+	} {
+		if !strings.Contains(signed, want) {
+			t.Errorf("policy %s\nmissing %s", signed, want)
+		}
+	}
+}
+
+func TestS3DriverRoundTrip(t *testing.T) {
+	d := newTestS3Driver(t)
+	ctx := context.Background()
+
+	content := []byte("hello")
+	if err := d.Put(ctx, "a.txt", bytes.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Put(ctx, ".tus-part", bytes.NewReader(content), int64(len(content)), "application/octet-stream"); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := d.Open(ctx, "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(src)
+	src.Close()
+	if !bytes.Equal(got, content) {
+		t.Errorf("read %q, want %q", got, content)
+	}
+
+	files, err := d.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Key != "a.txt" || files[0].Size != int64(len(content)) {
+		t.Errorf("listed %+v, want only a.txt", files)
+	}
+
+	if err := d.MarkTemporary(ctx, "a.txt", true); err != nil {
+		t.Fatal(err)
+	}
+	objectTags, err := d.client.GetObjectTagging(ctx, d.bucket, "a.txt", minio.GetObjectTaggingOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objectTags.ToMap()[TemporaryTag] != "true" {
+		t.Errorf("tags %v, want %s", objectTags.ToMap(), TemporaryTag)
+	}
+	if err := d.MarkTemporary(ctx, "a.txt", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.Delete(ctx, "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if exists, err := d.Exists(ctx, "a.txt"); err != nil || exists {
+		t.Errorf("exists after delete = %v, %v", exists, err)
+	}
+	if err := d.Delete(ctx, "a.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleting a missing key = %v, want ErrNotFound", err)
+	}
+	if _, err := d.Open(ctx, "a.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("opening a missing key = %v, want ErrNotFound", err)
+	}
+	if _, err := d.Stat(ctx, "a.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("stat of a missing key = %v, want ErrNotFound", err)
+	}
+}
+
+func TestS3DirectUpload(t *testing.T) {
+	d := newTestS3Driver(t)
+	ctx := context.Background()
+	policy := UploadPolicy{
+		ContentType: "image/png",
+		MaxSize:     16,
+		Expiry:      time.Minute,
+		Metadata:    map[string]string{"uploaded-by": "user1"},
+		Temporary:   true,
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		content     []byte
+		accepted    bool
+	}{
+		{name: "within the policy", contentType: "image/png", content: []byte("png content"), accepted: true},
+		{name: "larger than allowed", contentType: "image/png", content: bytes.Repeat([]byte("x"), 17)},
+		{name: "another content type", contentType: "text/html", content: []byte("<html>")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := strings.ReplaceAll(tt.name, " ", "-") + ".png"
+			upload, err := d.PresignUpload(ctx, key, policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res := postUpload(t, upload, tt.contentType, tt.content)
+			accepted := res.StatusCode < 300
+			if accepted != tt.accepted {
+				body, _ := io.ReadAll(res.Body)
+				t.Fatalf("status %d, accepted %v, want %v: %s", res.StatusCode, accepted, tt.accepted, body)
+			}
+
+			info, err := d.Stat(ctx, key)
+			if !tt.accepted {
+				if !errors.Is(err, ErrNotFound) {
+					t.Errorf("rejected upload was stored: %+v, %v", info, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Size != int64(len(tt.content)) || info.ContentType != "image/png" {
+				t.Errorf("stored %+v", info)
+			}
+			if info.Metadata["uploaded-by"] != "user1" {
+				t.Errorf("metadata %v, want uploaded-by", info.Metadata)
+			}
+			objectTags, err := d.client.GetObjectTagging(ctx, d.bucket, key, minio.GetObjectTaggingOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if objectTags.ToMap()[TemporaryTag] != "true" {
+				t.Errorf("tags %v, want %s", objectTags.ToMap(), TemporaryTag)
+			}
+		})
+	}
+}
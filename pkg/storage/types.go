@@ -1,3 +1,6 @@
 package storage
 
 var DocumentFileType = []string{".jpg", ".jpeg", ".jfif", ".pjpeg", ".pjp", ".png", ".svg", ".webp"}
+
+// LocalUploadDir is the directory served under /uploads for locally stored files
+const LocalUploadDir = "uploads"
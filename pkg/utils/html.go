@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// WalkHTML visits node and all of its descendants depth-first
+func WalkHTML(node *html.Node, fn func(n *html.Node)) {
+	if node == nil {
+		return
+	}
+	fn(node)
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		WalkHTML(child, fn)
+	}
+}
+
+// GetAttr returns the value of an attribute, matched case-insensitively
+func GetAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if strings.EqualFold(attr.Key, key) {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// SetAttr sets or adds an attribute on an element node
+func SetAttr(n *html.Node, key, value string) {
+	for i, attr := range n.Attr {
+		if strings.EqualFold(attr.Key, key) {
+			n.Attr[i].Val = value
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: value})
+}
+
+// RemoveAttr drops an attribute from an element node
+func RemoveAttr(n *html.Node, key string) {
+	attrs := n.Attr[:0]
+	for _, attr := range n.Attr {
+		if !strings.EqualFold(attr.Key, key) {
+			attrs = append(attrs, attr)
+		}
+	}
+	n.Attr = attrs
+}
+
+// ParseHTMLFragment parses an HTML snippet such as article content
+func ParseHTMLFragment(content string) ([]*html.Node, error) {
+	return html.ParseFragment(strings.NewReader(content), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+}
+
+// RewriteHTMLFragment parses content, lets fn modify every node and renders the result back
+func RewriteHTMLFragment(content string, fn func(n *html.Node)) (string, error) {
+	nodes, err := ParseHTMLFragment(content)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	for _, node := range nodes {
+		WalkHTML(node, fn)
+		if err := html.Render(&buf, node); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+// HTMLToText returns the visible text of an HTML snippet with whitespace collapsed
+func HTMLToText(content string) string {
+	nodes, err := ParseHTMLFragment(content)
+	if err != nil {
+		return ""
+	}
+
+	var buf strings.Builder
+	for _, node := range nodes {
+		WalkHTML(node, func(n *html.Node) {
+			if n.Type == html.TextNode && (n.Parent == nil || (n.Parent.DataAtom != atom.Script && n.Parent.DataAtom != atom.Style)) {
+				buf.WriteString(n.Data)
+				buf.WriteByte(' ')
+			}
+		})
+	}
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+// TruncateText shortens text to at most max runes, cutting at a word boundary
+// when possible and marking the cut with an ellipsis
+func TruncateText(text string, max int) string {
+	text = strings.TrimSpace(text)
+	if max <= 0 || utf8.RuneCountInString(text) <= max {
+		return text
+	}
+
+	runes := []rune(text)
+	cut := string(runes[:max-1])
+	if i := strings.LastIndexByte(cut, ' '); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:-") + "…"
+}
+
+// excerptMinParagraph is the length below which a paragraph is taken for a caption or a
+// lead-in such as "Update:" rather than the start of the article
+const excerptMinParagraph = 40
+
+// GenerateExcerpt summarises HTML content with its first meaningful paragraph, cut at a
+// sentence boundary so the excerpt reads as prose
+func GenerateExcerpt(content string, max int) string {
+	nodes, err := ParseHTMLFragment(content)
+	if err != nil {
+		return ""
+	}
+
+	var paragraphs []string
+	for _, node := range nodes {
+		WalkHTML(node, func(n *html.Node) {
+			if n.Type != html.ElementNode || n.DataAtom != atom.P {
+				return
+			}
+			var buf bytes.Buffer
+			if err := html.Render(&buf, n); err == nil {
+				if text := HTMLToText(buf.String()); text != "" {
+					paragraphs = append(paragraphs, text)
+				}
+			}
+		})
+	}
+	// content written without paragraphs is one block of text
+	if len(paragraphs) == 0 {
+		paragraphs = append(paragraphs, HTMLToText(content))
+	}
+
+	excerpt := paragraphs[0]
+	for _, p := range paragraphs {
+		if utf8.RuneCountInString(p) >= excerptMinParagraph {
+			excerpt = p
+			break
+		}
+	}
+	return TruncateSentences(excerpt, max)
+}
+
+// TruncateSentences shortens text to at most max runes, keeping whole sentences when the
+// first one fits and falling back to TruncateText otherwise
+func TruncateSentences(text string, max int) string {
+	text = strings.TrimSpace(text)
+	if max <= 0 || utf8.RuneCountInString(text) <= max {
+		return text
+	}
+
+	runes := []rune(text)
+	end := -1
+	for i := 0; i < max; i++ {
+		switch runes[i] {
+		case '.', '!', '?', '。':
+			if i+1 == len(runes) || unicode.IsSpace(runes[i+1]) {
+				end = i + 1
+			}
+		}
+	}
+	if end <= 0 {
+		return TruncateText(text, max)
+	}
+	return string(runes[:end])
+}
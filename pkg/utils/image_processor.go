@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/chai2010/webp"
 	"github.com/disintegration/imaging"
 )
 
@@ -20,6 +21,13 @@ const (
 	Quality   = 85   // JPEG quality (1-100)
 )
 
+// imageFormatExt is the file extension written for each format EncodeImage supports
+var imageFormatExt = map[string]string{
+	"jpeg": ".jpg",
+	"png":  ".png",
+	"webp": ".webp",
+}
+
 // ProcessImage optimizes the image while maintaining quality
 func ProcessImage(file *multipart.FileHeader) ([]byte, error) {
 	src, err := file.Open()
@@ -135,3 +143,37 @@ func SaveOptimizedImage(src io.Reader, dst io.Writer, format string) error {
 
 	return err
 }
+
+// IsResizableFormat reports whether variants can be generated for images of the format,
+// animated formats such as GIF would lose their frames
+func IsResizableFormat(format string) bool {
+	_, ok := imageFormatExt[format]
+	return ok
+}
+
+// ImageFormatExt returns the file extension of an image format, with the dot
+func ImageFormatExt(format string) string {
+	return imageFormatExt[format]
+}
+
+// ResizeToWidth scales an image down to the given width, keeping its aspect ratio
+func ResizeToWidth(img image.Image, width int) image.Image {
+	if width <= 0 || width >= img.Bounds().Dx() {
+		return img
+	}
+	return imaging.Resize(img, width, 0, imaging.Lanczos)
+}
+
+// EncodeImage writes an image in the given format, quality applies to JPEG and WebP (1-100)
+func EncodeImage(dst io.Writer, img image.Image, format string, quality int) error {
+	switch format {
+	case "jpeg":
+		return jpeg.Encode(dst, img, &jpeg.Options{Quality: quality})
+	case "png":
+		return png.Encode(dst, img)
+	case "webp":
+		return webp.Encode(dst, img, &webp.Options{Quality: float32(quality)})
+	default:
+		return fmt.Errorf("unsupported image format: %s", format)
+	}
+}
@@ -12,6 +12,13 @@ type SlugChecker interface {
 	SlugExists(ctx context.Context, slug string) (bool, error)
 }
 
+// SlugCheckerFunc adapts a plain function to a SlugChecker
+type SlugCheckerFunc func(ctx context.Context, slug string) (bool, error)
+
+func (f SlugCheckerFunc) SlugExists(ctx context.Context, slug string) (bool, error) {
+	return f(ctx, slug)
+}
+
 // Slugify creates a URL-safe slug from a string
 func Slugify(s string) string {
 	// lowercase
@@ -0,0 +1,499 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"io"
+	"strings"
+)
+
+// uploadFormatExt maps the extensions uploads may have to the format their content must be
+var uploadFormatExt = map[string]string{
+	".jpg":   "jpeg",
+	".jpeg":  "jpeg",
+	".jfif":  "jpeg",
+	".pjpeg": "jpeg",
+	".pjp":   "jpeg",
+	".png":   "png",
+	".gif":   "gif",
+	".webp":  "webp",
+	".svg":   "svg",
+	".pdf":   "pdf",
+	".mp4":   "mp4",
+	".m4v":   "mp4",
+	".mov":   "mov",
+	".webm":  "webm",
+}
+
+// cleanedFormats are checked as a whole and rewritten, other formats are
+// too large for that and only have their start checked
+var cleanedFormats = map[string]bool{
+	"jpeg": true, "png": true, "gif": true, "webp": true, "svg": true,
+}
+
+// markupSniffLen is how much of a file browsers look at when guessing its type
+const markupSniffLen = 1024
+
+// markupSignatures make a browser that sniffs the content treat a file as a page
+var markupSignatures = [][]byte{
+	[]byte("<!doctype"), []byte("<html"), []byte("<head"), []byte("<body"), []byte("<script"),
+	[]byte("<iframe"), []byte("<svg"), []byte("<?php"), []byte("<?xml"),
+}
+
+// UploadFormat returns the format the content of a file with the given extension must be, empty when not allowed
+func UploadFormat(ext string) string {
+	return uploadFormatExt[strings.ToLower(ext)]
+}
+
+// IsCleanedFormat reports whether uploads with the extension are checked whole by CleanUpload,
+// the others only need their first UploadHeadLen bytes checked by CheckUploadHead
+func IsCleanedFormat(ext string) bool {
+	return cleanedFormats[UploadFormat(ext)]
+}
+
+// UploadHeadLen is how much of a file CheckUploadHead looks at
+const UploadHeadLen = markupSniffLen
+
+// SniffFormat detects the format of a file from its magic bytes, empty when unknown
+func SniffFormat(content []byte) string {
+	switch {
+	case bytes.HasPrefix(content, []byte{0xFF, 0xD8, 0xFF}):
+		return "jpeg"
+	case bytes.HasPrefix(content, []byte("\x89PNG\r\n\x1a\n")):
+		return "png"
+	case bytes.HasPrefix(content, []byte("GIF87a")), bytes.HasPrefix(content, []byte("GIF89a")):
+		return "gif"
+	case len(content) >= 12 && bytes.Equal(content[:4], []byte("RIFF")) && bytes.Equal(content[8:12], []byte("WEBP")):
+		return "webp"
+	case bytes.HasPrefix(content, []byte("%PDF-")):
+		return "pdf"
+	case len(content) >= 12 && bytes.Equal(content[4:8], []byte("ftyp")):
+		if bytes.Equal(content[8:12], []byte("qt  ")) {
+			return "mov"
+		}
+		return "mp4"
+	case bytes.HasPrefix(content, []byte{0x1A, 0x45, 0xDF, 0xA3}): // EBML, WebM is Matroska
+		return "webm"
+	case isSVG(content):
+		return "svg"
+	}
+	return ""
+}
+
+// CheckUploadHead checks that a file starts like the format its extension claims and
+// without markup a browser could sniff
+func CheckUploadHead(ext string, head []byte) error {
+	format, err := checkFormat(ext, head)
+	if err != nil || format == "svg" {
+		return err
+	}
+	return checkMarkup(head)
+}
+
+// checkFormat returns the format of content, which must be the one its extension claims
+func checkFormat(ext string, content []byte) (string, error) {
+	format := UploadFormat(ext)
+	if format == "" {
+		return "", fmt.Errorf("files with extension %q are not allowed", ext)
+	}
+	if sniffed := SniffFormat(content); sniffed != format {
+		return "", fmt.Errorf("content is %s, not %s", describeFormat(sniffed), format)
+	}
+	return format, nil
+}
+
+func checkMarkup(content []byte) error {
+	head := bytes.ToLower(content[:min(len(content), markupSniffLen)])
+	for _, signature := range markupSignatures {
+		if bytes.Contains(head, signature) {
+			return errors.New("file contains markup")
+		}
+	}
+	return nil
+}
+
+// CleanUpload checks that content is really of the format its extension claims and returns
+// it safe to serve. Raster images must decode, carry no markup a browser could sniff and
+// end where the format ends, so a file cannot be an image and a page or an archive at once.
+// JPEG metadata is removed except the orientation, SVGs are sanitized. Other formats
+// only have their start checked, see CheckUploadHead.
+func CleanUpload(ext string, content []byte) ([]byte, error) {
+	format, err := checkFormat(ext, content)
+	if err != nil {
+		return nil, err
+	}
+	if format == "svg" {
+		return SanitizeSVG(content)
+	}
+	if !cleanedFormats[format] {
+		return content, checkMarkup(content)
+	}
+
+	if _, decoded, err := image.Decode(bytes.NewReader(content)); err != nil || decoded != format {
+		return nil, fmt.Errorf("content is not a valid %s image", format)
+	}
+	// end is where the image ends in content, cleaned is the image without its metadata
+	var cleaned []byte
+	var end int
+	switch format {
+	case "jpeg":
+		cleaned, end, err = StripJPEGMetadata(content)
+	case "png":
+		cleaned, end, err = stripPNGMetadata(content)
+	case "gif":
+		end, err = gifEnd(content)
+	case "webp":
+		end, err = webpEnd(content)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.Trim(content[end:], "\x00")) > 0 {
+		return nil, errors.New("unexpected data after the image")
+	}
+	content = Fallback(cleaned, content[:end], cleaned != nil)
+
+	// metadata is gone, what is left at the start must be image data
+	if err := checkMarkup(content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func describeFormat(format string) string {
+	if format == "" {
+		return "not a supported format"
+	}
+	return format
+}
+
+// StripJPEGMetadata removes the EXIF, XMP, IPTC and comment segments of a JPEG, which carry
+// camera details and GPS positions, keeping only the orientation so photos stay upright.
+// It returns the cleaned image and where the image ends in content.
+func StripJPEGMetadata(content []byte) ([]byte, int, error) {
+	errInvalid := errors.New("content is not a valid jpeg image")
+	out := bytes.NewBuffer(make([]byte, 0, len(content)))
+	out.Write(content[:2]) // SOI
+
+	orientation := uint16(0)
+	i := 2
+	for {
+		if i+2 > len(content) || content[i] != 0xFF {
+			return nil, 0, errInvalid
+		}
+		marker := content[i+1]
+		if marker == 0xFF { // fill byte
+			i++
+			continue
+		}
+		if marker == 0xD9 { // EOI
+			out.Write(content[i : i+2])
+			return withOrientation(out.Bytes(), orientation), i + 2, nil
+		}
+		if i+4 > len(content) {
+			return nil, 0, errInvalid
+		}
+		length := int(binary.BigEndian.Uint16(content[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(content) {
+			return nil, 0, errInvalid
+		}
+		segment := content[i:end]
+
+		switch {
+		case marker == 0xE1: // EXIF or XMP
+			if o := jpegOrientation(segment[4:]); o > 1 {
+				orientation = o
+			}
+		case marker == 0xED, marker == 0xFE: // IPTC, comment
+		case marker == 0xDA: // SOS, the entropy coded data runs to the next marker
+			out.Write(segment)
+			j := end
+			for j+1 < len(content) && (content[j] != 0xFF || content[j+1] == 0x00 || (content[j+1] >= 0xD0 && content[j+1] <= 0xD7)) {
+				j++
+			}
+			if j+1 >= len(content) {
+				return nil, 0, errInvalid
+			}
+			out.Write(content[end:j])
+			end = j
+		default:
+			out.Write(segment)
+		}
+		i = end
+	}
+}
+
+// jpegOrientation reads the orientation tag of an EXIF segment, 0 when it has none
+func jpegOrientation(exif []byte) uint16 {
+	if !bytes.HasPrefix(exif, []byte("Exif\x00\x00")) || len(exif) < 14 {
+		return 0
+	}
+	tiff := exif[6:]
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 0
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < entries; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return order.Uint16(tiff[entry+8:])
+		}
+	}
+	return 0
+}
+
+// withOrientation puts an EXIF segment with the orientation at the start of a JPEG,
+// after the JFIF segment when there is one, where readers look for it
+func withOrientation(jpeg []byte, orientation uint16) []byte {
+	if orientation <= 1 {
+		return jpeg
+	}
+	at := 2
+	if len(jpeg) > 6 && jpeg[2] == 0xFF && jpeg[3] == 0xE0 {
+		at = 4 + int(binary.BigEndian.Uint16(jpeg[4:]))
+	}
+	segment := orientationSegment(orientation)
+	res := make([]byte, 0, len(jpeg)+len(segment))
+	res = append(res, jpeg[:at]...)
+	res = append(res, segment...)
+	return append(res, jpeg[at:]...)
+}
+
+// orientationSegment is an EXIF segment holding nothing but the orientation
+func orientationSegment(orientation uint16) []byte {
+	tiff := []byte{
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08, // big endian header, IFD0 at offset 8
+		0x00, 0x01, // one entry
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, // orientation, SHORT, count 1
+		byte(orientation >> 8), byte(orientation), 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, // no next IFD
+	}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0x00, 0x00}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// stripPNGMetadata removes the text and EXIF chunks of a PNG and returns where the image ends in content
+func stripPNGMetadata(content []byte) ([]byte, int, error) {
+	out := bytes.NewBuffer(make([]byte, 0, len(content)))
+	out.Write(content[:8])
+	for i := 8; i+12 <= len(content); {
+		length := int(binary.BigEndian.Uint32(content[i:]))
+		end := i + 12 + length
+		if length < 0 || end > len(content) {
+			break
+		}
+		switch kind := string(content[i+4 : i+8]); kind {
+		case "tEXt", "zTXt", "iTXt", "eXIf":
+		default:
+			out.Write(content[i:end])
+			if kind == "IEND" {
+				return out.Bytes(), end, nil
+			}
+		}
+		i = end
+	}
+	return nil, 0, errors.New("content is not a valid png image")
+}
+
+// gifEnd walks the blocks of a GIF up to its trailer
+func gifEnd(content []byte) (int, error) {
+	errInvalid := errors.New("content is not a valid gif image")
+	colorTable := func(flags byte) int {
+		if flags&0x80 == 0 {
+			return 0
+		}
+		return 3 << (flags&0x07 + 1)
+	}
+	subBlocks := func(i int) int {
+		for i < len(content) && content[i] != 0 {
+			i += int(content[i]) + 1
+		}
+		return i + 1
+	}
+
+	if len(content) < 13 {
+		return 0, errInvalid
+	}
+	i := 13 + colorTable(content[10])
+	for i < len(content) {
+		switch content[i] {
+		case 0x3B: // trailer
+			return i + 1, nil
+		case 0x21: // extension
+			i = subBlocks(i + 2)
+		case 0x2C: // image descriptor, then the LZW minimum code size
+			if i+10 > len(content) {
+				return 0, errInvalid
+			}
+			i = subBlocks(i + 10 + colorTable(content[i+9]) + 1)
+		default:
+			return 0, errInvalid
+		}
+	}
+	return 0, errInvalid
+}
+
+// webpEnd is the end of the RIFF container of a WebP image
+func webpEnd(content []byte) (int, error) {
+	end := 8 + int(binary.LittleEndian.Uint32(content[4:]))
+	if end > len(content) || end < 12 {
+		return 0, errors.New("content is not a valid webp image")
+	}
+	return end, nil
+}
+
+func isSVG(content []byte) bool {
+	d := xml.NewDecoder(bytes.NewReader(content))
+	d.Strict = false
+	for {
+		tok, err := d.RawToken()
+		if err != nil {
+			return false
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			return t.Name.Local == "svg"
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) > 0 {
+				return false
+			}
+		}
+	}
+}
+
+// svgDroppedElements can run scripts or embed other documents
+var svgDroppedElements = map[string]bool{
+	"script": true, "foreignobject": true, "iframe": true, "object": true, "embed": true,
+	"handler": true, "listener": true,
+}
+
+// SanitizeSVG rewrites an SVG without scripts, event handlers, embedded documents and
+// references to anything outside the file. Doctypes are dropped with their entities.
+func SanitizeSVG(content []byte) ([]byte, error) {
+	d := xml.NewDecoder(bytes.NewReader(content))
+	var out bytes.Buffer
+	skip := 0 // depth inside a dropped element
+	inStyle := false
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("content is not a valid svg image: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if skip > 0 || svgDroppedElements[strings.ToLower(t.Name.Local)] {
+				skip++
+				continue
+			}
+			inStyle = strings.EqualFold(t.Name.Local, "style")
+			out.WriteString("<" + xmlName(t.Name))
+			for _, attr := range t.Attr {
+				if !safeSVGAttr(attr) {
+					continue
+				}
+				out.WriteString(" " + xmlName(attr.Name) + `="`)
+				xml.EscapeText(&out, []byte(attr.Value))
+				out.WriteString(`"`)
+			}
+			out.WriteString(">")
+		case xml.EndElement:
+			if skip > 0 {
+				skip--
+				continue
+			}
+			inStyle = false
+			out.WriteString("</" + xmlName(t.Name) + ">")
+		case xml.CharData:
+			if skip > 0 {
+				continue
+			}
+			if inStyle && !safeCSS(string(t)) {
+				continue
+			}
+			xml.EscapeText(&out, t)
+		case xml.ProcInst:
+			if t.Target == "xml" && out.Len() == 0 {
+				out.WriteString("<?xml " + string(t.Inst) + "?>")
+			}
+		}
+	}
+	if !isSVG(out.Bytes()) {
+		return nil, errors.New("content is not a valid svg image")
+	}
+	return out.Bytes(), nil
+}
+
+func xmlName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// safeSVGAttr drops event handlers and links outside the file, keeping fragments
+// and inline raster images
+func safeSVGAttr(attr xml.Attr) bool {
+	name := strings.ToLower(attr.Name.Local)
+	value := strings.ToLower(strings.Join(strings.Fields(attr.Value), ""))
+	switch {
+	case strings.HasPrefix(name, "on"):
+		return false
+	case name == "href" || name == "src" || name == "action" || name == "formaction":
+		return strings.HasPrefix(value, "#") || isInlineRaster(value)
+	case name == "style":
+		return safeCSS(value)
+	}
+	return !strings.Contains(value, "javascript:") && safeCSS(value)
+}
+
+// safeCSS rejects styles that load anything from outside the file
+func safeCSS(css string) bool {
+	css = strings.ToLower(strings.Join(strings.Fields(css), ""))
+	if strings.Contains(css, "@import") || strings.Contains(css, "expression(") || strings.Contains(css, "javascript:") {
+		return false
+	}
+	for rest := css; ; {
+		i := strings.Index(rest, "url(")
+		if i < 0 {
+			return true
+		}
+		rest = strings.TrimLeft(rest[i+4:], `'"`)
+		if !strings.HasPrefix(rest, "#") && !isInlineRaster(rest) {
+			return false
+		}
+	}
+}
+
+func isInlineRaster(value string) bool {
+	for _, prefix := range []string{"data:image/png", "data:image/jpeg", "data:image/gif", "data:image/webp"} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}